@@ -5,10 +5,12 @@ import (
 	"github.com/pkg/profile"
 	"github.com/raulferras/nes-golang/src/audio"
 	"github.com/raulferras/nes-golang/src/debugger"
+	"github.com/raulferras/nes-golang/src/frontend"
 	"github.com/raulferras/nes-golang/src/nes"
 	"github.com/raulferras/nes-golang/src/nes/gamePak"
-	"github.com/raulferras/nes-golang/src/nes/types"
-	"image"
+	"github.com/raulferras/nes-golang/src/nes/ppu"
+	"github.com/raulferras/nes-golang/src/nes/rewind"
+	"log"
 )
 
 type Options struct {
@@ -18,6 +20,12 @@ type Options struct {
 	debugPPU   bool
 	breakpoint string
 	cpuProfile bool
+	volume     int
+	mute       bool
+
+	palettePath string
+	ntsc        ppu.NTSCParameters
+	region      string
 }
 
 func NewOptions(videoScale int,
@@ -25,7 +33,12 @@ func NewOptions(videoScale int,
 	logCPU bool,
 	debugPPU bool,
 	breakpoint string,
-	cpuProfile bool) Options {
+	cpuProfile bool,
+	volume int,
+	mute bool,
+	palettePath string,
+	ntsc ppu.NTSCParameters,
+	region string) Options {
 	return Options{
 		videoScale: videoScale,
 		romPath:    romPath,
@@ -33,6 +46,12 @@ func NewOptions(videoScale int,
 		debugPPU:   debugPPU,
 		breakpoint: breakpoint,
 		cpuProfile: cpuProfile,
+		volume:     volume,
+		mute:       mute,
+
+		palettePath: palettePath,
+		ntsc:        ntsc,
+		region:      region,
 	}
 }
 
@@ -45,108 +64,96 @@ func RunEmulator(options Options) {
 	}
 	r.InitWindow(windowWidth, 700, "NES golang")
 	r.SetTraceLog(r.LogWarning)
-	r.SetTargetFPS(30)
 	font := r.LoadFont("./assets/Pixel_NES.otf")
 	r.SetTextureFilter(font.Texture, r.FilterPoint)
 
-	audioDevice := audio.NewAudio()
-	audioDevice.Init()
-	defer audioDevice.Stop()
-	audio.Generate()
-
 	nesDebugger := nes.CreateNesDebugger(
 		"./var",
 		options.logCPU,
 		options.debugPPU,
 	)
 
-	cartridge := gamePak.CreateGamePakFromROMFile(options.romPath)
+	cartridge, err := gamePak.CreateGamePakFromROMFile(options.romPath)
+	if err != nil {
+		log.Fatalf("could not load rom: %s", err)
+	}
 	console := nes.CreateNes(
 		&cartridge,
 		nesDebugger,
 	)
+	if options.region != "" {
+		region, err := nes.RegionByName(options.region)
+		if err != nil {
+			log.Fatalf("could not set region: %s", err)
+		}
+		console.SetRegion(region)
+	}
+
+	palette := ppu.GenerateNTSCPalette(options.ntsc)
+	if options.palettePath != "" {
+		palette, err = ppu.LoadPaletteFile(options.palettePath)
+		if err != nil {
+			log.Fatalf("could not load palette: %s", err)
+		}
+	}
+	console.PPU().SetPalette(palette)
+
+	audioDevice := audio.NewAudio(float32(console.APU().SampleRate()))
+	audioDevice.SetVolume(float32(options.volume) / 100)
+	audioDevice.SetMuted(options.mute)
 
 	debugger.PrintRomInfo(&cartridge)
 	if options.cpuProfile {
 		defer profile.Start(profile.CPUProfile, profile.ProfilePath(".")).Stop()
 	}
 
-	loop(console, options.videoScale, audioDevice)
+	loop(console, options.videoScale, audioDevice, &saveStates{romPath: options.romPath})
 
 	r.UnloadFont(font)
-	r.CloseAudioDevice()
 	r.CloseWindow()
 }
 
-func loop(console *nes.Nes, videoScale int, audioDevice *audio.Audio) {
+// loop runs one emulated frame per drawn frame, paced by the console frame rate
+func loop(console *nes.Nes, videoScale int, audioDevice *audio.Audio, states *saveStates) {
 	console.Start()
-	_timestamp := r.GetTime()
 	debuggerGUI := debugger.NewDebugger(console, audioDevice)
+	speakers := newSpeakers(audioDevice)
+	defer speakers.Close()
+
+	session := frontend.NewSession(
+		console,
+		&window{scale: videoScale, debugger: &debuggerGUI},
+		speakers,
+		keyboard{},
+		frontend.NewRealTimeClock(console.Region().FrameRate),
+	)
+	session.EnableRewind(rewind.DefaultOptions())
 
 	for !r.WindowShouldClose() {
 		if console.Finished() {
 			break
 		}
 
-		timestamp := r.GetTime()
-		dt := timestamp - _timestamp
-		_timestamp = timestamp
-		if dt > 1 {
-			// difference too big
-			dt = 0
+		readAudioControls(audioDevice)
+		states.readSaveStateControls(console)
+		if err := session.RunFrame(); err != nil {
+			log.Printf("could not run frame: %s", err)
 		}
-		audioDevice.Update()
-
-		// Update emulator
-		controllerState := readController()
-		console.UpdateController(1, controllerState)
-
-		if !console.Paused() {
-			//console.TickForTime(dt)
-			console.TickTillFrameComplete()
-		} else {
-			console.PausedTick()
-		}
-
-		// Draw --------------------
-
-		r.BeginDrawing()
-		r.ClearBackground(r.Black)
-		texture := drawEmulation(console.Frame(), videoScale)
-		debuggerGUI.Tick()
-		r.EndDrawing()
-		r.UnloadTexture(texture)
-		// End Draw --------------------
 	}
 
 	debuggerGUI.Close()
 	console.Stop()
 }
 
-func readController() nes.ControllerState {
-	state := nes.ControllerState{
-		A:      r.IsKeyDown(r.KeyZ),
-		B:      r.IsKeyDown(r.KeyX),
-		Select: r.IsKeyDown(r.KeyA),
-		Start:  r.IsKeyDown(r.KeyS),
-		Up:     r.IsKeyDown(r.KeyUp),
-		Down:   r.IsKeyDown(r.KeyDown),
-		Left:   r.IsKeyDown(r.KeyLeft),
-		Right:  r.IsKeyDown(r.KeyRight),
+// readAudioControls handles M to mute, and -/= to change volume
+func readAudioControls(audioDevice *audio.Audio) {
+	if r.IsKeyPressed(r.KeyM) {
+		audioDevice.SetMuted(!audioDevice.Muted())
+	}
+	if r.IsKeyPressed(r.KeyMinus) {
+		audioDevice.SetVolume(audioDevice.Volume() - 0.1)
+	}
+	if r.IsKeyPressed(r.KeyEqual) {
+		audioDevice.SetVolume(audioDevice.Volume() + 0.1)
 	}
-
-	return state
-}
-
-func drawEmulation(frame image.Image, scale int) r.Texture2D {
-	padding := int32(20)
-	paddingY := int32(20)
-	screenWidth := int32(types.SCREEN_WIDTH) * int32(scale)
-	screenHeight := int32(types.SCREEN_HEIGHT) * int32(scale)
-	r.DrawRectangle(padding-1, paddingY-1, screenWidth+2, screenHeight+2, r.RayWhite)
-
-	image := r.NewImageFromImage(frame)
-	texture := r.LoadTextureFromImage(image)
-	r.DrawTextureEx(texture, r.Vector2{X: float32(padding), Y: float32(paddingY)}, 0, float32(scale), r.White)
-	return texture
 }
@@ -0,0 +1,104 @@
+package app
+
+import (
+	r "github.com/gen2brain/raylib-go/raylib"
+	"github.com/raulferras/nes-golang/src/audio"
+	"github.com/raulferras/nes-golang/src/debugger"
+	"github.com/raulferras/nes-golang/src/frontend"
+	"github.com/raulferras/nes-golang/src/nes"
+	"github.com/raulferras/nes-golang/src/nes/types"
+	"image"
+	"log"
+)
+
+// window draws frames into the raylib window, along the debugger panels
+type window struct {
+	scale    int
+	debugger *debugger.GuiDebugger
+}
+
+func (w *window) PushFrame(frame *image.RGBA) error {
+	r.BeginDrawing()
+	r.ClearBackground(r.Black)
+	texture := drawEmulation(frame, w.scale)
+	w.debugger.Tick()
+	r.EndDrawing()
+	r.UnloadTexture(texture)
+
+	return nil
+}
+
+func drawEmulation(frame image.Image, scale int) r.Texture2D {
+	padding := int32(20)
+	paddingY := int32(20)
+	screenWidth := int32(types.SCREEN_WIDTH) * int32(scale)
+	screenHeight := int32(types.SCREEN_HEIGHT) * int32(scale)
+	r.DrawRectangle(padding-1, paddingY-1, screenWidth+2, screenHeight+2, r.RayWhite)
+
+	image := r.NewImageFromImage(frame)
+	texture := r.LoadTextureFromImage(image)
+	r.DrawTextureEx(texture, r.Vector2{X: float32(padding), Y: float32(paddingY)}, 0, float32(scale), r.White)
+	return texture
+}
+
+// speakers plays samples through a raylib audio stream, fed in chunks from audio.Audio
+type speakers struct {
+	audio  *audio.Audio
+	stream r.AudioStream
+}
+
+func newSpeakers(audioDevice *audio.Audio) *speakers {
+	log.Println("Init audio")
+	r.InitAudioDevice()
+	// Buffer size has to be set before loading the stream
+	r.SetAudioStreamBufferSizeDefault(audio.SamplesCount)
+	stream := r.LoadAudioStream(
+		uint32(audioDevice.SampleRate()),
+		32,
+		1,
+	)
+	r.PlayAudioStream(stream)
+
+	return &speakers{audio: audioDevice, stream: stream}
+}
+
+// PushSamples queues samples, and feeds the audio stream whenever it has consumed the previous chunk
+func (s *speakers) PushSamples(samples []float32) error {
+	s.audio.Push(samples)
+	for r.IsAudioStreamProcessed(s.stream) {
+		chunk := s.audio.NextChunk()
+		r.UpdateAudioStream(s.stream, chunk.Sample, chunk.SamplesCount)
+	}
+
+	return nil
+}
+
+func (s *speakers) Close() {
+	r.UnloadAudioStream(s.stream)
+	r.CloseAudioDevice()
+}
+
+// keyboard reads controller 1, and Backspace to rewind
+type keyboard struct{}
+
+func (keyboard) ReadInput(frame int) frontend.Input {
+	return frontend.Input{
+		Controller1: readController(),
+		Rewind:      r.IsKeyDown(r.KeyBackspace),
+	}
+}
+
+func readController() nes.ControllerState {
+	state := nes.ControllerState{
+		A:      r.IsKeyDown(r.KeyZ),
+		B:      r.IsKeyDown(r.KeyX),
+		Select: r.IsKeyDown(r.KeyA),
+		Start:  r.IsKeyDown(r.KeyS),
+		Up:     r.IsKeyDown(r.KeyUp),
+		Down:   r.IsKeyDown(r.KeyDown),
+		Left:   r.IsKeyDown(r.KeyLeft),
+		Right:  r.IsKeyDown(r.KeyRight),
+	}
+
+	return state
+}
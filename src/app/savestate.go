@@ -0,0 +1,83 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	r "github.com/gen2brain/raylib-go/raylib"
+	"github.com/raulferras/nes-golang/src/nes"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const saveStateSlots = 10
+
+// saveStates keeps the slot selected to save and load states of the running rom
+type saveStates struct {
+	romPath string
+	slot    int
+}
+
+// stateFilePath returns the file of a save state slot: the rom path with its extension replaced by .ss<slot>
+func stateFilePath(romPath string, slot int) string {
+	return fmt.Sprintf("%s.ss%d", strings.TrimSuffix(romPath, filepath.Ext(romPath)), slot)
+}
+
+// readSaveStateControls handles F5 to save, F8 to load, and F6/F7 to select previous/next slot
+func (states *saveStates) readSaveStateControls(console *nes.Nes) {
+	if r.IsKeyPressed(r.KeyF6) {
+		states.slot = (states.slot + saveStateSlots - 1) % saveStateSlots
+		log.Printf("save state slot %d", states.slot)
+	}
+	if r.IsKeyPressed(r.KeyF7) {
+		states.slot = (states.slot + 1) % saveStateSlots
+		log.Printf("save state slot %d", states.slot)
+	}
+	if r.IsKeyPressed(r.KeyF5) {
+		if err := states.save(console); err != nil {
+			log.Printf("could not save state: %s", err)
+		} else {
+			log.Printf("state saved into slot %d", states.slot)
+		}
+	}
+	if r.IsKeyPressed(r.KeyF8) {
+		if err := states.load(console); err != nil {
+			log.Printf("could not load state: %s", err)
+		} else {
+			log.Printf("state loaded from slot %d", states.slot)
+		}
+	}
+}
+
+// save writes a temporary file first, so a crash never leaves a half written state in the slot
+func (states *saveStates) save(console *nes.Nes) error {
+	path := stateFilePath(states.romPath, states.slot)
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	err = console.SaveState(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// load reads the whole slot file first, the console keeps running untouched when the file can not be read
+func (states *saveStates) load(console *nes.Nes) error {
+	data, err := ioutil.ReadFile(stateFilePath(states.romPath, states.slot))
+	if err != nil {
+		return err
+	}
+
+	return console.LoadState(bytes.NewReader(data))
+}
@@ -0,0 +1,92 @@
+package audio
+
+// SamplesCount is the size of each chunk sent to the audio stream
+const SamplesCount = 1024
+
+// The ring buffer holds a few chunks. Rate control steers it towards half full,
+// which keeps latency around 2 chunks while leaving room for frame time jitter.
+const ringBufferSize = SamplesCount * 4
+const targetFill = 0.5
+
+// Maximum deviation from the nominal sample rate. 0.5% is below what ears notice as a pitch change.
+const maxRateDelta = 0.005
+
+// Audio queues samples generated by the emulator until the audio device asks for the next chunk.
+// The device itself belongs to the frontend.
+type Audio struct {
+	sampleRate  float32
+	AudioSample *Sample
+	ring        *RingBuffer
+	resampler   Resampler
+	resampled   []float32
+	lastSample  float32
+
+	volume float32
+	muted  bool
+}
+
+func NewAudio(sampleRate float32) *Audio {
+	return &Audio{
+		sampleRate:  sampleRate,
+		AudioSample: NewAudioSample(),
+		ring:        NewRingBuffer(ringBufferSize),
+		volume:      0.5,
+	}
+}
+
+func (a *Audio) SampleRate() float32 {
+	return a.sampleRate
+}
+
+// Push queues samples generated by the emulator, resampling them slightly
+// faster or slower depending on how full the ring buffer is.
+func (a *Audio) Push(samples []float32) {
+	ratio := 1 + maxRateDelta*(targetFill-a.ring.Fill())*2
+	a.resampled = a.resampler.Resample(samples, ratio, a.resampled[:0])
+	a.ring.Write(a.resampled)
+}
+
+// NextChunk fills AudioSample with queued samples, scaled by volume. The audio device calls it whenever
+// it has consumed the previous chunk.
+func (a *Audio) NextChunk() *Sample {
+	read := a.ring.Read(a.AudioSample.Sample)
+	if read > 0 {
+		a.lastSample = a.AudioSample.Sample[read-1]
+	}
+	// On underrun, hold last sample instead of dropping to 0, which would click
+	for i := read; i < len(a.AudioSample.Sample); i++ {
+		a.AudioSample.Sample[i] = a.lastSample
+	}
+
+	gain := a.volume
+	if a.muted {
+		gain = 0
+	}
+	for i := range a.AudioSample.Sample {
+		a.AudioSample.Sample[i] *= gain
+	}
+
+	return a.AudioSample
+}
+
+// SetVolume sets output volume, from 0 to 1
+func (a *Audio) SetVolume(volume float32) {
+	if volume < 0 {
+		volume = 0
+	} else if volume > 1 {
+		volume = 1
+	}
+	a.volume = volume
+}
+
+func (a *Audio) Volume() float32 {
+	return a.volume
+}
+
+func (a *Audio) SetMuted(muted bool) {
+	a.muted = muted
+}
+
+func (a *Audio) Muted() bool {
+	return a.muted
+}
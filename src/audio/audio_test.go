@@ -0,0 +1,27 @@
+package audio
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAudio_NextChunk_holds_last_sample_on_underrun(t *testing.T) {
+	a := NewAudio(44100)
+	a.SetVolume(1)
+	a.Push([]float32{0.5, 0.5, 0.5, 0.5})
+
+	chunk := a.NextChunk()
+
+	assert.Equal(t, int32(SamplesCount), chunk.SamplesCount)
+	assert.Equal(t, float32(0.5), chunk.Sample[SamplesCount-1])
+}
+
+func TestAudio_NextChunk_applies_volume(t *testing.T) {
+	a := NewAudio(44100)
+	a.SetVolume(0.5)
+	a.Push([]float32{1, 1, 1, 1})
+	assert.Equal(t, float32(0.5), a.NextChunk().Sample[SamplesCount-1])
+
+	a.SetMuted(true)
+	assert.Equal(t, float32(0), a.NextChunk().Sample[0])
+}
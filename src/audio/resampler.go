@@ -0,0 +1,24 @@
+package audio
+
+// Resampler converts a stream of samples to a slightly different rate using linear interpolation.
+// The ratio can change on every call, which lets the frontend steer the ring buffer fill level
+// (dynamic rate control) instead of dropping or repeating whole chunks of audio.
+type Resampler struct {
+	position float64 // Position of next output sample between last and next input sample
+	last     float32
+}
+
+// Resample appends to output the samples of input resampled by ratio (output rate / input rate)
+func (r *Resampler) Resample(input []float32, ratio float64, output []float32) []float32 {
+	step := 1 / ratio
+	for _, sample := range input {
+		for r.position < 1 {
+			output = append(output, r.last+(sample-r.last)*float32(r.position))
+			r.position += step
+		}
+		r.position -= 1
+		r.last = sample
+	}
+
+	return output
+}
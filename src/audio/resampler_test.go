@@ -0,0 +1,37 @@
+package audio
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestResampler_ratio_changes_output_length(t *testing.T) {
+	tests := []struct {
+		name     string
+		ratio    float64
+		expected int
+	}{
+		{"same rate", 1, 1000},
+		{"faster", 1.005, 1005},
+		{"slower", 0.995, 995},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resampler := Resampler{}
+			input := make([]float32, 1000)
+
+			output := resampler.Resample(input, tt.ratio, nil)
+
+			assert.InDelta(t, tt.expected, len(output), 1)
+		})
+	}
+}
+
+func TestResampler_interpolates_between_samples(t *testing.T) {
+	resampler := Resampler{}
+
+	output := resampler.Resample([]float32{0, 1, 2}, 2, nil)
+
+	assert.Equal(t, []float32{0, 0, 0, 0.5, 1, 1.5}, output)
+}
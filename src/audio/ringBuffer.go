@@ -0,0 +1,53 @@
+package audio
+
+// RingBuffer is a fixed size FIFO of samples. The emulator writes into it, the audio device reads from it.
+type RingBuffer struct {
+	samples []float32
+	read    int
+	length  int
+}
+
+func NewRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{samples: make([]float32, capacity)}
+}
+
+// Write appends samples, dropping the ones that do not fit. Returns how many were written.
+func (rb *RingBuffer) Write(samples []float32) int {
+	written := 0
+	for _, sample := range samples {
+		if rb.length == len(rb.samples) {
+			break
+		}
+		rb.samples[(rb.read+rb.length)%len(rb.samples)] = sample
+		rb.length++
+		written++
+	}
+
+	return written
+}
+
+// Read fills out with the oldest samples. Returns how many were read.
+func (rb *RingBuffer) Read(out []float32) int {
+	read := 0
+	for read < len(out) && rb.length > 0 {
+		out[read] = rb.samples[rb.read]
+		rb.read = (rb.read + 1) % len(rb.samples)
+		rb.length--
+		read++
+	}
+
+	return read
+}
+
+func (rb *RingBuffer) Len() int {
+	return rb.length
+}
+
+func (rb *RingBuffer) Cap() int {
+	return len(rb.samples)
+}
+
+// Fill returns how full the buffer is, from 0 to 1
+func (rb *RingBuffer) Fill() float64 {
+	return float64(rb.length) / float64(len(rb.samples))
+}
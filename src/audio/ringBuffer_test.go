@@ -0,0 +1,27 @@
+package audio
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRingBuffer_reads_samples_in_order(t *testing.T) {
+	rb := NewRingBuffer(4)
+	rb.Write([]float32{1, 2, 3})
+
+	out := make([]float32, 2)
+	assert.Equal(t, 2, rb.Read(out))
+	assert.Equal(t, []float32{1, 2}, out)
+
+	rb.Write([]float32{4, 5, 6})
+	out = make([]float32, 5)
+	assert.Equal(t, 4, rb.Read(out), "should wrap around")
+	assert.Equal(t, []float32{3, 4, 5, 6, 0}, out)
+}
+
+func TestRingBuffer_drops_samples_when_full(t *testing.T) {
+	rb := NewRingBuffer(2)
+
+	assert.Equal(t, 2, rb.Write([]float32{1, 2, 3}))
+	assert.Equal(t, 1.0, rb.Fill())
+}
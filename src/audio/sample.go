@@ -1,7 +1,6 @@
 package audio
 
-import "math"
-
+// Sample is the chunk of audio handed to the audio device on every stream update
 type Sample struct {
 	Sample       []float32
 	SamplesCount int32
@@ -15,15 +14,3 @@ func NewAudioSample() *Sample {
 
 	return &as
 }
-
-func (a *Sample) genDummy(osc *Oscillator) {
-	for t := 0; t < SamplesCount; t++ {
-		osc.phase = osc.phaseStride
-		if osc.phase >= 1.0 {
-			osc.phase -= 1.0
-		}
-
-		x := 2 * math.Pi * osc.phase * float32(t)
-		a.Sample[t] = float32(math.Sin(float64(x)))
-	}
-}
@@ -0,0 +1,193 @@
+// nes-headless runs a rom for a number of frames without window, audio device or any graphics dependency,
+// for CI and batch rendering.
+//
+//	nes-headless -rom game.nes -frames 600 -input inputs.txt -png last.png -wav audio.wav -ram-hash
+//
+// Battery backed RAM is neither loaded nor saved, so runs are reproducible.
+package main
+
+import (
+	"crypto/sha1"
+	"flag"
+	"fmt"
+	"github.com/raulferras/nes-golang/src/frontend"
+	"github.com/raulferras/nes-golang/src/nes"
+	"github.com/raulferras/nes-golang/src/nes/gamePak"
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+type options struct {
+	romPath   string
+	frames    int
+	inputPath string
+	region    string
+	pngPath   string
+	pngEvery  int
+	pngDir    string
+	wavPath   string
+	ramHash   bool
+}
+
+func main() {
+	options := cmdLineArguments()
+	if err := run(options); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func cmdLineArguments() options {
+	var romPath = flag.String("rom", "", "path to rom")
+	var frames = flag.Int("frames", 60, "frames to run")
+	var inputPath = flag.String("input", "", "input script for controller 1, lines of \"<frame> [buttons...]\"")
+	var region = flag.String("region", "", "console timing: ntsc, pal or dendy. Defaults to the one in the rom header")
+	var pngPath = flag.String("png", "", "writes the final frame into this PNG file")
+	var pngEvery = flag.Int("png-every", 0, "writes every Nth frame into -png-dir")
+	var pngDir = flag.String("png-dir", ".", "directory for frames written by -png-every")
+	var wavPath = flag.String("wav", "", "writes audio into this WAV file")
+	var ramHash = flag.Bool("ram-hash", false, "prints the SHA-1 of console RAM after the last frame")
+	flag.Parse()
+
+	if *romPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	return options{
+		romPath:   *romPath,
+		frames:    *frames,
+		inputPath: *inputPath,
+		region:    *region,
+		pngPath:   *pngPath,
+		pngEvery:  *pngEvery,
+		pngDir:    *pngDir,
+		wavPath:   *wavPath,
+		ramHash:   *ramHash,
+	}
+}
+
+func run(options options) error {
+	console, err := createConsole(options)
+	if err != nil {
+		return err
+	}
+
+	input := inputScript{}
+	if options.inputPath != "" {
+		if input, err = loadInputScript(options.inputPath); err != nil {
+			return err
+		}
+	}
+
+	video := &pngFrames{every: options.pngEvery, dir: options.pngDir}
+	var audio frontend.AudioSink = discardAudio{}
+	var wav *wavWriter
+	if options.wavPath != "" {
+		file, err := os.Create(options.wavPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if wav, err = newWAVWriter(file, console.APU().SampleRate()); err != nil {
+			return err
+		}
+		audio = wav
+	}
+
+	console.Start()
+	session := frontend.NewSession(console, video, audio, input, frontend.FreeRunningClock{})
+	for session.Frame() < options.frames {
+		if err := session.RunFrame(); err != nil {
+			return err
+		}
+	}
+
+	if wav != nil {
+		if err := wav.Close(); err != nil {
+			return err
+		}
+	}
+	if options.pngPath != "" {
+		if err := writePNG(options.pngPath, console.Frame()); err != nil {
+			return err
+		}
+	}
+	if options.ramHash {
+		fmt.Printf("%x\n", sha1.Sum(console.RAM()))
+	}
+
+	return nil
+}
+
+// createConsole loads the rom without a save file path, so battery backed RAM is not persisted
+func createConsole(options options) (*nes.Nes, error) {
+	file, err := os.Open(options.romPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	cartridge, err := gamePak.LoadGamePak(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", options.romPath, err)
+	}
+
+	console := nes.CreateNes(&cartridge, nes.CreateNesDebugger("", false, false))
+	if options.region != "" {
+		region, err := nes.RegionByName(options.region)
+		if err != nil {
+			return nil, err
+		}
+		console.SetRegion(region)
+	}
+
+	return console, nil
+}
+
+func loadInputScript(path string) (inputScript, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return inputScript{}, err
+	}
+	defer file.Close()
+
+	return parseInputScript(file)
+}
+
+// pngFrames writes every Nth frame into a directory. N being 0 writes none.
+type pngFrames struct {
+	every int
+	dir   string
+	frame int
+}
+
+func (video *pngFrames) PushFrame(frame *image.RGBA) error {
+	video.frame++
+	if video.every <= 0 || video.frame%video.every != 0 {
+		return nil
+	}
+
+	return writePNG(filepath.Join(video.dir, fmt.Sprintf("frame_%06d.png", video.frame)), frame)
+}
+
+type discardAudio struct{}
+
+func (discardAudio) PushSamples(samples []float32) error {
+	return nil
+}
+
+func writePNG(path string, frame image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, frame); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/raulferras/nes-golang/src/frontend"
+	"github.com/raulferras/nes-golang/src/nes"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// inputScript holds the buttons pressed on controller 1 along the run.
+//
+// Each line of a script gives the frame from which a set of buttons is held, until the next line:
+//
+//	# Comments start with #
+//	0
+//	120 start
+//	125
+//	200 right a
+//
+// Buttons are a, b, select, start, up, down, left and right. A frame without buttons releases them all.
+type inputScript struct {
+	changes []inputChange
+}
+
+type inputChange struct {
+	frame int
+	state nes.ControllerState
+}
+
+type scriptError struct {
+	line    int
+	message string
+}
+
+func (err scriptError) Error() string {
+	return fmt.Sprintf("input script line %d: %s", err.line, err.message)
+}
+
+func parseInputScript(reader io.Reader) (inputScript, error) {
+	script := inputScript{}
+	scanner := bufio.NewScanner(reader)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if comment := strings.Index(text, "#"); comment >= 0 {
+			text = text[:comment]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		frame, err := strconv.Atoi(fields[0])
+		if err != nil || frame < 0 {
+			return inputScript{}, scriptError{line, fmt.Sprintf("invalid frame %q", fields[0])}
+		}
+		change := inputChange{frame: frame}
+		for _, button := range fields[1:] {
+			if !pressButton(&change.state, strings.ToLower(button)) {
+				return inputScript{}, scriptError{line, fmt.Sprintf("unknown button %q", button)}
+			}
+		}
+		script.changes = append(script.changes, change)
+	}
+	if err := scanner.Err(); err != nil {
+		return inputScript{}, err
+	}
+
+	sort.SliceStable(script.changes, func(i, j int) bool {
+		return script.changes[i].frame < script.changes[j].frame
+	})
+
+	return script, nil
+}
+
+func pressButton(state *nes.ControllerState, button string) bool {
+	switch button {
+	case "a":
+		state.A = true
+	case "b":
+		state.B = true
+	case "select":
+		state.Select = true
+	case "start":
+		state.Start = true
+	case "up":
+		state.Up = true
+	case "down":
+		state.Down = true
+	case "left":
+		state.Left = true
+	case "right":
+		state.Right = true
+	default:
+		return false
+	}
+
+	return true
+}
+
+// ReadInput returns the buttons held along a frame
+func (script inputScript) ReadInput(frame int) frontend.Input {
+	state := nes.ControllerState{}
+	for _, change := range script.changes {
+		if change.frame > frame {
+			break
+		}
+		state = change.state
+	}
+
+	return frontend.Input{Controller1: state}
+}
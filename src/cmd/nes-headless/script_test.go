@@ -0,0 +1,51 @@
+package main
+
+import (
+	"github.com/raulferras/nes-golang/src/nes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestInputScript_holds_buttons_until_next_line(t *testing.T) {
+	script, err := parseInputScript(strings.NewReader(`
+# Press start, then run right jumping
+120 start
+125
+200 Right A # jump
+`))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		frame    int
+		expected nes.ControllerState
+	}{
+		{0, nes.ControllerState{}},
+		{120, nes.ControllerState{Start: true}},
+		{124, nes.ControllerState{Start: true}},
+		{125, nes.ControllerState{}},
+		{5000, nes.ControllerState{Right: true, A: true}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, script.ReadInput(tt.frame).Controller1, "frame %d", tt.frame)
+	}
+}
+
+func TestInputScript_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected error
+	}{
+		{"invalid frame", "0\nstart 10", scriptError{2, `invalid frame "start"`}},
+		{"negative frame", "-1 a", scriptError{1, `invalid frame "-1"`}},
+		{"unknown button", "10 a turbo", scriptError{1, `unknown button "turbo"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseInputScript(strings.NewReader(tt.script))
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// wavWriter writes mono 16 bit PCM audio. Sizes in the header are only known once all samples are written,
+// so they are filled by Close.
+type wavWriter struct {
+	writer     io.WriteSeeker
+	sampleRate uint32
+	dataSize   uint32
+	err        error
+}
+
+const wavHeaderSize = 44
+
+func newWAVWriter(writer io.WriteSeeker, sampleRate float64) (*wavWriter, error) {
+	wav := &wavWriter{writer: writer, sampleRate: uint32(math.Round(sampleRate))}
+	wav.writeHeader()
+
+	return wav, wav.err
+}
+
+// PushSamples appends samples, from -1 to 1
+func (wav *wavWriter) PushSamples(samples []float32) error {
+	if wav.err != nil {
+		return wav.err
+	}
+
+	data := make([]int16, len(samples))
+	for i, sample := range samples {
+		data[i] = int16(math.Max(-1, math.Min(1, float64(sample))) * math.MaxInt16)
+	}
+	wav.err = binary.Write(wav.writer, binary.LittleEndian, data)
+	wav.dataSize += uint32(2 * len(data))
+
+	return wav.err
+}
+
+// Close fills the sizes of the header
+func (wav *wavWriter) Close() error {
+	if wav.err != nil {
+		return wav.err
+	}
+	if _, err := wav.writer.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	wav.writeHeader()
+
+	return wav.err
+}
+
+func (wav *wavWriter) writeHeader() {
+	const channels = 1
+	const bitsPerSample = 16
+	blockAlign := uint16(channels * bitsPerSample / 8)
+
+	header := struct {
+		RIFF          [4]byte
+		RIFFSize      uint32
+		WAVE          [4]byte
+		Fmt           [4]byte
+		FmtSize       uint32
+		Format        uint16
+		Channels      uint16
+		SampleRate    uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+		Data          [4]byte
+		DataSize      uint32
+	}{
+		RIFF:          [4]byte{'R', 'I', 'F', 'F'},
+		RIFFSize:      wavHeaderSize - 8 + wav.dataSize,
+		WAVE:          [4]byte{'W', 'A', 'V', 'E'},
+		Fmt:           [4]byte{'f', 'm', 't', ' '},
+		FmtSize:       16,
+		Format:        1, // PCM
+		Channels:      channels,
+		SampleRate:    wav.sampleRate,
+		ByteRate:      wav.sampleRate * uint32(blockAlign),
+		BlockAlign:    blockAlign,
+		BitsPerSample: bitsPerSample,
+		Data:          [4]byte{'d', 'a', 't', 'a'},
+		DataSize:      wav.dataSize,
+	}
+	wav.err = binary.Write(wav.writer, binary.LittleEndian, &header)
+}
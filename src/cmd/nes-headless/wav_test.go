@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWAVWriter_fills_sizes_on_close(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "wav")
+	defer os.RemoveAll(dir)
+	file, _ := os.Create(filepath.Join(dir, "audio.wav"))
+	defer file.Close()
+
+	wav, err := newWAVWriter(file, 44100)
+	assert.NoError(t, err)
+	assert.NoError(t, wav.PushSamples([]float32{0, 1, -1}))
+	assert.NoError(t, wav.PushSamples([]float32{2}))
+	assert.NoError(t, wav.Close())
+
+	data, _ := ioutil.ReadFile(file.Name())
+	assert.Equal(t, wavHeaderSize+8, len(data))
+	assert.Equal(t, "RIFF", string(data[0:4]))
+	assert.Equal(t, uint32(wavHeaderSize-8+8), binary.LittleEndian.Uint32(data[4:]))
+	assert.Equal(t, uint32(44100), binary.LittleEndian.Uint32(data[24:]))
+	assert.Equal(t, uint32(8), binary.LittleEndian.Uint32(data[40:]))
+	assert.Equal(t, []byte{0x00, 0x00, 0xFF, 0x7F, 0x01, 0x80, 0xFF, 0x7F}, data[wavHeaderSize:], "samples should be clamped")
+}
@@ -21,6 +21,7 @@ type GuiDebugger struct {
 	emulator           *nes.Nes
 	ppuDebugger        *PPUDebugger
 	breakpointDebugger *breakpointDebugger
+	spritesDebugger    *spritesDebugger
 	audioDebugger      *audioDebugger
 }
 
@@ -40,6 +41,7 @@ func NewDebugger(emulator *nes.Nes, audio *audio.Audio) GuiDebugger {
 		emulator:              emulator,
 		ppuDebugger:           NewPPUDebugger(emulator.PPU()),
 		breakpointDebugger:    NewBreakpointDebugger(emulator),
+		spritesDebugger:       NewSpritesDebugger(emulator),
 		audioDebugger:         NewAudioDebugger(audio),
 	}
 }
@@ -54,6 +56,7 @@ func (dbg *GuiDebugger) Tick() {
 
 	dbg.ppuDebugger.Draw()
 	dbg.breakpointDebugger.Draw()
+	dbg.spritesDebugger.Draw()
 	//dbg.DrawDebugger(dbg.emulator)
 	dbg.audioDebugger.Draw()
 }
@@ -123,6 +126,10 @@ func (dbg *GuiDebugger) listenKeyboard() {
 	if rl.IsKeyPressed(rl.KeyO) {
 		dbg.breakpointDebugger.Toggle()
 	}
+
+	if rl.IsKeyPressed(rl.KeyI) {
+		dbg.spritesDebugger.Toggle()
+	}
 }
 
 func colorFlag(flag bool) rl.Color {
@@ -269,7 +276,7 @@ func drawPPUDebugger(console *nes.Nes) {
 
 func drawObjectAttributeEntries(console *nes.Nes) {
 	for i := 0; i < 20; i++ {
-		oae := console.Debugger().OAM(byte(i))
+		oae := console.Debugger().OAM(byte(i * 4))
 		graphics.DrawText(
 			fmt.Sprintf("[%d] x:%d y:%d tileId: %x",
 				i,
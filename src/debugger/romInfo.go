@@ -14,14 +14,40 @@ func PrintRomInfo(cartridge *gamePak.GamePak) {
 		fmt.Println("Rom has no trainer")
 	}
 
-	if inesHeader.Mirroring() == gamePak.VerticalMirroring {
+	switch inesHeader.Mirroring() {
+	case gamePak.VerticalMirroring:
 		fmt.Println("Vertical Mirroring")
-	} else {
+	case gamePak.FourScreenMirroring:
+		fmt.Println("Four Screen VRAM")
+	default:
 		fmt.Println("Horizontal Mirroring")
 	}
 
-	fmt.Println("PRG:", inesHeader.ProgramSize(), "x 16KB Banks")
-	fmt.Println("CHR:", inesHeader.CHRSize(), "x 8KB Banks")
-	fmt.Println("Mapper:", inesHeader.MapperNumber())
-	fmt.Println("Tv System:", inesHeader.TvSystem())
+	if inesHeader.IsNES2() {
+		fmt.Println("Format: NES 2.0")
+	} else {
+		fmt.Println("Format: iNES")
+	}
+
+	fmt.Println("PRG:", inesHeader.ProgramROMLength()/1024, "KB")
+	fmt.Println("CHR:", inesHeader.CHRROMLength()/1024, "KB")
+	fmt.Println("PRG RAM:", inesHeader.PRGRAMSize(), "bytes, NVRAM:", inesHeader.PRGNVRAMSize(), "bytes")
+	fmt.Println("CHR RAM:", inesHeader.CHRRAMSize(), "bytes, NVRAM:", inesHeader.CHRNVRAMSize(), "bytes")
+	fmt.Println("Mapper:", inesHeader.MapperNumber(), "Submapper:", inesHeader.SubMapper())
+	fmt.Println("Tv System:", tvSystemName(inesHeader.TvSystem()))
+	fmt.Println("Console Type:", inesHeader.ConsoleType())
+	fmt.Println("Expansion Device:", inesHeader.DefaultExpansionDevice())
+}
+
+func tvSystemName(tvSystem byte) string {
+	switch tvSystem {
+	case gamePak.TvSystemPAL:
+		return "PAL"
+	case gamePak.TvSystemMultiRegion:
+		return "Multi-region"
+	case gamePak.TvSystemDendy:
+		return "Dendy"
+	default:
+		return "NTSC"
+	}
 }
@@ -0,0 +1,67 @@
+package debugger
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/raulferras/nes-golang/src/nes"
+)
+
+const spritesPerRow = 16
+const spriteScale = int32(2)
+
+// Cells are tall enough to hold 8x16 sprites
+const spriteCellWidth = 8*spriteScale + 4
+const spriteCellHeight = 16*spriteScale + 4
+
+type spritesDebugger struct {
+	emulator *nes.Nes
+	panel    *draggablePanel
+}
+
+func NewSpritesDebugger(emulator *nes.Nes) *spritesDebugger {
+	return &spritesDebugger{
+		emulator: emulator,
+		panel: NewDraggablePanel(
+			"Sprites",
+			rl.Vector2{X: 300, Y: 10},
+			int(spritesPerRow*spriteCellWidth+10),
+			int(4*spriteCellHeight+40),
+		),
+	}
+}
+
+func (dbg *spritesDebugger) Toggle() {
+	dbg.panel.SetEnabled(!dbg.panel.enabled)
+}
+
+// Draw renders the 64 OAM sprites, 8x8 or 8x16 depending on PPUCTRL
+func (dbg *spritesDebugger) Draw() {
+	if !dbg.panel.Draw() {
+		return
+	}
+
+	anchorX := int32(dbg.panel.position.X) + 5
+	anchorY := int32(dbg.panel.position.Y) + 30
+	rl.DrawRectangle(anchorX, anchorY, spritesPerRow*spriteCellWidth, 4*spriteCellHeight, rl.DarkGray)
+
+	for i := 0; i < 64; i++ {
+		sprite := dbg.emulator.Debugger().Sprite(byte(i))
+		cellX := anchorX + int32(i%spritesPerRow)*spriteCellWidth + 2
+		cellY := anchorY + int32(i/spritesPerRow)*spriteCellHeight + 2
+
+		for x := 0; x < sprite.Bounds().Max.X; x++ {
+			for y := 0; y < sprite.Bounds().Max.Y; y++ {
+				pixel := sprite.RGBAAt(x, y)
+				if pixel.A == 0 {
+					continue
+				}
+				rl.DrawRectangle(
+					cellX+int32(x)*spriteScale,
+					cellY+int32(y)*spriteScale,
+					spriteScale,
+					spriteScale,
+					pixelColor2rlColor(pixel),
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package frontend
+
+import "time"
+
+// FreeRunningClock never waits, frames run as fast as the host allows
+type FreeRunningClock struct{}
+
+func (FreeRunningClock) WaitNextFrame() {}
+
+// A RealTimeClock running this late gives up catching up, and starts counting again from now
+const maxClockLag = 250 * time.Millisecond
+
+// RealTimeClock runs frames at the frame rate of the console
+type RealTimeClock struct {
+	frameDuration time.Duration
+	next          time.Time
+	now           func() time.Time
+	sleep         func(time.Duration)
+}
+
+func NewRealTimeClock(frameRate float64) *RealTimeClock {
+	return &RealTimeClock{
+		frameDuration: time.Duration(float64(time.Second) / frameRate),
+		now:           time.Now,
+		sleep:         time.Sleep,
+	}
+}
+
+// WaitNextFrame sleeps until a frame duration after the previous one ended. Deadlines are kept apart
+// by exactly a frame duration, so sleeping a bit too long on a frame is made up on the next ones.
+func (clock *RealTimeClock) WaitNextFrame() {
+	now := clock.now()
+	if clock.next.IsZero() || now.Sub(clock.next) > maxClockLag {
+		clock.next = now
+	}
+	clock.next = clock.next.Add(clock.frameDuration)
+
+	if wait := clock.next.Sub(now); wait > 0 {
+		clock.sleep(wait)
+	}
+}
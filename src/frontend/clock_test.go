@@ -0,0 +1,47 @@
+package frontend
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// aFakeTimeClock returns a clock whose sleeps move time forward, plus a frame of delay on each one
+func aFakeTimeClock(now *time.Time, sleeps *[]time.Duration) *RealTimeClock {
+	clock := NewRealTimeClock(50)
+	clock.now = func() time.Time { return *now }
+	clock.sleep = func(duration time.Duration) {
+		*sleeps = append(*sleeps, duration)
+		*now = now.Add(duration)
+	}
+
+	return clock
+}
+
+func TestRealTimeClock_makes_up_for_slow_frames(t *testing.T) {
+	now := time.Unix(0, 0)
+	sleeps := []time.Duration{}
+	clock := aFakeTimeClock(&now, &sleeps)
+
+	clock.WaitNextFrame()
+	now = now.Add(5 * time.Millisecond) // fast frame
+	clock.WaitNextFrame()
+	now = now.Add(30 * time.Millisecond) // slow frame
+	clock.WaitNextFrame()
+	now = now.Add(5 * time.Millisecond)
+	clock.WaitNextFrame()
+
+	assert.Equal(t, []time.Duration{20 * time.Millisecond, 15 * time.Millisecond, 5 * time.Millisecond}, sleeps)
+}
+
+func TestRealTimeClock_does_not_catch_up_after_a_stall(t *testing.T) {
+	now := time.Unix(0, 0)
+	sleeps := []time.Duration{}
+	clock := aFakeTimeClock(&now, &sleeps)
+
+	clock.WaitNextFrame()
+	now = now.Add(time.Second)
+	clock.WaitNextFrame()
+
+	assert.Equal(t, []time.Duration{20 * time.Millisecond, 20 * time.Millisecond}, sleeps)
+}
@@ -0,0 +1,35 @@
+// Package frontend connects the console to the outside world: a screen, speakers, a controller and a clock.
+// Each frontend implements these pieces on its own platform, like the raylib window of app, or the files written by
+// nes-headless, and Session runs the console on them. It does not depend on cgo.
+package frontend
+
+import (
+	"github.com/raulferras/nes-golang/src/nes"
+	"image"
+)
+
+// VideoSink receives each emulated frame
+type VideoSink interface {
+	PushFrame(frame *image.RGBA) error
+}
+
+// AudioSink receives the samples generated along each frame, from -1 to 1, at the APU sample rate
+type AudioSink interface {
+	PushSamples(samples []float32) error
+}
+
+// InputSource tells the buttons held on each frame
+type InputSource interface {
+	ReadInput(frame int) Input
+}
+
+type Input struct {
+	Controller1 nes.ControllerState
+	Controller2 nes.ControllerState
+	Rewind      bool // Play frames backwards, when rewind is enabled
+}
+
+// Clock paces emulation. WaitNextFrame returns when it is time to run the next frame.
+type Clock interface {
+	WaitNextFrame()
+}
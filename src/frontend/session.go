@@ -0,0 +1,85 @@
+package frontend
+
+import (
+	"github.com/raulferras/nes-golang/src/nes"
+	"github.com/raulferras/nes-golang/src/nes/rewind"
+)
+
+// Session runs a console on a frontend, one frame at a time
+type Session struct {
+	console *nes.Nes
+	video   VideoSink
+	audio   AudioSink
+	input   InputSource
+	clock   Clock
+	history *rewind.Buffer // nil when rewind is disabled
+	frame   int
+}
+
+func NewSession(console *nes.Nes, video VideoSink, audio AudioSink, input InputSource, clock Clock) *Session {
+	return &Session{
+		console: console,
+		video:   video,
+		audio:   audio,
+		input:   input,
+		clock:   clock,
+	}
+}
+
+// EnableRewind keeps a history of the console state, played backwards while input asks to rewind
+func (session *Session) EnableRewind(options rewind.Options) {
+	session.history = rewind.NewBuffer(options)
+}
+
+// Frame returns how many frames were run
+func (session *Session) Frame() int {
+	return session.frame
+}
+
+// RunFrame reads input, runs a frame, or goes a frame back in history while rewinding,
+// hands its audio and video to the sinks, and waits for the clock.
+func (session *Session) RunFrame() error {
+	input := session.input.ReadInput(session.frame)
+	session.console.UpdateController(1, input.Controller1)
+	session.console.UpdateController(2, input.Controller2)
+
+	switch {
+	case input.Rewind && session.history != nil:
+		if err := session.rewindFrame(); err != nil {
+			return err
+		}
+	case session.console.Paused():
+		session.console.PausedTick()
+	default:
+		session.console.TickTillFrameComplete()
+		if session.history != nil {
+			if err := session.history.Capture(session.console); err != nil {
+				return err
+			}
+		}
+	}
+	session.frame++
+
+	if err := session.audio.PushSamples(session.console.APU().DrainSamples()); err != nil {
+		return err
+	}
+	if err := session.video.PushFrame(session.console.Frame()); err != nil {
+		return err
+	}
+	session.clock.WaitNextFrame()
+
+	return nil
+}
+
+// rewindFrame goes one snapshot back in history, and runs a frame to show it. Its audio is dropped.
+func (session *Session) rewindFrame() error {
+	rewound, err := session.history.Rewind(session.console)
+	if err != nil || !rewound {
+		return err
+	}
+
+	session.console.TickTillFrameComplete()
+	session.console.APU().DrainSamples()
+
+	return nil
+}
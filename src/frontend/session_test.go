@@ -0,0 +1,97 @@
+package frontend
+
+import (
+	"github.com/raulferras/nes-golang/src/nes"
+	"github.com/raulferras/nes-golang/src/nes/gamePak"
+	"github.com/raulferras/nes-golang/src/nes/rewind"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"testing"
+)
+
+type recordingVideo struct {
+	frames int
+}
+
+func (video *recordingVideo) PushFrame(frame *image.RGBA) error {
+	video.frames++
+	return nil
+}
+
+type recordingAudio struct {
+	samples int
+}
+
+func (audio *recordingAudio) PushSamples(samples []float32) error {
+	audio.samples += len(samples)
+	return nil
+}
+
+// scriptedInput rewinds along the given frames
+type scriptedInput struct {
+	rewindFrom int
+	frames     []int
+}
+
+func (input *scriptedInput) ReadInput(frame int) Input {
+	input.frames = append(input.frames, frame)
+	return Input{Rewind: input.rewindFrom > 0 && frame >= input.rewindFrom}
+}
+
+type countingClock struct {
+	waits int
+}
+
+func (clock *countingClock) WaitNextFrame() {
+	clock.waits++
+}
+
+func aSession(t *testing.T, input InputSource) (*Session, *nes.Nes, *recordingVideo, *recordingAudio, *countingClock) {
+	cartridge, err := gamePak.CreateGamePakFromROMFile("./../../assets/roms/tests/nestest/nestest.nes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	console := nes.CreateNes(&cartridge, nes.CreateNesDebugger("", false, false))
+	console.Start()
+
+	video := &recordingVideo{}
+	audio := &recordingAudio{}
+	clock := &countingClock{}
+
+	return NewSession(console, video, audio, input, clock), console, video, audio, clock
+}
+
+func TestSession_RunFrame_hands_each_frame_to_the_frontend(t *testing.T) {
+	input := &scriptedInput{}
+	session, console, video, audio, clock := aSession(t, input)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, session.RunFrame())
+	}
+
+	assert.Equal(t, []int{0, 1, 2}, input.frames)
+	assert.Equal(t, 3, video.frames)
+	assert.Equal(t, 3, clock.waits)
+	assert.Equal(t, 3, session.Frame())
+	assert.InDelta(t, 3*console.APU().SampleRate()/console.Region().FrameRate, audio.samples, 100)
+}
+
+func TestSession_RunFrame_plays_frames_backwards_while_rewinding(t *testing.T) {
+	input := &scriptedInput{rewindFrom: 10}
+	session, console, video, audio, _ := aSession(t, input)
+	session.EnableRewind(rewind.DefaultOptions())
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, session.RunFrame())
+	}
+	frameNumber := console.PPU().FrameNumber()
+	samples := audio.samples
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, session.RunFrame())
+	}
+
+	assert.Equal(t, frameNumber-3, console.PPU().FrameNumber(), "each frame should go one frame back")
+	assert.Equal(t, 14, video.frames)
+	assert.Equal(t, samples, audio.samples, "audio should be dropped while rewinding")
+}
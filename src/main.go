@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"github.com/raulferras/nes-golang/src/app"
+	"github.com/raulferras/nes-golang/src/nes/ppu"
 	_ "net/http/pprof"
 )
 
@@ -18,7 +19,21 @@ func cmdLineArguments() app.Options {
 	var debugPPU = flag.Bool("debugPPU", false, "Displays PPU debug information")
 	var scale = flag.Int("scale", 1, "scale resolution")
 	var breakpoint = flag.String("breakpoint", "", "defines a breakpoint on start")
+	var volume = flag.Int("volume", 50, "audio volume, from 0 to 100")
+	var mute = flag.Bool("mute", false, "starts with audio muted")
+	var palette = flag.String("palette", "", "path to a .pal palette file (192 or 1536 bytes). Defaults to a generated NTSC palette")
+	var hue = flag.Float64("hue", ppu.DefaultNTSCParameters.Hue, "generated palette hue shift, in degrees")
+	var saturation = flag.Float64("saturation", ppu.DefaultNTSCParameters.Saturation, "generated palette saturation")
+	var contrast = flag.Float64("contrast", ppu.DefaultNTSCParameters.Contrast, "generated palette contrast")
+	var brightness = flag.Float64("brightness", ppu.DefaultNTSCParameters.Brightness, "generated palette brightness")
+	var region = flag.String("region", "", "console timing: ntsc, pal or dendy. Defaults to the one in the rom header")
 	flag.Parse()
 
-	return app.NewOptions(*scale, *romPath, *logCPU, *debugPPU, *breakpoint, *cpuprofile)
+	ntsc := ppu.DefaultNTSCParameters
+	ntsc.Hue = *hue
+	ntsc.Saturation = *saturation
+	ntsc.Contrast = *contrast
+	ntsc.Brightness = *brightness
+
+	return app.NewOptions(*scale, *romPath, *logCPU, *debugPPU, *breakpoint, *cpuprofile, *volume, *mute, *palette, ntsc, *region)
 }
@@ -29,6 +29,16 @@ func (m *MockableHeader) CHRSize() byte {
 	panic("implement me")
 }
 
+func (m *MockableHeader) ProgramROMLength() int {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockableHeader) CHRROMLength() int {
+	//TODO implement me
+	panic("implement me")
+}
+
 func (m *MockableHeader) Mirroring() byte {
 	args := m.Called()
 
@@ -50,12 +60,37 @@ func (m *MockableHeader) IgnoreMirroringControl() bool {
 	panic("implement me")
 }
 
-func (m *MockableHeader) MapperNumber() byte {
+func (m *MockableHeader) IsNES2() bool {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockableHeader) MapperNumber() uint16 {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockableHeader) SubMapper() byte {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockableHeader) PRGRAMSize() int {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockableHeader) PRGNVRAMSize() int {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockableHeader) CHRRAMSize() int {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *MockableHeader) PRGRAM() byte {
+func (m *MockableHeader) CHRNVRAMSize() int {
 	//TODO implement me
 	panic("implement me")
 }
@@ -64,3 +99,13 @@ func (m *MockableHeader) TvSystem() byte {
 	//TODO implement me
 	panic("implement me")
 }
+
+func (m *MockableHeader) ConsoleType() byte {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MockableHeader) DefaultExpansionDevice() byte {
+	//TODO implement me
+	panic("implement me")
+}
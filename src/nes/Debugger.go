@@ -221,3 +221,8 @@ func (debugger *Debugger) GetPaletteColorFromPaletteRam(paletteIndex byte, color
 func (debugger *Debugger) OAM(index byte) []byte {
 	return debugger.ppu.Oam(index)
 }
+
+// Sprite renders the sprite at the given OAM slot (0-63)
+func (debugger *Debugger) Sprite(index byte) image.RGBA {
+	return debugger.ppu.Sprite(index)
+}
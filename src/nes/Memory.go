@@ -2,6 +2,7 @@ package nes
 
 import (
 	"fmt"
+	"github.com/raulferras/nes-golang/src/nes/apu"
 	"github.com/raulferras/nes-golang/src/nes/gamePak"
 	"github.com/raulferras/nes-golang/src/nes/ppu"
 	"github.com/raulferras/nes-golang/src/nes/types"
@@ -49,48 +50,36 @@ type Memory interface {
 	Peek(types.Address) byte
 	Read(types.Address) byte
 	Write(types.Address, byte)
-
-	IsDMAWaiting() bool
-	IsDMATransfer() bool
-	DisableDMWaiting()
-	GetDMAPage() byte
-	GetDMAAddress() byte
-	GetDMAReadBuffer() byte
-	SetDMAReadBuffer(value byte)
-	IncrementDMAAddress()
-	ResetDMA()
 }
 
 type CPUMemory struct {
 	ram              [0xFFFF + 1]byte
 	gamePak          *gamePak.GamePak
 	ppu              ppu.PPU
-	DmaTransfer      bool
-	DmaPage          byte
-	DmaWaiting       bool
-	DmaAddress       byte
-	DmaReadBuffer    byte
+	apu              *apu.Apu2a03
+	dma              *dma
 	controllers      [2]byte
 	controllersState [2]byte
 }
 
-func newCPUMemory(ppu ppu.PPU, gamePak *gamePak.GamePak) *CPUMemory {
+func newCPUMemory(ppu ppu.PPU, apu *apu.Apu2a03, gamePak *gamePak.GamePak) *CPUMemory {
 	return &CPUMemory{
 		gamePak: gamePak,
 		ppu:     ppu,
+		apu:     apu,
 	}
 }
 
-func newNESCPUMemory(ppu ppu.PPU, cartidge *gamePak.GamePak) *CPUMemory {
-	return &CPUMemory{gamePak: cartidge, ppu: ppu}
+func newNESCPUMemory(ppu ppu.PPU, apu *apu.Apu2a03, cartidge *gamePak.GamePak) *CPUMemory {
+	return &CPUMemory{gamePak: cartidge, ppu: ppu, apu: apu}
 }
 
 func (cm *CPUMemory) Peek(address types.Address) byte {
-	return cm.read(address, false)
+	return cm.read(address, true)
 }
 
 func (cm *CPUMemory) Read(address types.Address) byte {
-	return cm.read(address, true)
+	return cm.read(address, false)
 }
 
 func (cm *CPUMemory) read(address types.Address, readOnly bool) byte {
@@ -104,11 +93,18 @@ func (cm *CPUMemory) read(address types.Address, readOnly bool) byte {
 		if (cm.controllersState[address&0x0001] & 0x80) > 0 {
 			data = 1
 		}
-		cm.controllersState[address&0x0001] <<= 1
+		if !readOnly {
+			cm.controllersState[address&0x0001] <<= 1
+		}
 
 		return data
+	} else if address == apu.STATUS {
+		if readOnly {
+			return cm.apu.PeekStatus()
+		}
+		return cm.apu.ReadRegister(address)
 	} else if address >= types.Address(0x4000) && address <= types.Address(0x401F) {
-		// TODO Implement APU / IO reading
+		// Write only APU registers and disabled APU/IO test functionality
 		return 0x00
 	} else if address >= gamePak.GAMEPAK_LOW_RANGE {
 		return cm.gamePak.ReadPrgROM(address)
@@ -122,54 +118,19 @@ func (cm *CPUMemory) Write(address types.Address, value byte) {
 		cm.ram[address&RAM_LAST_REAL_ADDRESS] = value
 	} else if address <= 0x3FFF {
 		cm.ppu.WriteRegister(address&0x2007, value)
-	} else if address >= CONTROLLER_1_ADDRESS && address <= CONTROLLER_2_ADDRESS {
-		cm.snapshotControllerState(address)
-	} else if address == 0x4014 {
-		cm.DmaTransfer = true
-		cm.DmaWaiting = true
-		cm.DmaPage = value
-		cm.DmaAddress = 0
-	} else if address >= gamePak.GAMEPAK_ROM_LOWER_BANK_START {
+	} else if address == CONTROLLER_1_ADDRESS {
+		// Strobe latches both controllers
+		cm.snapshotControllerState(CONTROLLER_1_ADDRESS)
+		cm.snapshotControllerState(CONTROLLER_2_ADDRESS)
+	} else if address == ppu.OAMDMA {
+		cm.dma.requestOAM(value)
+	} else if address >= apu.APU_LOW_ADDRESS && address <= apu.APU_HIGH_ADDRESS {
+		cm.apu.WriteRegister(address, value)
+	} else if address >= gamePak.GAMEPAK_LOW_RANGE {
 		cm.gamePak.WritePrgROM(address, value)
 	}
 }
 
-func (cm *CPUMemory) IsDMAWaiting() bool {
-	return cm.DmaWaiting
-}
-
-func (cm *CPUMemory) IsDMATransfer() bool {
-	return cm.DmaTransfer
-}
-
-func (cm *CPUMemory) DisableDMWaiting() {
-	cm.DmaWaiting = false
-}
-
-func (cm *CPUMemory) GetDMAPage() byte {
-	return cm.DmaPage
-}
-
-func (cm *CPUMemory) GetDMAAddress() byte {
-	return cm.DmaAddress
-}
-
-func (cm *CPUMemory) GetDMAReadBuffer() byte {
-	return cm.DmaReadBuffer
-}
-
-func (cm *CPUMemory) SetDMAReadBuffer(value byte) {
-	cm.DmaReadBuffer = value
-}
-
-func (cm *CPUMemory) IncrementDMAAddress() {
-	cm.DmaAddress++
-}
-
-func (cm *CPUMemory) ResetDMA() {
-	cm.DmaTransfer = false
-}
-
 func (cm *CPUMemory) snapshotControllerState(address types.Address) {
 	cm.controllersState[address&0x0001] = cm.controllers[address&0x0001]
 }
@@ -179,7 +179,6 @@ func TestCPUMemory_Writing_into_controller_takes_a_snapshot_of_the_controller(t
 
 	bus.Write(0x4016, 1)
 	assert.Equal(t, buttons.value(), bus.controllersState[0])
-	bus.Write(0x4017, 1)
 	assert.Equal(t, buttons.value(), bus.controllersState[1])
 }
 
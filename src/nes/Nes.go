@@ -2,6 +2,7 @@ package nes
 
 import (
 	"github.com/FMNSSun/hexit"
+	"github.com/raulferras/nes-golang/src/nes/apu"
 	cpu2 "github.com/raulferras/nes-golang/src/nes/cpu"
 	"github.com/raulferras/nes-golang/src/nes/gamePak"
 	"github.com/raulferras/nes-golang/src/nes/ppu"
@@ -12,22 +13,33 @@ import (
 )
 
 type Nes struct {
-	Cpu *Cpu6502
-	ppu *ppu.P2c02
-	bus *CPUMemory
+	Cpu       *Cpu6502
+	ppu       *ppu.P2c02
+	apu       *apu.Apu2a03
+	bus       *CPUMemory
+	dma       *dma
+	cartridge *gamePak.GamePak
 
 	systemClockCounter uint64 // Controls how many times to call each processor
+	cpuClockCounter    uint64 // Lifetime CPU cycles, DMA transfers included
+	region             Region
 	debug              *Debugger
 	vBlankCount        byte
 	finished           bool
 	paused             bool
+	batteryFlushFrame  uint16 // Frame number when battery backed RAM was last persisted
 }
 
+// Battery backed PRG RAM is persisted every few seconds, so progress survives a crash
+const batteryFlushFrames = 60 * 5
+
 func CreateNes(gamePak *gamePak.GamePak, debugger *Debugger) *Nes {
 	hexit.BuildTable()
 	thePPU := ppu.CreatePPU(gamePak, debugger.DebugPPU, debugger.logPath+"/ppu.log")
 
-	cpuBus := newNESCPUMemory(thePPU, gamePak)
+	theAPU := apu.CreateAPU(apu.DefaultSampleRate)
+	cpuBus := newNESCPUMemory(thePPU, theAPU, gamePak)
+	cpuBus.dma = newDMA(cpuBus, theAPU)
 	cpu := CreateCPU(
 		cpuBus,
 		cpu2.NewDebugger(debugger.debugCPU, debugger.logPath+"/Cpu.log"),
@@ -36,17 +48,32 @@ func CreateNes(gamePak *gamePak.GamePak, debugger *Debugger) *Nes {
 	debugger.ppu = thePPU
 
 	nes := &Nes{
-		Cpu:   cpu,
-		ppu:   thePPU,
-		bus:   cpuBus,
-		debug: debugger,
+		Cpu:       cpu,
+		ppu:       thePPU,
+		apu:       theAPU,
+		bus:       cpuBus,
+		dma:       cpuBus.dma,
+		cartridge: gamePak,
+		debug:     debugger,
 	}
 
 	nes.debug.pauseEmulation = nes.Pause
+	nes.SetRegion(RegionFromTvSystem(gamePak.Header().TvSystem()))
 
 	return nes
 }
 
+// SetRegion changes CPU/PPU clock ratio, frame layout and APU periods to the ones of a console model
+func (nes *Nes) SetRegion(region Region) {
+	nes.region = region
+	nes.ppu.SetTiming(region.PPU)
+	nes.apu.SetTiming(region.APU)
+}
+
+func (nes *Nes) Region() Region {
+	return nes.region
+}
+
 func (nes *Nes) StartAt(address types.Address) {
 	nes.systemClockCounter = 0
 	disassembledMap, sortedDisassembled := nes.Cpu.Disassemble(0x8000, 0xFFFF)
@@ -59,11 +86,13 @@ func (nes *Nes) StartAt(address types.Address) {
 		nes.ppu.Tick()
 		nes.systemClockCounter++
 	}
+	nes.cpuClockCounter = 7
 }
 
 // Start todo Rename to PowerOn
 func (nes *Nes) Start() {
 	nes.systemClockCounter = 0
+	nes.cpuClockCounter = 0
 	disassembledMap, sortedDisassembled := nes.Cpu.Disassemble(0x8000, 0xFFFF)
 	nes.debug.disassembled = disassembledMap
 	nes.debug.sortedDisassembled = sortedDisassembled
@@ -102,20 +131,15 @@ func (nes *Nes) PausedTick() {
 	}
 
 	// Run until just before next cpu operation schedules to be called
-	if (nes.systemClockCounter)%3 != 0 {
-		for {
-			nes.Tick()
-			if (nes.systemClockCounter)%3 == 0 {
-				break
-			}
-		}
+	for !nes.isCPUCycle() {
+		nes.Tick()
 	}
 
 	nes.Debugger().oneCpuOperationRan()
 }
 
 func (nes *Nes) TickForTime(seconds float64) {
-	cycles := int(1789773 * seconds)
+	cycles := int(nes.region.APU.CPUFrequency * seconds)
 	//waitingForCpuOperation := false
 	for cycles > 0 {
 		if nes.Cpu.Complete() && nes.Debugger().shouldPauseEmulation() {
@@ -143,67 +167,94 @@ func (nes *Nes) Tick() (byte, bool) {
 	if nes.Cpu.debugger.Enabled {
 		ppuState = ppu.NewSimplePPUState(nes.ppu.FrameNumber(), nes.ppu.RenderCycle(), nes.ppu.Scanline())
 	}
-	//start := time.Now()
-	nes.ppu.Tick()
-	//elapsed := time.Since(start)
-	//log.Printf("ppu took %s", elapsed)
-
-	//start = time.Now()
 	cpuCycles := byte(0)
 	cpuExecuted := false
-	if nes.systemClockCounter%3 == 0 {
+	cpuHalted := false
+	if nes.isCPUCycle() {
 		cpuExecuted = true
-		if nes.Cpu.memory.IsDMATransfer() {
-			// DMA starts on an even Cpu cycle
-			if nes.Cpu.memory.IsDMAWaiting() {
-				if nes.systemClockCounter%2 == 1 {
-					nes.Cpu.memory.DisableDMWaiting()
-				}
-			} else {
-				// On even cycles, read from RAM
-				if nes.systemClockCounter%2 == 0 {
-					address := uint16(nes.Cpu.memory.GetDMAPage())<<8 | uint16(nes.Cpu.memory.GetDMAAddress())
-					nes.Cpu.memory.SetDMAReadBuffer(nes.Cpu.memory.Read(types.Address(address)))
-				} else { // On odd cycles, write to OAMDATA
-					nes.ppu.WriteRegister(ppu.OAMADDR, nes.Cpu.memory.GetDMAAddress())
-					nes.ppu.WriteRegister(ppu.OAMDATA, nes.Cpu.memory.GetDMAReadBuffer())
-					nes.Cpu.memory.IncrementDMAAddress()
-					if nes.Cpu.memory.GetDMAAddress() == 0 {
-						nes.Cpu.memory.ResetDMA()
-					}
-				}
-			}
-			cpuCycles = 1
-		} else {
-			spend, cpuState := nes.Cpu.Tick()
-			cpuCycles = spend
-			if nes.Cpu.debugger.Enabled {
-				nes.Cpu.debugger.LogState(
-					cpuState,
-					ppuState,
-				)
-			}
-		}
+		nes.apu.Tick()
+		nes.cartridge.OnCPUCycle(nes.cpuClockCounter)
+		cpuCycles, cpuHalted = nes.tickCPU(ppuState)
 	}
-	//elapsed = time.Since(start)
-	//log.Printf("cpu took %s", elapsed)
 
-	if nes.ppu.Nmi() {
-		nes.Cpu.nmi()
-		nes.ppu.ResetNmi()
-	}
+	// The PPU goes on while the CPU cycle completes, interrupt lines are sampled at its end.
+	// A PPUSTATUS read racing VBlank can then hide the NMI for 2 PPU cycles, not 3.
+	nes.ppu.Tick()
 
+	if cpuExecuted {
+		nes.updateInterruptLines()
+		if !cpuHalted {
+			nes.Cpu.pollInterrupts()
+		}
+		nes.cpuClockCounter++
+	}
 	nes.systemClockCounter++
 
+	if nes.ppu.FrameNumber()-nes.batteryFlushFrame >= batteryFlushFrames {
+		nes.batteryFlushFrame = nes.ppu.FrameNumber()
+		nes.flushBatteryRAM()
+	}
+
 	return cpuCycles, cpuExecuted
 }
 
+// tickCPU runs a CPU cycle, unless the DMA unit has halted the CPU to take it over, which is told back
+func (nes *Nes) tickCPU(ppuState ppu.SimplePPUState) (byte, bool) {
+	nes.dma.pollDMC()
+	if nes.dma.wantsToHalt() {
+		// Writes can not be halted, the CPU goes on until it reads
+		if address, write := nes.Cpu.nextBusAccess(); !write {
+			nes.dma.halt(address)
+		}
+	}
+
+	if nes.dma.halted {
+		nes.dma.tick(nes.cpuClockCounter%2 == 0)
+		nes.Cpu.stall()
+		return 1, true
+	}
+
+	cpuCycles, cpuState := nes.Cpu.Tick()
+	if nes.Cpu.debugger.Enabled {
+		nes.Cpu.debugger.LogState(
+			cpuState,
+			ppuState,
+		)
+	}
+
+	return cpuCycles, false
+}
+
+// isCPUCycle tells if the CPU is clocked along the current PPU cycle.
+// Each Tick is a PPU cycle, PPUDivider master clock cycles long. The CPU is clocked
+// when the master clock crosses a multiple of CPUDivider: every 3 PPU cycles on NTSC, 3.2 on PAL.
+func (nes *Nes) isCPUCycle() bool {
+	masterClock := nes.systemClockCounter * nes.region.PPUDivider
+
+	return masterClock%nes.region.CPUDivider < nes.region.PPUDivider
+}
+
+// updateInterruptLines lets the CPU sample its NMI and IRQ lines once per CPU cycle
+func (nes *Nes) updateInterruptLines() {
+	nes.Cpu.SetNMI(nes.ppu.NMIAsserted())
+	nes.Cpu.SetIRQ(IRQFrameCounter, nes.apu.FrameIRQ())
+	nes.Cpu.SetIRQ(IRQDMC, nes.apu.DMCIRQ())
+	nes.Cpu.SetIRQ(IRQMapper, nes.cartridge.IRQ())
+}
+
 func (nes *Nes) Stop() {
 	nes.Cpu.Stop()
 	nes.ppu.Stop()
+	nes.flushBatteryRAM()
 	nes.finished = true
 }
 
+func (nes *Nes) flushBatteryRAM() {
+	if err := nes.cartridge.FlushBatteryRAM(); err != nil {
+		log.Printf("could not write save file: %s", err)
+	}
+}
+
 func (nes *Nes) Finished() bool {
 	return nes.finished
 }
@@ -227,10 +278,22 @@ func (nes *Nes) FramePattern() []byte {
 	return nes.ppu.FramePattern()
 }
 
+// RAM returns a copy of the 2KB of console RAM
+func (nes *Nes) RAM() []byte {
+	ram := make([]byte, RAM_LAST_REAL_ADDRESS+1)
+	copy(ram, nes.bus.ram[:])
+
+	return ram
+}
+
 func (nes *Nes) PPU() *ppu.P2c02 {
 	return nes.ppu
 }
 
+func (nes *Nes) APU() *apu.Apu2a03 {
+	return nes.apu
+}
+
 func (nes *Nes) handlePanic() {
 	a := recover()
 	if a != nil {
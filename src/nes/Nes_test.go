@@ -0,0 +1,110 @@
+package nes
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/gamePak"
+	"github.com/raulferras/nes-golang/src/nes/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// aNopNes returns a console executing NOPs from RAM. Empty PRG ROM makes the NMI handler start at $0000, also NOPs.
+func aNopNes() *Nes {
+	cartridge := gamePak.CreateGamePak(
+		gamePak.CreateINes1Header(1, 1, 0, 0, 0, 0, 0),
+		make([]byte, 0x4000),
+		gamePak.NewEmptyCHRROM(),
+	)
+	nes := CreateNes(&cartridge, aDebugger())
+	for address := 0; address < 0x0800; address++ {
+		nes.bus.Write(types.Address(address), 0xEA)
+	}
+	nes.Cpu.registers.Pc = 0x0100
+	nes.Cpu.registers.Sp = 0xFD
+
+	return nes
+}
+
+func tickCPUCycles(nes *Nes, cycles int) {
+	for executed := 0; executed < cycles; {
+		if _, cpuExecuted := nes.Tick(); cpuExecuted {
+			executed++
+		}
+	}
+}
+
+func TestNes_NMI_is_triggered_once_per_edge(t *testing.T) {
+	nes := aNopNes()
+	nes.ppu.PpuControl.GenerateNMIAtVBlank = true
+	nes.ppu.PpuStatus.VerticalBlankStarted = true
+
+	tickCPUCycles(nes, 40)
+
+	assert.Equal(t, byte(0xFA), nes.Cpu.registers.Sp, "NMI should have been serviced exactly once")
+	assert.Equal(t, byte(1), nes.Cpu.registers.InterruptFlag())
+}
+
+func TestNes_NMI_is_triggered_when_enabled_during_vblank(t *testing.T) {
+	nes := aNopNes()
+	nes.ppu.PpuStatus.VerticalBlankStarted = true
+
+	tickCPUCycles(nes, 10)
+	assert.Equal(t, byte(0xFD), nes.Cpu.registers.Sp, "NMI should not trigger while disabled")
+
+	nes.ppu.PpuControl.GenerateNMIAtVBlank = true
+	tickCPUCycles(nes, 10)
+	assert.Equal(t, byte(0xFA), nes.Cpu.registers.Sp, "Enabling NMI in VBlank should trigger it")
+}
+
+func TestNes_NMI_waits_for_current_instruction_to_complete(t *testing.T) {
+	nes := aNopNes()
+	nes.ppu.PpuControl.GenerateNMIAtVBlank = true
+	nes.ppu.PpuStatus.VerticalBlankStarted = true
+
+	// First cycle of a NOP detects the NMI, it is serviced once the NOP completes
+	tickCPUCycles(nes, 1)
+	assert.True(t, nes.Cpu.nmiPending)
+	assert.Equal(t, byte(0xFD), nes.Cpu.registers.Sp)
+
+	tickCPUCycles(nes, 1)
+	assert.False(t, nes.Cpu.nmiPending)
+	assert.Equal(t, byte(0xFD), nes.Cpu.registers.Sp, "NMI sequence starts on next cycle")
+
+	tickCPUCycles(nes, 7)
+	assert.Equal(t, byte(0xFA), nes.Cpu.registers.Sp)
+}
+
+// TestNes_PPUSTATUS_read_racing_VBlank_suppresses_NMI mirrors vbl_nmi_timing's nmi_suppression:
+// the read is moved one PPU cycle at a time around the cycle VBlank is set.
+func TestNes_PPUSTATUS_read_racing_VBlank_suppresses_NMI(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset int
+		status byte
+		nmi    bool
+	}{
+		{"2 cycles before", -2, 0x00, true},
+		{"1 cycle before", -1, 0x00, false},
+		{"same cycle", 0, 0x80, false},
+		{"1 cycle after", 1, 0x80, false},
+		{"2 cycles after", 2, 0x80, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nes := aNopNes()
+			nes.ppu.PpuControl.GenerateNMIAtVBlank = true
+			nes.bus.Write(0x0100, 0xAD) // LDA $2002
+			nes.bus.Write(0x0101, 0x02)
+			nes.bus.Write(0x0102, 0x20)
+
+			// LDA reads on its 4th CPU cycle, after 9 PPU cycles. With offset 0, the last one sets VBlank on scanline 241 cycle 1.
+			for !(nes.ppu.Scanline() == 240 && int(nes.ppu.RenderCycle()) == 334+tt.offset) {
+				nes.ppu.Tick()
+			}
+			tickCPUCycles(nes, 40)
+
+			assert.Equal(t, tt.status, nes.Cpu.registers.A&0x80)
+			assert.Equal(t, tt.nmi, nes.Cpu.registers.Sp == 0xFA, "NMI serviced")
+		})
+	}
+}
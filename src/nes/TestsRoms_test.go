@@ -8,7 +8,9 @@ import (
 	ppu2 "github.com/raulferras/nes-golang/src/nes/ppu"
 	"github.com/raulferras/nes-golang/src/nes/types"
 	"github.com/raulferras/nes-golang/src/utils"
+	"github.com/stretchr/testify/assert"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -16,10 +18,14 @@ import (
 )
 
 func TestNestest(t *testing.T) {
-	gamePak := gamePak2.CreateGamePakFromROMFile("./../../assets/roms/tests/nestest/nestest.nes")
+	gamePak, err := gamePak2.CreateGamePakFromROMFile("./../../assets/roms/tests/nestest/nestest.nes")
+	if err != nil {
+		t.Fatal(err)
+	}
 	outputLogPath := "./../../var"
 
-	var limitCycles uint32 = 5004
+	// Number of instructions in nestest.log, including the unofficial opcodes section
+	const logInstructions = 8991
 
 	nes := CreateNes(
 		&gamePak,
@@ -28,16 +34,8 @@ func TestNestest(t *testing.T) {
 
 	nes.StartAt(0xC000)
 
-	var i uint32 = 1
-	for {
+	for len(nes.Cpu.debugger.Logger.Snapshots()) < logInstructions {
 		nes.Tick()
-		opCyclesLeft := nes.Cpu.opCyclesLeft
-		if opCyclesLeft == 0 {
-			i++
-		}
-		if limitCycles > 0 && i >= limitCycles {
-			break
-		}
 	}
 
 	nes.Cpu.debugger.Stop()
@@ -82,27 +80,226 @@ func compareLogs(t *testing.T, snapshots []cpu.Snapshot) {
 	}
 }
 
+// cpu_dummy_reads does not report in cartridge RAM. Once done it beeps its result code, which is left on the stack
+// at $01FD (0 means passed), and then loops forever at $E60F-$E617 with NMI disabled.
+const dummyReadsResultAddress = types.Address(0x01FD)
+const dummyReadsHaltStart = types.Address(0xE60F)
+const dummyReadsHaltEnd = types.Address(0xE617)
+const dummyReadsMaxFrames = 300
+
 func TestCPUDummyReads(t *testing.T) {
-	t.Skip()
-	gamePak := gamePak2.CreateGamePakFromROMFile("./../../tests/roms/cpu_dummy_reads.nes")
+	gamePak, err := gamePak2.CreateGamePakFromROMFile("./../../assets/roms/tests/cpu_dummy_reads.nes")
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	nes := CreateNes(&gamePak, &Debugger{})
 	nes.Start()
+
+	for frame := 0; frame < dummyReadsMaxFrames; frame++ {
+		nes.TickTillFrameComplete()
+		pc := nes.Cpu.registers.Pc
+		if pc >= dummyReadsHaltStart && pc <= dummyReadsHaltEnd {
+			assert.Equal(t, byte(0), nes.bus.Peek(dummyReadsResultAddress), "unexpected result code")
+			return
+		}
+	}
+
+	t.Fatalf("cpu_dummy_reads did not finish after %d frames", dummyReadsMaxFrames)
+}
+
+// Blargg's newer test ROMs report their result in cartridge RAM:
+// $6000 holds 0x80 while the test runs and the result code once done (0 means passed),
+// $6001-$6003 hold a signature and $6004 onwards a zero terminated message.
+const blarggStatusAddress = types.Address(0x6000)
+const blarggMessageAddress = types.Address(0x6004)
+const blarggRunning = 0x80
+const blarggMaxFrames = 600
+
+func runBlarggTestROM(t *testing.T, romPath string) {
+	status, message, finished := runBlarggTest(aRunningNes(t, romPath), blarggMaxFrames)
+	if !finished {
+		t.Fatalf("%s did not finish after %d frames", romPath, blarggMaxFrames)
+	}
+
+	assert.Equal(t, byte(0), status, message)
+}
+
+// runBlarggTest runs the console until the rom reports its result code and message at $6000
+func runBlarggTest(nes *Nes, maxFrames int) (status byte, message string, finished bool) {
+	started := false
+	for frame := 0; frame < maxFrames; frame++ {
+		nes.TickTillFrameComplete()
+		if !blarggSignaturePresent(nes) {
+			continue
+		}
+
+		status := nes.bus.Peek(blarggStatusAddress)
+		if status == blarggRunning {
+			started = true
+			continue
+		}
+		if started && status < blarggRunning {
+			return status, blarggMessage(nes), true
+		}
+	}
+
+	return 0, "", false
+}
+
+func blarggSignaturePresent(nes *Nes) bool {
+	return nes.bus.Peek(blarggStatusAddress+1) == 0xDE &&
+		nes.bus.Peek(blarggStatusAddress+2) == 0xB0 &&
+		nes.bus.Peek(blarggStatusAddress+3) == 0x61
+}
+
+func blarggMessage(nes *Nes) string {
+	message := strings.Builder{}
+	for address := blarggMessageAddress; address < 0x8000; address++ {
+		char := nes.bus.Peek(address)
+		if char == 0 {
+			break
+		}
+		message.WriteByte(char)
+	}
+
+	return message.String()
+}
+
+// TestSpriteHitROMs runs blargg's ppu_sprite_hit suite. Its ROMs are not part of the repository,
+// copy them into assets/roms/tests/ppu_sprite_hit to run it.
+func TestSpriteHitROMs(t *testing.T) {
+	roms, _ := filepath.Glob("./../../assets/roms/tests/ppu_sprite_hit/*.nes")
+	if len(roms) == 0 {
+		t.Skip("ppu_sprite_hit ROMs not found in assets/roms/tests/ppu_sprite_hit")
+	}
+
+	for _, rom := range roms {
+		t.Run(filepath.Base(rom), func(t *testing.T) {
+			runBlarggTestROM(t, rom)
+		})
+	}
+}
+
+// Blargg's older test ROMs store their result code in zero page $F0 (1 means passed),
+// and then loop forever on a JMP to itself with NMI disabled.
+const legacyBlarggResultAddress = types.Address(0xF0)
+const legacyBlarggPassed = 1
+const legacyBlarggMaxFrames = 300
+
+// vbl_nmi_timing synchronizes with VBlank over and over, its ROMs run for several seconds each
+const vblNMITimingMaxFrames = 1200
+
+func runLegacyBlarggTestROM(t *testing.T, romPath string, maxFrames int) {
+	gamePak, err := gamePak2.CreateGamePakFromROMFile(romPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nes := CreateNes(&gamePak, &Debugger{})
+	nes.Start()
+
+	for frame := 0; frame < maxFrames; frame++ {
+		nes.TickTillFrameComplete()
+		if legacyBlarggFinished(nes) {
+			assert.Equal(t, byte(legacyBlarggPassed), nes.bus.Peek(legacyBlarggResultAddress), "unexpected result code")
+			return
+		}
+	}
+
+	t.Fatalf("%s did not finish after %d frames", romPath, maxFrames)
+}
+
+func legacyBlarggFinished(nes *Nes) bool {
+	pc := nes.Cpu.registers.Pc
+	jumpsToItself := nes.bus.Peek(pc) == 0x4C &&
+		types.CreateAddress(nes.bus.Peek(pc+1), nes.bus.Peek(pc+2)) == pc
+
+	return jumpsToItself && !nes.ppu.PpuControl.GenerateNMIAtVBlank
+}
+
+func TestPPUBlarggROMs(t *testing.T) {
+	tests := []struct {
+		rom  string
+		skip string
+	}{
+		{"palette_ram.nes", ""},
+		{"vram_access.nes", ""},
+		{"sprite_ram.nes", ""},
+		{"vbl_clear_time.nes", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rom, func(t *testing.T) {
+			if tt.skip != "" {
+				t.Skip(tt.skip)
+			}
+			runLegacyBlarggTestROM(t, "./../../assets/roms/tests/ppu-blargg/"+tt.rom, legacyBlarggMaxFrames)
+		})
+	}
+}
+
+// TestVBlankNMIROMs runs blargg's vbl_nmi_timing suite. Its ROMs are not part of the repository,
+// copy them into assets/roms/tests/vbl_nmi_timing to run it.
+func TestVBlankNMIROMs(t *testing.T) {
+	roms, _ := filepath.Glob("./../../assets/roms/tests/vbl_nmi_timing/*.nes")
+	if len(roms) == 0 {
+		t.Skip("vbl_nmi_timing ROMs not found in assets/roms/tests/vbl_nmi_timing")
+	}
+
+	for _, rom := range roms {
+		t.Run(filepath.Base(rom), func(t *testing.T) {
+			runLegacyBlarggTestROM(t, rom, vblNMITimingMaxFrames)
+		})
+	}
+}
+
+// TestCPUInterruptsROMs runs blargg's cpu_interrupts_v2 suite. Its ROMs are not part of the repository,
+// copy them into assets/roms/tests/cpu_interrupts_v2/rom_singles to run it.
+func TestCPUInterruptsROMs(t *testing.T) {
+	roms, _ := filepath.Glob("./../../assets/roms/tests/cpu_interrupts_v2/rom_singles/*.nes")
+	if len(roms) == 0 {
+		t.Skip("cpu_interrupts_v2 ROMs not found in assets/roms/tests/cpu_interrupts_v2/rom_singles")
+	}
+
+	for _, rom := range roms {
+		t.Run(filepath.Base(rom), func(t *testing.T) {
+			runBlarggTestROM(t, rom)
+		})
+	}
+}
+
+// TestMMC3ROMs runs blargg's mmc3_test_2 suite. Its ROMs are not part of the repository,
+// copy them into assets/roms/tests/mmc3_test_2/rom_singles to run it.
+func TestMMC3ROMs(t *testing.T) {
+	roms, _ := filepath.Glob("./../../assets/roms/tests/mmc3_test_2/rom_singles/*.nes")
+	if len(roms) == 0 {
+		t.Skip("mmc3_test_2 ROMs not found in assets/roms/tests/mmc3_test_2/rom_singles")
+	}
+
+	for _, rom := range roms {
+		t.Run(filepath.Base(rom), func(t *testing.T) {
+			if strings.HasPrefix(filepath.Base(rom), "6-MMC3_alt") {
+				t.Skip("mapper 4 follows MMC3B/C, MMC3_alt checks the older MMC3A reload behaviour")
+			}
+			runBlarggTestROM(t, rom)
+		})
+	}
 }
 
 func CreateSnapshotFromNesTestLine(nesTestLine string) cpu.Snapshot {
 	tokens := strings.Fields(nesTestLine)
 	//_ = opCodeTokens
 
-	blocks := utils.StringSplitByRegex(nesTestLine)
-
-	result := utils.HexStringToByteArray(blocks[0])
+	// nestest.log is laid out in fixed columns:
+	// PC at 0, opcode bytes at 6, mnemonic at 15 (prefixed with "*" for unofficial opcodes), registers at 48
+	result := utils.HexStringToByteArray(nesTestLine[0:4])
 	pc := types.CreateAddress(result[1], result[0])
 
-	opCodeTokens := strings.Fields(blocks[1])
-	opcode := [3]byte{utils.HexStringToByteArray(opCodeTokens[0])[0]}
+	opcode := [3]byte{utils.HexStringToByteArray(nesTestLine[6:8])[0]}
+	mnemonic := strings.TrimPrefix(strings.Fields(nesTestLine[15:48])[0], "*")
 
-	flagFields := strings.Fields(blocks[3])
+	flagFields := strings.Fields(nesTestLine[48:])
 
 	r, _ := regexp.Compile("CYC:([0-9]+)$")
 	cpuCyclesString := r.FindStringSubmatch(nesTestLine)
@@ -120,7 +317,7 @@ func CreateSnapshotFromNesTestLine(nesTestLine string) cpu.Snapshot {
 		},
 		opcode,
 		cpu.CreateInstruction(
-			strings.Fields(blocks[2])[0],
+			mnemonic,
 			cpu.Implicit,
 			nil,
 			0,
@@ -0,0 +1,268 @@
+package apu
+
+import "github.com/raulferras/nes-golang/src/nes/types"
+
+// Apu2a03 is the Audio Processing Unit embedded in the 2A03 CPU.
+// It must be ticked once per CPU cycle, and produces mono samples at the configured sample rate.
+type Apu2a03 struct {
+	pulse1       pulse
+	pulse2       pulse
+	triangle     triangle
+	noise        noise
+	dmc          dmc
+	frameCounter frameCounter
+
+	cycle  uint64 // Lifetime CPU cycles
+	timing Timing
+
+	// Output
+	sampleRate         float64
+	cyclesPerSample    float64
+	sampleCycles       float64 // CPU cycles accumulated towards next sample
+	sampleAccumulator  float32
+	accumulatedSamples int
+	filters            [3]filter
+	samples            []float32
+}
+
+// Samples not drained are dropped after about a second
+const maxBufferedSamples = DefaultSampleRate
+
+func CreateAPU(sampleRate float64) *Apu2a03 {
+	apu := &Apu2a03{
+		pulse1: pulse{onesComplement: true},
+		pulse2: pulse{},
+		noise:  newNoise(),
+		dmc:    newDMC(),
+	}
+	apu.SetTiming(NTSCTiming)
+	apu.SetSampleRate(sampleRate)
+
+	return apu
+}
+
+// DMCSampleRequest tells if the DMC is waiting for the next byte of its sample, and where to read it from.
+// The DMA unit fetches it from the CPU bus, halting the CPU, and hands it over through LoadDMCSample.
+func (apu *Apu2a03) DMCSampleRequest() (types.Address, bool) {
+	return apu.dmc.sampleRequest()
+}
+
+// LoadDMCSample fills the DMC sample buffer with the byte fetched by the DMA unit
+func (apu *Apu2a03) LoadDMCSample(value byte) {
+	apu.dmc.loadSample(value)
+}
+
+// SetSampleRate changes the rate, in Hz, at which the APU outputs samples
+func (apu *Apu2a03) SetSampleRate(sampleRate float64) {
+	apu.sampleRate = sampleRate
+	apu.cyclesPerSample = apu.timing.CPUFrequency / sampleRate
+	apu.filters = [3]filter{
+		newHighPassFilter(sampleRate, 90),
+		newHighPassFilter(sampleRate, 440),
+		newLowPassFilter(sampleRate, 14000),
+	}
+}
+
+// SetTiming adapts the APU to the CPU clock and period tables of a console region
+func (apu *Apu2a03) SetTiming(timing Timing) {
+	apu.timing = timing
+	apu.noise.periods = timing.noisePeriods
+	apu.dmc.rates = timing.dmcRates
+	apu.frameCounter.steps = timing.frameSteps
+	if apu.sampleRate != 0 {
+		apu.SetSampleRate(apu.sampleRate)
+	}
+}
+
+func (apu *Apu2a03) Timing() Timing {
+	return apu.timing
+}
+
+func (apu *Apu2a03) SampleRate() float64 {
+	return apu.sampleRate
+}
+
+// Tick runs one CPU cycle
+func (apu *Apu2a03) Tick() {
+	apu.triangle.clockTimer()
+	apu.noise.clockTimer()
+	apu.dmc.clockTimer()
+	if apu.cycle%2 == 1 {
+		apu.pulse1.clockTimer()
+		apu.pulse2.clockTimer()
+	}
+
+	events := apu.frameCounter.clock()
+	if events.quarter {
+		apu.clockQuarterFrame()
+	}
+	if events.half {
+		apu.clockHalfFrame()
+	}
+
+	apu.generateSample()
+	apu.cycle++
+}
+
+// IRQ tells if the frame counter or the DMC are asserting the CPU IRQ line
+func (apu *Apu2a03) IRQ() bool {
+	return apu.FrameIRQ() || apu.DMCIRQ()
+}
+
+// FrameIRQ tells if the frame counter is asserting the CPU IRQ line. Reading 0x4015 acknowledges it.
+func (apu *Apu2a03) FrameIRQ() bool {
+	return apu.frameCounter.irq
+}
+
+// DMCIRQ tells if the DMC is asserting the CPU IRQ line. Writing 0x4015 acknowledges it.
+func (apu *Apu2a03) DMCIRQ() bool {
+	return apu.dmc.irq
+}
+
+// DrainSamples returns the samples generated since the last call
+func (apu *Apu2a03) DrainSamples() []float32 {
+	samples := apu.samples
+	apu.samples = make([]float32, 0, len(samples))
+
+	return samples
+}
+
+func (apu *Apu2a03) ReadRegister(address types.Address) byte {
+	if address != STATUS {
+		return 0
+	}
+
+	status := apu.PeekStatus()
+	apu.frameCounter.irq = false
+
+	return status
+}
+
+// PeekStatus reads 0x4015 without acknowledging the frame IRQ
+//
+//	IF-D NT21  DMC IRQ, frame IRQ, DMC active, length counter > 0 for noise, triangle, pulse 2 and pulse 1
+func (apu *Apu2a03) PeekStatus() byte {
+	status := byte(0)
+	if apu.pulse1.lengthCounter.active() {
+		status |= 0x01
+	}
+	if apu.pulse2.lengthCounter.active() {
+		status |= 0x02
+	}
+	if apu.triangle.lengthCounter.active() {
+		status |= 0x04
+	}
+	if apu.noise.lengthCounter.active() {
+		status |= 0x08
+	}
+	if apu.dmc.bytesRemaining > 0 {
+		status |= 0x10
+	}
+	if apu.frameCounter.irq {
+		status |= 0x40
+	}
+	if apu.dmc.irq {
+		status |= 0x80
+	}
+
+	return status
+}
+
+func (apu *Apu2a03) WriteRegister(address types.Address, value byte) {
+	switch address {
+	case PULSE1_CONTROL:
+		apu.pulse1.writeControl(value)
+	case PULSE1_SWEEP:
+		apu.pulse1.writeSweep(value)
+	case PULSE1_TIMER_LOW:
+		apu.pulse1.writeTimerLow(value)
+	case PULSE1_TIMER_HIGH:
+		apu.pulse1.writeTimerHigh(value)
+	case PULSE2_CONTROL:
+		apu.pulse2.writeControl(value)
+	case PULSE2_SWEEP:
+		apu.pulse2.writeSweep(value)
+	case PULSE2_TIMER_LOW:
+		apu.pulse2.writeTimerLow(value)
+	case PULSE2_TIMER_HIGH:
+		apu.pulse2.writeTimerHigh(value)
+	case TRIANGLE_CONTROL:
+		apu.triangle.writeControl(value)
+	case TRIANGLE_TIMER_LOW:
+		apu.triangle.writeTimerLow(value)
+	case TRIANGLE_TIMER_HIGH:
+		apu.triangle.writeTimerHigh(value)
+	case NOISE_CONTROL:
+		apu.noise.writeControl(value)
+	case NOISE_PERIOD:
+		apu.noise.writePeriod(value)
+	case NOISE_LENGTH:
+		apu.noise.writeLength(value)
+	case DMC_CONTROL:
+		apu.dmc.writeControl(value)
+	case DMC_LOAD:
+		apu.dmc.writeLoad(value)
+	case DMC_ADDRESS:
+		apu.dmc.writeAddress(value)
+	case DMC_LENGTH:
+		apu.dmc.writeLength(value)
+	case STATUS:
+		apu.pulse1.lengthCounter.setEnabled(value&0x01 == 0x01)
+		apu.pulse2.lengthCounter.setEnabled(value&0x02 == 0x02)
+		apu.triangle.lengthCounter.setEnabled(value&0x04 == 0x04)
+		apu.noise.lengthCounter.setEnabled(value&0x08 == 0x08)
+		apu.dmc.setEnabled(value&0x10 == 0x10)
+		apu.dmc.irq = false
+	case FRAME_COUNTER:
+		apu.frameCounter.write(value, apu.cycle%2 == 1)
+	}
+}
+
+func (apu *Apu2a03) clockQuarterFrame() {
+	apu.pulse1.envelope.clock()
+	apu.pulse2.envelope.clock()
+	apu.noise.envelope.clock()
+	apu.triangle.clockLinearCounter()
+}
+
+func (apu *Apu2a03) clockHalfFrame() {
+	apu.pulse1.lengthCounter.clock()
+	apu.pulse2.lengthCounter.clock()
+	apu.triangle.lengthCounter.clock()
+	apu.noise.lengthCounter.clock()
+	apu.pulse1.clockSweep()
+	apu.pulse2.clockSweep()
+}
+
+// generateSample averages the mixer output over the CPU cycles of each output sample
+func (apu *Apu2a03) generateSample() {
+	apu.sampleAccumulator += apu.output()
+	apu.accumulatedSamples++
+	apu.sampleCycles++
+	if apu.sampleCycles < apu.cyclesPerSample {
+		return
+	}
+	apu.sampleCycles -= apu.cyclesPerSample
+
+	sample := apu.sampleAccumulator / float32(apu.accumulatedSamples)
+	apu.sampleAccumulator = 0
+	apu.accumulatedSamples = 0
+
+	for i := range apu.filters {
+		sample = apu.filters[i].apply(sample)
+	}
+
+	if len(apu.samples) < maxBufferedSamples {
+		apu.samples = append(apu.samples, sample)
+	}
+}
+
+func (apu *Apu2a03) output() float32 {
+	return mix(
+		apu.pulse1.output(),
+		apu.pulse2.output(),
+		apu.triangle.output(),
+		apu.noise.output(),
+		apu.dmc.output(),
+	)
+}
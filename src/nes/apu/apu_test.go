@@ -0,0 +1,203 @@
+package apu
+
+import (
+	"bytes"
+	"github.com/raulferras/nes-golang/src/nes/savestate"
+	"github.com/raulferras/nes-golang/src/nes/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type fakeMemory struct {
+	data  map[types.Address]byte
+	reads []types.Address
+}
+
+func (m *fakeMemory) Read(address types.Address) byte {
+	m.reads = append(m.reads, address)
+	return m.data[address]
+}
+
+// serveDMC does the job of the DMA unit, handing the DMC its next sample byte when requested
+func (m *fakeMemory) serveDMC(d *dmc) {
+	if address, requested := d.sampleRequest(); requested {
+		d.loadSample(m.Read(address))
+	}
+}
+
+func tickAPU(apu *Apu2a03, cycles int) {
+	for i := 0; i < cycles; i++ {
+		apu.Tick()
+	}
+}
+
+func TestAPU_status_reports_active_length_counters(t *testing.T) {
+	apu := CreateAPU(DefaultSampleRate)
+	apu.WriteRegister(STATUS, 0x0F)
+
+	apu.WriteRegister(PULSE1_TIMER_HIGH, 0x08)
+	apu.WriteRegister(TRIANGLE_TIMER_HIGH, 0x08)
+	apu.WriteRegister(NOISE_LENGTH, 0x08)
+
+	assert.Equal(t, byte(0x0D), apu.ReadRegister(STATUS))
+}
+
+func TestAPU_length_counter_is_not_loaded_while_channel_is_disabled(t *testing.T) {
+	apu := CreateAPU(DefaultSampleRate)
+
+	apu.WriteRegister(PULSE2_TIMER_HIGH, 0x08)
+	assert.Equal(t, byte(0), apu.ReadRegister(STATUS)&0x02)
+
+	apu.WriteRegister(STATUS, 0x02)
+	apu.WriteRegister(PULSE2_TIMER_HIGH, 0x08)
+	assert.Equal(t, byte(0x02), apu.ReadRegister(STATUS)&0x02)
+
+	apu.WriteRegister(STATUS, 0x00)
+	assert.Equal(t, byte(0), apu.ReadRegister(STATUS)&0x02, "disabling a channel clears its length counter")
+}
+
+func TestAPU_length_counter_is_clocked_twice_per_4_step_sequence(t *testing.T) {
+	apu := CreateAPU(DefaultSampleRate)
+	apu.WriteRegister(STATUS, 0x01)
+	apu.WriteRegister(PULSE1_TIMER_HIGH, 0x18) // Length index 3: 2
+
+	tickAPU(apu, frameCounterStep2)
+	assert.Equal(t, byte(1), apu.pulse1.lengthCounter.value)
+
+	tickAPU(apu, frameCounterStep4-frameCounterStep2)
+	assert.Equal(t, byte(0), apu.pulse1.lengthCounter.value)
+}
+
+func TestAPU_halted_length_counter_is_not_clocked(t *testing.T) {
+	apu := CreateAPU(DefaultSampleRate)
+	apu.WriteRegister(STATUS, 0x01)
+	apu.WriteRegister(PULSE1_CONTROL, 0x20)
+	apu.WriteRegister(PULSE1_TIMER_HIGH, 0x18)
+
+	tickAPU(apu, frameCounterStep4+2)
+
+	assert.Equal(t, byte(2), apu.pulse1.lengthCounter.value)
+}
+
+func TestAPU_frame_irq_in_4_step_mode(t *testing.T) {
+	apu := CreateAPU(DefaultSampleRate)
+
+	tickAPU(apu, frameCounterStep4-2)
+	assert.False(t, apu.IRQ())
+
+	tickAPU(apu, 1)
+	assert.True(t, apu.IRQ())
+	assert.Equal(t, byte(0x40), apu.PeekStatus()&0x40)
+
+	apu.ReadRegister(STATUS)
+	assert.False(t, apu.IRQ(), "reading status acknowledges frame irq")
+}
+
+func TestAPU_frame_irq_can_be_inhibited(t *testing.T) {
+	apu := CreateAPU(DefaultSampleRate)
+	apu.WriteRegister(FRAME_COUNTER, 0x40)
+
+	tickAPU(apu, frameCounterStep4*2)
+
+	assert.False(t, apu.IRQ())
+}
+
+func TestAPU_5_step_mode_has_no_irq_and_clocks_units_on_write(t *testing.T) {
+	apu := CreateAPU(DefaultSampleRate)
+	apu.WriteRegister(STATUS, 0x01)
+	apu.WriteRegister(PULSE1_TIMER_HIGH, 0x18)
+
+	apu.WriteRegister(FRAME_COUNTER, 0x80)
+	tickAPU(apu, 3)
+	assert.Equal(t, byte(1), apu.pulse1.lengthCounter.value)
+
+	tickAPU(apu, frameCounterStep5*2)
+	assert.False(t, apu.IRQ())
+}
+
+func TestAPU_outputs_samples_at_configured_rate(t *testing.T) {
+	apu := CreateAPU(48000)
+
+	tickAPU(apu, CPU_FREQUENCY/10)
+
+	assert.InDelta(t, 4800, len(apu.DrainSamples()), 1)
+	assert.Equal(t, 0, len(apu.DrainSamples()))
+}
+
+func TestAPU_dmc_irq_is_reported_in_status(t *testing.T) {
+	memory := &fakeMemory{data: map[types.Address]byte{}}
+	apu := CreateAPU(DefaultSampleRate)
+	apu.WriteRegister(FRAME_COUNTER, 0x40)
+	apu.WriteRegister(DMC_CONTROL, 0x8F)
+	apu.WriteRegister(DMC_LENGTH, 0x00)
+	apu.WriteRegister(STATUS, 0x10)
+
+	memory.serveDMC(&apu.dmc)
+	tickAPU(apu, 1)
+
+	assert.True(t, apu.IRQ())
+	assert.Equal(t, byte(0x80), apu.ReadRegister(STATUS))
+
+	apu.WriteRegister(STATUS, 0x00)
+	assert.False(t, apu.IRQ(), "writing status acknowledges dmc irq")
+}
+
+func TestMixer(t *testing.T) {
+	assert.Equal(t, float32(0), mix(0, 0, 0, 0, 0))
+	assert.InDelta(t, 0.2585, mix(15, 15, 0, 0, 0), 0.001)
+	assert.InDelta(t, 0.7415, mix(0, 0, 15, 15, 127), 0.001)
+}
+
+func TestAPU_PAL_timing_uses_PAL_periods(t *testing.T) {
+	apu := CreateAPU(DefaultSampleRate)
+	apu.SetTiming(PALTiming)
+
+	apu.WriteRegister(NOISE_PERIOD, 0x0F)
+	apu.WriteRegister(DMC_CONTROL, 0x00)
+
+	assert.Equal(t, uint16(3778), apu.noise.timerPeriod)
+	assert.Equal(t, uint16(398), apu.dmc.timerPeriod)
+}
+
+func TestAPU_PAL_frame_irq_in_4_step_mode(t *testing.T) {
+	apu := CreateAPU(DefaultSampleRate)
+	apu.SetTiming(PALTiming)
+
+	tickAPU(apu, int(palFrameSteps[3])-2)
+	assert.False(t, apu.IRQ())
+
+	tickAPU(apu, 1)
+	assert.True(t, apu.IRQ())
+}
+
+func TestAPU_sample_rate_follows_CPU_frequency(t *testing.T) {
+	apu := CreateAPU(DefaultSampleRate)
+	apu.SetTiming(PALTiming)
+
+	assert.Equal(t, float64(PAL_CPU_FREQUENCY)/DefaultSampleRate, apu.cyclesPerSample)
+}
+
+func TestAPU_state_keeps_channels_and_frame_counter(t *testing.T) {
+	apu := CreateAPU(DefaultSampleRate)
+	apu.WriteRegister(STATUS, 0x0F)
+	apu.WriteRegister(PULSE1_TIMER_HIGH, 0x08)
+	apu.WriteRegister(TRIANGLE_TIMER_HIGH, 0x08)
+	tickAPU(apu, 1000)
+
+	state := bytes.Buffer{}
+	apu.SyncState(savestate.NewSaver(&state))
+
+	loaded := CreateAPU(DefaultSampleRate)
+	loader := savestate.NewLoader(&state)
+	loaded.SyncState(loader)
+
+	assert.NoError(t, loader.Err())
+	assert.Equal(t, apu.pulse1, loaded.pulse1)
+	assert.Equal(t, apu.triangle, loaded.triangle)
+	assert.Equal(t, apu.frameCounter, loaded.frameCounter)
+
+	tickAPU(apu, frameCounterStep4)
+	tickAPU(loaded, frameCounterStep4)
+	assert.Equal(t, apu.IRQ(), loaded.IRQ())
+	assert.Equal(t, apu.ReadRegister(STATUS), loaded.ReadRegister(STATUS))
+}
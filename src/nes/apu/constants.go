@@ -0,0 +1,70 @@
+package apu
+
+import "github.com/raulferras/nes-golang/src/nes/types"
+
+const APU_LOW_ADDRESS = types.Address(0x4000)
+const APU_HIGH_ADDRESS = types.Address(0x4017)
+
+// Registers
+const PULSE1_CONTROL = types.Address(0x4000)
+const PULSE1_SWEEP = types.Address(0x4001)
+const PULSE1_TIMER_LOW = types.Address(0x4002)
+const PULSE1_TIMER_HIGH = types.Address(0x4003)
+const PULSE2_CONTROL = types.Address(0x4004)
+const PULSE2_SWEEP = types.Address(0x4005)
+const PULSE2_TIMER_LOW = types.Address(0x4006)
+const PULSE2_TIMER_HIGH = types.Address(0x4007)
+const TRIANGLE_CONTROL = types.Address(0x4008)
+const TRIANGLE_TIMER_LOW = types.Address(0x400A)
+const TRIANGLE_TIMER_HIGH = types.Address(0x400B)
+const NOISE_CONTROL = types.Address(0x400C)
+const NOISE_PERIOD = types.Address(0x400E)
+const NOISE_LENGTH = types.Address(0x400F)
+const DMC_CONTROL = types.Address(0x4010)
+const DMC_LOAD = types.Address(0x4011)
+const DMC_ADDRESS = types.Address(0x4012)
+const DMC_LENGTH = types.Address(0x4013)
+const STATUS = types.Address(0x4015)
+const FRAME_COUNTER = types.Address(0x4017)
+
+// CPU clock rate of a NTSC console, in Hz
+const CPU_FREQUENCY = 1789773
+
+// CPU clock rates of PAL and Dendy consoles, in Hz
+const PAL_CPU_FREQUENCY = 1662607
+const DENDY_CPU_FREQUENCY = 1773448
+
+const DefaultSampleRate = 44100
+
+var lengthTable = [32]byte{
+	10, 254, 20, 2, 40, 4, 80, 6, 160, 8, 60, 10, 14, 12, 26, 14,
+	12, 16, 24, 18, 48, 20, 96, 22, 192, 24, 72, 26, 16, 28, 32, 30,
+}
+
+var dutyTable = [4][8]byte{
+	{0, 1, 0, 0, 0, 0, 0, 0}, // 12.5%
+	{0, 1, 1, 0, 0, 0, 0, 0}, // 25%
+	{0, 1, 1, 1, 1, 0, 0, 0}, // 50%
+	{1, 0, 0, 1, 1, 1, 1, 1}, // 25% negated
+}
+
+var triangleSequence = [32]byte{
+	15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0,
+	0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+}
+
+// Noise timer periods, in CPU cycles
+var noisePeriodTable = [16]uint16{
+	4, 8, 16, 32, 64, 96, 128, 160, 202, 254, 380, 508, 762, 1016, 2034, 4068,
+}
+var palNoisePeriodTable = [16]uint16{
+	4, 8, 14, 30, 60, 88, 118, 148, 188, 236, 354, 472, 708, 944, 1890, 3778,
+}
+
+// DMC output rates, in CPU cycles
+var dmcRateTable = [16]uint16{
+	428, 380, 340, 320, 286, 254, 226, 214, 190, 160, 142, 128, 106, 84, 72, 54,
+}
+var palDMCRateTable = [16]uint16{
+	398, 354, 316, 298, 276, 236, 210, 198, 176, 148, 132, 118, 98, 78, 66, 50,
+}
@@ -0,0 +1,139 @@
+package apu
+
+import "github.com/raulferras/nes-golang/src/nes/types"
+
+// dmc plays 1 bit delta encoded samples fetched from CPU memory, or raw 7 bit values written into 0x4011.
+//
+//	0x4010: IL-- RRRR  IRQ enabled, loop, rate index
+//	0x4011: -DDD DDDD  Direct load of the output level
+//	0x4012: AAAA AAAA  Sample address, 0xC000 + A * 64
+//	0x4013: LLLL LLLL  Sample length, L * 16 + 1 bytes
+type dmc struct {
+	irqEnabled bool
+	irq        bool
+	loop       bool
+
+	timer       uint16
+	timerPeriod uint16
+	rates       *[16]uint16
+	level       byte
+
+	sampleAddress  types.Address
+	sampleLength   uint16
+	currentAddress types.Address
+	bytesRemaining uint16
+
+	sampleBuffer      byte
+	sampleBufferEmpty bool
+
+	shift         byte
+	bitsRemaining byte
+	silence       bool
+}
+
+func newDMC() dmc {
+	return dmc{
+		timerPeriod:       dmcRateTable[0],
+		rates:             &dmcRateTable,
+		sampleBufferEmpty: true,
+		bitsRemaining:     8,
+		silence:           true,
+	}
+}
+
+func (d *dmc) writeControl(value byte) {
+	d.irqEnabled = value&0x80 == 0x80
+	d.loop = value&0x40 == 0x40
+	d.timerPeriod = d.rates[value&0x0F]
+	if !d.irqEnabled {
+		d.irq = false
+	}
+}
+
+func (d *dmc) writeLoad(value byte) {
+	d.level = value & 0x7F
+}
+
+func (d *dmc) writeAddress(value byte) {
+	d.sampleAddress = 0xC000 | types.Address(value)<<6
+}
+
+func (d *dmc) writeLength(value byte) {
+	d.sampleLength = uint16(value)<<4 | 1
+}
+
+func (d *dmc) setEnabled(enabled bool) {
+	if !enabled {
+		d.bytesRemaining = 0
+		return
+	}
+
+	if d.bytesRemaining == 0 {
+		d.restart()
+	}
+}
+
+func (d *dmc) restart() {
+	d.currentAddress = d.sampleAddress
+	d.bytesRemaining = d.sampleLength
+}
+
+// clockTimer is called every CPU cycle
+func (d *dmc) clockTimer() {
+	if d.timer > 0 {
+		d.timer--
+		return
+	}
+	d.timer = d.timerPeriod - 1
+
+	if !d.silence {
+		if d.shift&0x01 == 0x01 {
+			if d.level <= 125 {
+				d.level += 2
+			}
+		} else if d.level >= 2 {
+			d.level -= 2
+		}
+	}
+	d.shift >>= 1
+
+	d.bitsRemaining--
+	if d.bitsRemaining == 0 {
+		d.bitsRemaining = 8
+		d.silence = d.sampleBufferEmpty
+		if !d.sampleBufferEmpty {
+			d.shift = d.sampleBuffer
+			d.sampleBufferEmpty = true
+		}
+	}
+}
+
+// sampleRequest tells if the sample buffer is waiting for the next byte of the sample, and its address
+func (d *dmc) sampleRequest() (types.Address, bool) {
+	return d.currentAddress, d.sampleBufferEmpty && d.bytesRemaining > 0
+}
+
+// loadSample fills the sample buffer with the byte fetched by DMA
+func (d *dmc) loadSample(value byte) {
+	d.sampleBuffer = value
+	d.sampleBufferEmpty = false
+
+	if d.currentAddress == 0xFFFF {
+		d.currentAddress = 0x8000
+	} else {
+		d.currentAddress++
+	}
+
+	d.bytesRemaining--
+	if d.bytesRemaining == 0 {
+		if d.loop {
+			d.restart()
+		} else if d.irqEnabled {
+			d.irq = true
+		}
+	}
+}
+
+func (d *dmc) output() byte {
+	return d.level
+}
@@ -0,0 +1,65 @@
+package apu
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDMC_fetches_sample_bytes_from_memory(t *testing.T) {
+	memory := &fakeMemory{data: map[types.Address]byte{0xC040: 0xFF, 0xC041: 0x00}}
+	d := newDMC()
+	d.writeAddress(0x01)
+	d.writeLength(0x01) // 17 bytes
+	d.setEnabled(true)
+
+	memory.serveDMC(&d)
+
+	assert.Equal(t, []types.Address{0xC040}, memory.reads)
+	assert.Equal(t, uint16(16), d.bytesRemaining)
+}
+
+func TestDMC_output_level_follows_delta_bits(t *testing.T) {
+	memory := &fakeMemory{data: map[types.Address]byte{0xC000: 0xFF}}
+	d := newDMC()
+	d.writeControl(0x0F) // fastest rate
+	d.writeLoad(0x40)
+	d.writeAddress(0x00)
+	d.writeLength(0x00)
+	d.setEnabled(true)
+
+	// Output unit stays silent for 8 bits until the first sample byte reaches the shift register
+	for i := 0; i < 16*int(dmcRateTable[0x0F]); i++ {
+		memory.serveDMC(&d)
+		d.clockTimer()
+	}
+
+	assert.Equal(t, byte(0x40+2*8), d.output())
+}
+
+func TestDMC_address_wraps_to_0x8000(t *testing.T) {
+	memory := &fakeMemory{data: map[types.Address]byte{}}
+	d := newDMC()
+	d.sampleLength = 2
+	d.sampleAddress = 0xFFFF
+	d.setEnabled(true)
+
+	memory.serveDMC(&d)
+	d.sampleBufferEmpty = true
+	memory.serveDMC(&d)
+
+	assert.Equal(t, []types.Address{0xFFFF, 0x8000}, memory.reads)
+}
+
+func TestDMC_loops_sample_instead_of_raising_irq(t *testing.T) {
+	memory := &fakeMemory{data: map[types.Address]byte{}}
+	d := newDMC()
+	d.writeControl(0xC0)
+	d.writeLength(0x00)
+	d.setEnabled(true)
+
+	memory.serveDMC(&d)
+
+	assert.False(t, d.irq)
+	assert.Equal(t, uint16(1), d.bytesRemaining)
+}
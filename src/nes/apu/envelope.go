@@ -0,0 +1,79 @@
+package apu
+
+// envelope generates a decaying volume, or a constant one, for pulse and noise channels.
+// Clocked by the frame counter on every quarter frame.
+type envelope struct {
+	start    bool
+	loop     bool // Shared with the length counter halt flag
+	constant bool
+	volume   byte // Constant volume, and also the divider period
+	divider  byte
+	decay    byte
+}
+
+// write handles the --LC VVVV part of a channel control register
+func (e *envelope) write(value byte) {
+	e.loop = value&0x20 == 0x20
+	e.constant = value&0x10 == 0x10
+	e.volume = value & 0x0F
+}
+
+func (e *envelope) clock() {
+	if e.start {
+		e.start = false
+		e.decay = 15
+		e.divider = e.volume
+		return
+	}
+
+	if e.divider > 0 {
+		e.divider--
+		return
+	}
+
+	e.divider = e.volume
+	if e.decay > 0 {
+		e.decay--
+	} else if e.loop {
+		e.decay = 15
+	}
+}
+
+func (e *envelope) output() byte {
+	if e.constant {
+		return e.volume
+	}
+
+	return e.decay
+}
+
+// lengthCounter silences a channel once it counts down to 0.
+// Clocked by the frame counter on every half frame.
+type lengthCounter struct {
+	enabled bool
+	halt    bool
+	value   byte
+}
+
+func (l *lengthCounter) load(index byte) {
+	if l.enabled {
+		l.value = lengthTable[index&0x1F]
+	}
+}
+
+func (l *lengthCounter) setEnabled(enabled bool) {
+	l.enabled = enabled
+	if !enabled {
+		l.value = 0
+	}
+}
+
+func (l *lengthCounter) clock() {
+	if l.value > 0 && !l.halt {
+		l.value--
+	}
+}
+
+func (l *lengthCounter) active() bool {
+	return l.value > 0
+}
@@ -0,0 +1,104 @@
+package apu
+
+// frameCounter clocks envelopes, linear counter, length counters and sweeps at ~240Hz,
+// and can raise an IRQ at the end of each sequence.
+//
+//	0x4017: MI-- ----  Mode (0: 4-step, 1: 5-step), IRQ inhibit
+//
+//	4-step: Quarter  Half+Quarter  Quarter  Half+Quarter+IRQ
+//	5-step: Quarter  Half+Quarter  Quarter  -  Half+Quarter
+type frameCounter struct {
+	fiveStep   bool
+	irqInhibit bool
+	irq        bool
+	cycle      uint32 // CPU cycles since the sequence started
+	steps      frameSteps
+
+	// Writes to 0x4017 reset the sequence 3 or 4 CPU cycles later
+	resetDelay byte
+}
+
+// Step timings, in CPU cycles
+const frameCounterStep1 = 7457
+const frameCounterStep2 = 14913
+const frameCounterStep3 = 22371
+const frameCounterStep4 = 29829
+const frameCounterStep5 = 37281
+
+type frameSteps [5]uint32
+
+var ntscFrameSteps = frameSteps{frameCounterStep1, frameCounterStep2, frameCounterStep3, frameCounterStep4, frameCounterStep5}
+var palFrameSteps = frameSteps{8313, 16627, 24939, 33253, 41565}
+
+type frameEvents struct {
+	quarter bool
+	half    bool
+}
+
+func (f *frameCounter) write(value byte, oddCycle bool) {
+	f.fiveStep = value&0x80 == 0x80
+	f.irqInhibit = value&0x40 == 0x40
+	if f.irqInhibit {
+		f.irq = false
+	}
+
+	if oddCycle {
+		f.resetDelay = 4
+	} else {
+		f.resetDelay = 3
+	}
+}
+
+// clock is called every CPU cycle and tells which units the APU has to clock
+func (f *frameCounter) clock() frameEvents {
+	events := frameEvents{}
+
+	if f.resetDelay > 0 {
+		f.resetDelay--
+		if f.resetDelay == 0 {
+			f.cycle = 0
+			if f.fiveStep {
+				// Entering 5-step mode clocks all units immediately
+				return frameEvents{quarter: true, half: true}
+			}
+			return events
+		}
+	}
+
+	f.cycle++
+	switch f.cycle {
+	case f.steps[0], f.steps[2]:
+		events.quarter = true
+	case f.steps[1]:
+		events.quarter = true
+		events.half = true
+	case f.steps[3] - 1:
+		if !f.fiveStep {
+			f.raiseIRQ()
+		}
+	case f.steps[3]:
+		if !f.fiveStep {
+			events.quarter = true
+			events.half = true
+			f.raiseIRQ()
+		}
+	case f.steps[3] + 1:
+		if !f.fiveStep {
+			f.raiseIRQ()
+			f.cycle = 0
+		}
+	case f.steps[4]:
+		events.quarter = true
+		events.half = true
+	case f.steps[4] + 1:
+		f.cycle = 0
+	}
+
+	return events
+}
+
+func (f *frameCounter) raiseIRQ() {
+	if !f.irqInhibit {
+		f.irq = true
+	}
+}
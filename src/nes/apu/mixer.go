@@ -0,0 +1,56 @@
+package apu
+
+import "math"
+
+// The APU mixes its channels with a nonlinear DAC. Lookup tables from the nesdev wiki approximation:
+//
+//	pulse_out = 95.52 / (8128.0 / (pulse1 + pulse2) + 100)
+//	tnd_out   = 163.67 / (24329.0 / (3 * triangle + 2 * noise + dmc) + 100)
+var pulseTable [31]float32
+var tndTable [203]float32
+
+func init() {
+	for i := 1; i < len(pulseTable); i++ {
+		pulseTable[i] = float32(95.52 / (8128.0/float64(i) + 100))
+	}
+	for i := 1; i < len(tndTable); i++ {
+		tndTable[i] = float32(163.67 / (24329.0/float64(i) + 100))
+	}
+}
+
+func mix(pulse1 byte, pulse2 byte, triangle byte, noise byte, dmc byte) float32 {
+	return pulseTable[pulse1+pulse2] + tndTable[3*int(triangle)+2*int(noise)+int(dmc)]
+}
+
+// filter is a first order IIR filter, like the RC filters found in the NES audio path
+type filter struct {
+	highPass  bool
+	alpha     float32
+	lastInput float32
+	output    float32
+}
+
+func newHighPassFilter(sampleRate float64, cutoff float64) filter {
+	rc := 1 / (2 * math.Pi * cutoff)
+	dt := 1 / sampleRate
+
+	return filter{highPass: true, alpha: float32(rc / (rc + dt))}
+}
+
+func newLowPassFilter(sampleRate float64, cutoff float64) filter {
+	rc := 1 / (2 * math.Pi * cutoff)
+	dt := 1 / sampleRate
+
+	return filter{alpha: float32(dt / (rc + dt))}
+}
+
+func (f *filter) apply(input float32) float32 {
+	if f.highPass {
+		f.output = f.alpha * (f.output + input - f.lastInput)
+	} else {
+		f.output += f.alpha * (input - f.output)
+	}
+	f.lastInput = input
+
+	return f.output
+}
@@ -0,0 +1,60 @@
+package apu
+
+// noise generates pseudo-random noise from a 15 bit linear feedback shift register.
+//
+//	0x400C: --LC VVVV  Length counter halt / envelope loop, constant volume, volume
+//	0x400E: M--- PPPP  Mode, period index
+//	0x400F: LLLL L---  Length counter load
+type noise struct {
+	envelope      envelope
+	lengthCounter lengthCounter
+
+	mode        bool // Short mode takes the feedback from bit 6 instead of bit 1
+	timer       uint16
+	timerPeriod uint16
+	shift       uint16
+	periods     *[16]uint16
+}
+
+func newNoise() noise {
+	return noise{shift: 1, timerPeriod: noisePeriodTable[0], periods: &noisePeriodTable}
+}
+
+func (n *noise) writeControl(value byte) {
+	n.lengthCounter.halt = value&0x20 == 0x20
+	n.envelope.write(value)
+}
+
+func (n *noise) writePeriod(value byte) {
+	n.mode = value&0x80 == 0x80
+	n.timerPeriod = n.periods[value&0x0F]
+}
+
+func (n *noise) writeLength(value byte) {
+	n.lengthCounter.load(value >> 3)
+	n.envelope.start = true
+}
+
+// clockTimer is called every CPU cycle
+func (n *noise) clockTimer() {
+	if n.timer > 0 {
+		n.timer--
+		return
+	}
+
+	n.timer = n.timerPeriod - 1
+	feedbackBit := uint16(1)
+	if n.mode {
+		feedbackBit = 6
+	}
+	feedback := (n.shift & 0x01) ^ ((n.shift >> feedbackBit) & 0x01)
+	n.shift = (n.shift >> 1) | (feedback << 14)
+}
+
+func (n *noise) output() byte {
+	if n.shift&0x01 == 0x01 || !n.lengthCounter.active() {
+		return 0
+	}
+
+	return n.envelope.output()
+}
@@ -0,0 +1,32 @@
+package apu
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNoise_shift_register_feedback(t *testing.T) {
+	n := newNoise()
+	n.writePeriod(0x00)
+
+	n.clockTimer()
+	assert.Equal(t, uint16(0x4000), n.shift, "bit 0 xor bit 1 is fed into bit 14")
+
+	short := newNoise()
+	short.writePeriod(0x80)
+	short.shift = 0x41
+	short.clockTimer()
+	assert.Equal(t, uint16(0x20), short.shift, "short mode feeds back from bit 6")
+}
+
+func TestNoise_is_silent_while_bit_0_is_set(t *testing.T) {
+	n := newNoise()
+	n.lengthCounter.enabled = true
+	n.writeControl(0x1F)
+	n.writeLength(0x08)
+
+	assert.Equal(t, byte(0), n.output())
+
+	n.shift = 0x02
+	assert.Equal(t, byte(15), n.output())
+}
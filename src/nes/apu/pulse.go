@@ -0,0 +1,104 @@
+package apu
+
+// pulse generates a square wave.
+//
+//	0x4000 / 0x4004: DDLC VVVV  Duty, length counter halt / envelope loop, constant volume, volume
+//	0x4001 / 0x4005: EPPP NSSS  Sweep enabled, period, negate, shift
+//	0x4002 / 0x4006: TTTT TTTT  Timer low
+//	0x4003 / 0x4007: LLLL LTTT  Length counter load, timer high
+type pulse struct {
+	// Pulse 1 negates the sweep adjustment with ones' complement, Pulse 2 with two's complement
+	onesComplement bool
+
+	envelope      envelope
+	lengthCounter lengthCounter
+
+	duty         byte
+	dutyPosition byte
+	timer        uint16
+	timerPeriod  uint16
+
+	sweepEnabled bool
+	sweepPeriod  byte
+	sweepNegate  bool
+	sweepShift   byte
+	sweepDivider byte
+	sweepReload  bool
+}
+
+func (p *pulse) writeControl(value byte) {
+	p.duty = value >> 6
+	p.lengthCounter.halt = value&0x20 == 0x20
+	p.envelope.write(value)
+}
+
+func (p *pulse) writeSweep(value byte) {
+	p.sweepEnabled = value&0x80 == 0x80
+	p.sweepPeriod = (value >> 4) & 0x07
+	p.sweepNegate = value&0x08 == 0x08
+	p.sweepShift = value & 0x07
+	p.sweepReload = true
+}
+
+func (p *pulse) writeTimerLow(value byte) {
+	p.timerPeriod = (p.timerPeriod & 0x0700) | uint16(value)
+}
+
+func (p *pulse) writeTimerHigh(value byte) {
+	p.timerPeriod = (p.timerPeriod & 0x00FF) | uint16(value&0x07)<<8
+	p.lengthCounter.load(value >> 3)
+	p.dutyPosition = 0
+	p.envelope.start = true
+}
+
+// clockTimer is called every APU cycle (two CPU cycles)
+func (p *pulse) clockTimer() {
+	if p.timer == 0 {
+		p.timer = p.timerPeriod
+		p.dutyPosition = (p.dutyPosition + 1) & 0x07
+	} else {
+		p.timer--
+	}
+}
+
+func (p *pulse) clockSweep() {
+	if p.sweepDivider == 0 && p.sweepEnabled && p.sweepShift > 0 && !p.sweepMuting() {
+		p.timerPeriod = p.sweepTargetPeriod()
+	}
+
+	if p.sweepDivider == 0 || p.sweepReload {
+		p.sweepDivider = p.sweepPeriod
+		p.sweepReload = false
+	} else {
+		p.sweepDivider--
+	}
+}
+
+func (p *pulse) sweepTargetPeriod() uint16 {
+	change := p.timerPeriod >> p.sweepShift
+	if !p.sweepNegate {
+		return p.timerPeriod + change
+	}
+
+	if p.onesComplement {
+		change++
+	}
+	if change > p.timerPeriod {
+		return 0
+	}
+
+	return p.timerPeriod - change
+}
+
+// The sweep unit mutes the channel even when it is disabled
+func (p *pulse) sweepMuting() bool {
+	return p.timerPeriod < 8 || p.sweepTargetPeriod() > 0x7FF
+}
+
+func (p *pulse) output() byte {
+	if !p.lengthCounter.active() || p.sweepMuting() || dutyTable[p.duty][p.dutyPosition] == 0 {
+		return 0
+	}
+
+	return p.envelope.output()
+}
@@ -0,0 +1,68 @@
+package apu
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPulse_sweep_negate_differs_between_channels(t *testing.T) {
+	pulse1 := pulse{onesComplement: true, timerPeriod: 0x100}
+	pulse2 := pulse{timerPeriod: 0x100}
+	pulse1.writeSweep(0x89)
+	pulse2.writeSweep(0x89)
+
+	assert.Equal(t, uint16(0x7F), pulse1.sweepTargetPeriod())
+	assert.Equal(t, uint16(0x80), pulse2.sweepTargetPeriod())
+}
+
+func TestPulse_sweep_updates_period_on_divider_expiry(t *testing.T) {
+	p := pulse{timerPeriod: 0x100}
+	p.writeSweep(0x91) // period 1, shift 1
+
+	p.clockSweep() // divider starts expired
+	assert.Equal(t, uint16(0x180), p.timerPeriod)
+	p.clockSweep()
+	assert.Equal(t, uint16(0x180), p.timerPeriod)
+	p.clockSweep()
+	assert.Equal(t, uint16(0x240), p.timerPeriod)
+}
+
+func TestPulse_is_muted_by_sweep(t *testing.T) {
+	tests := []struct {
+		name   string
+		period uint16
+		sweep  byte
+	}{
+		{"period under 8", 7, 0x00},
+		{"target overflows", 0x7F0, 0x01},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := pulse{timerPeriod: tt.period}
+			p.lengthCounter.enabled = true
+			p.writeControl(0xDF) // 25% negated duty, constant volume 15
+			p.writeSweep(tt.sweep)
+			p.lengthCounter.value = 10
+
+			assert.True(t, p.sweepMuting())
+			assert.Equal(t, byte(0), p.output())
+		})
+	}
+}
+
+func TestPulse_envelope_decays_and_loops(t *testing.T) {
+	e := envelope{}
+	e.write(0x20) // loop, period 0
+	e.start = true
+
+	e.clock()
+	assert.Equal(t, byte(15), e.output())
+	for i := 0; i < 15; i++ {
+		e.clock()
+	}
+	assert.Equal(t, byte(0), e.output())
+
+	e.clock()
+	assert.Equal(t, byte(15), e.output())
+}
@@ -0,0 +1,57 @@
+package apu
+
+import "github.com/raulferras/nes-golang/src/nes/savestate"
+
+// SyncState saves or loads channels and frame counter. Output filters and pending samples are not part of it.
+func (apu *Apu2a03) SyncState(state *savestate.Stream) {
+	apu.pulse1.syncState(state)
+	apu.pulse2.syncState(state)
+	apu.triangle.syncState(state)
+	apu.noise.syncState(state)
+	apu.dmc.syncState(state)
+	apu.frameCounter.syncState(state)
+	state.Sync(&apu.cycle)
+}
+
+func (p *pulse) syncState(state *savestate.Stream) {
+	p.envelope.syncState(state)
+	p.lengthCounter.syncState(state)
+	state.Sync(
+		&p.duty, &p.dutyPosition, &p.timer, &p.timerPeriod,
+		&p.sweepEnabled, &p.sweepPeriod, &p.sweepNegate, &p.sweepShift, &p.sweepDivider, &p.sweepReload,
+	)
+}
+
+func (t *triangle) syncState(state *savestate.Stream) {
+	t.lengthCounter.syncState(state)
+	state.Sync(
+		&t.control, &t.linearCounter, &t.linearCounterPeriod, &t.linearCounterReload,
+		&t.timer, &t.timerPeriod, &t.step,
+	)
+}
+
+func (n *noise) syncState(state *savestate.Stream) {
+	n.envelope.syncState(state)
+	n.lengthCounter.syncState(state)
+	state.Sync(&n.mode, &n.timer, &n.timerPeriod, &n.shift)
+}
+
+func (d *dmc) syncState(state *savestate.Stream) {
+	state.Sync(
+		&d.irqEnabled, &d.irq, &d.loop, &d.timer, &d.timerPeriod, &d.level,
+		&d.sampleAddress, &d.sampleLength, &d.currentAddress, &d.bytesRemaining,
+		&d.sampleBuffer, &d.sampleBufferEmpty, &d.shift, &d.bitsRemaining, &d.silence,
+	)
+}
+
+func (e *envelope) syncState(state *savestate.Stream) {
+	state.Sync(&e.start, &e.loop, &e.constant, &e.volume, &e.divider, &e.decay)
+}
+
+func (l *lengthCounter) syncState(state *savestate.Stream) {
+	state.Sync(&l.enabled, &l.halt, &l.value)
+}
+
+func (f *frameCounter) syncState(state *savestate.Stream) {
+	state.Sync(&f.fiveStep, &f.irqInhibit, &f.irq, &f.cycle, &f.resetDelay)
+}
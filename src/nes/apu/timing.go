@@ -0,0 +1,32 @@
+package apu
+
+// Timing holds the APU parameters that change between console regions
+type Timing struct {
+	CPUFrequency float64 // CPU clock rate, in Hz
+
+	noisePeriods *[16]uint16
+	dmcRates     *[16]uint16
+	frameSteps   frameSteps
+}
+
+var NTSCTiming = Timing{
+	CPUFrequency: CPU_FREQUENCY,
+	noisePeriods: &noisePeriodTable,
+	dmcRates:     &dmcRateTable,
+	frameSteps:   ntscFrameSteps,
+}
+
+var PALTiming = Timing{
+	CPUFrequency: PAL_CPU_FREQUENCY,
+	noisePeriods: &palNoisePeriodTable,
+	dmcRates:     &palDMCRateTable,
+	frameSteps:   palFrameSteps,
+}
+
+// DendyTiming runs the CPU close to PAL speed, but keeps NTSC APU periods
+var DendyTiming = Timing{
+	CPUFrequency: DENDY_CPU_FREQUENCY,
+	noisePeriods: &noisePeriodTable,
+	dmcRates:     &dmcRateTable,
+	frameSteps:   ntscFrameSteps,
+}
@@ -0,0 +1,69 @@
+package apu
+
+// triangle generates a 32 step triangle wave. It has no volume control.
+//
+//	0x4008: CRRR RRRR  Length counter halt / linear counter control, linear counter reload value
+//	0x400A: TTTT TTTT  Timer low
+//	0x400B: LLLL LTTT  Length counter load, timer high
+type triangle struct {
+	lengthCounter lengthCounter
+
+	control             bool
+	linearCounter       byte
+	linearCounterPeriod byte
+	linearCounterReload bool
+
+	timer       uint16
+	timerPeriod uint16
+	step        byte
+}
+
+func (t *triangle) writeControl(value byte) {
+	t.control = value&0x80 == 0x80
+	t.lengthCounter.halt = t.control
+	t.linearCounterPeriod = value & 0x7F
+}
+
+func (t *triangle) writeTimerLow(value byte) {
+	t.timerPeriod = (t.timerPeriod & 0x0700) | uint16(value)
+}
+
+func (t *triangle) writeTimerHigh(value byte) {
+	t.timerPeriod = (t.timerPeriod & 0x00FF) | uint16(value&0x07)<<8
+	t.lengthCounter.load(value >> 3)
+	t.linearCounterReload = true
+}
+
+// clockTimer is called every CPU cycle
+func (t *triangle) clockTimer() {
+	if t.timer > 0 {
+		t.timer--
+		return
+	}
+
+	t.timer = t.timerPeriod
+	if t.linearCounter > 0 && t.lengthCounter.active() {
+		t.step = (t.step + 1) & 0x1F
+	}
+}
+
+func (t *triangle) clockLinearCounter() {
+	if t.linearCounterReload {
+		t.linearCounter = t.linearCounterPeriod
+	} else if t.linearCounter > 0 {
+		t.linearCounter--
+	}
+
+	if !t.control {
+		t.linearCounterReload = false
+	}
+}
+
+func (t *triangle) output() byte {
+	// Ultrasonic periods are inaudible, and hardware just produces a pop
+	if t.timerPeriod < 2 {
+		return 7
+	}
+
+	return triangleSequence[t.step]
+}
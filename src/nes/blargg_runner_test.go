@@ -0,0 +1,85 @@
+package nes
+
+import (
+	gamePak2 "github.com/raulferras/nes-golang/src/nes/gamePak"
+	"github.com/raulferras/nes-golang/src/nes/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// blarggProtocolROM assembles an NROM program reporting through the $6000 protocol of blargg's test ROMs:
+// it writes the signature and the running status, waits for 3 VBlanks, then writes message and result.
+func blarggProtocolROM(result byte, message string) []byte {
+	program := []byte{
+		0xA9, 0xDE, 0x8D, 0x01, 0x60, // LDA #$DE; STA $6001
+		0xA9, 0xB0, 0x8D, 0x02, 0x60, // LDA #$B0; STA $6002
+		0xA9, 0x61, 0x8D, 0x03, 0x60, // LDA #$61; STA $6003
+		0xA9, 0x80, 0x8D, 0x00, 0x60, // LDA #$80; STA $6000
+		0xA2, 0x03, //                   LDX #3
+		0x2C, 0x02, 0x20, //             wait: BIT $2002
+		0x10, 0xFB, //                   BPL wait
+		0xCA,       //                   DEX
+		0xD0, 0xF8, //                   BNE wait
+		0xA0, 0x00, //                   LDY #0
+		0xB9, 0x34, 0x80, //             copy: LDA message,Y
+		0x99, 0x04, 0x60, //             STA $6004,Y
+		0xF0, 0x03, //                   BEQ done
+		0xC8,       //                   INY
+		0xD0, 0xF5, //                   BNE copy
+		0xA9, result, 0x8D, 0x00, 0x60, // done: LDA #result; STA $6000
+		0x4C, 0x30, 0x80, //             forever: JMP forever
+		0x40, //                         vectors: RTI
+	}
+	program = append(program, message...)
+	program = append(program, 0)
+
+	return nromPRG(program, 0x8033, 0x8033)
+}
+
+// nromPRG lays a program out at $8000 in a 16KB PRG ROM, starting on reset
+func nromPRG(program []byte, nmi types.Address, irq types.Address) []byte {
+	prgROM := make([]byte, 0x4000)
+	copy(prgROM, program)
+	copy(prgROM[0x3FFA:], []byte{byte(nmi & 0xFF), byte(nmi >> 8), 0x00, 0x80, byte(irq & 0xFF), byte(irq >> 8)})
+
+	return prgROM
+}
+
+func aNesRunningProgram(prgROM []byte) *Nes {
+	cartridge := gamePak2.CreateGamePak(gamePak2.CreateINes1Header(1, 1, 0, 0, 0, 0, 0), prgROM, make([]byte, 0x2000))
+	nes := CreateNes(&cartridge, &Debugger{})
+	nes.Start()
+
+	return nes
+}
+
+func TestRunBlarggTest_reads_result_reported_at_0x6000(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  byte
+		message string
+	}{
+		{"passed", 0, "Passed"},
+		{"failed", 3, "Failed #3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nes := aNesRunningProgram(blarggProtocolROM(tt.result, tt.message))
+
+			status, message, finished := runBlarggTest(nes, 30)
+
+			assert.True(t, finished)
+			assert.Equal(t, tt.result, status)
+			assert.Equal(t, tt.message, message)
+		})
+	}
+}
+
+func TestRunBlarggTest_does_not_finish_without_signature(t *testing.T) {
+	prgROM := nromPRG([]byte{0x4C, 0x00, 0x80}, 0x8000, 0x8000) // JMP $8000
+
+	_, _, finished := runBlarggTest(aNesRunningProgram(prgROM), 30)
+
+	assert.False(t, finished)
+}
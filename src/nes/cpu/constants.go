@@ -1,3 +1,5 @@
 package cpu
 
+const NMIVectorAddress = 0xFFFA
 const ResetVectorAddress = 0xFFFC
+const IRQVectorAddress = 0xFFFE
@@ -13,9 +13,30 @@ type Cpu6502 struct {
 	registers cpu.Registers
 	memory    Memory
 
-	instructions [256]cpu.Instruction
-	opCyclesLeft byte // How many cycles left to finish execution of current cycle
-	cycle        uint32
+	instructions   [256]cpu.Instruction
+	operationKinds [256]operationKind
+	opCyclesLeft   byte // How many cycles left to finish execution of current cycle
+	cycle          uint32
+
+	// Instruction in progress, run one cycle at a time. See cpu6502_cycles.go
+	opcode           byte
+	instructionCycle byte          // Cycle of the instruction being run, opcode fetch is the first one
+	addressReady     bool          // Operand address is resolved, remaining cycles belong to the operation
+	operationCycle   byte          // Cycles run since operand address was resolved
+	baseAddress      types.Address // Pointer or address before indexing
+	operandAddress   types.Address // Effective address, built along the addressing cycles
+	dataLatch        byte          // Operand of read-modify-write instructions, held between its read and write cycles
+	operandLatched   bool          // Operand is taken from dataLatch instead of reading it again
+
+	// Interrupt lines, see cpu6502_interrupts.go
+	nmiLine            bool
+	nmiPending         bool
+	irqLine            IRQSource
+	interruptPollCycle byte          // Cycles left of current instruction when interrupts are polled
+	nmiRequested       bool          // NMI was pending when current instruction polled interrupts
+	irqRequested       bool          // IRQ was asserted and unmasked when current instruction polled interrupts
+	interruptVector    types.Address // Vector of the interrupt sequence in progress, if any
+	hardwareInterrupt  bool          // Interrupt sequence was started by NMI or IRQ lines instead of BRK
 
 	addressEvaluators [13]AddressModeMethod
 
@@ -30,6 +51,7 @@ func CreateCPU(memory Memory, debugger *cpu.Debugger) *Cpu6502 {
 	}
 
 	cpu6502.initInstructionsTable()
+	cpu6502.initOperationKinds()
 	cpu6502.initAddressModeEvaluators()
 
 	return &cpu6502
@@ -78,12 +100,13 @@ func (cpu6502 *Cpu6502) read16(address types.Address) types.Word {
 	return types.CreateWord(low, high)
 }
 
-func (cpu6502 *Cpu6502) read16Bugged(address types.Address) types.Word {
+// peek16Bugged reads a pointer without side effects, wrapping around its page like JMP indirect does
+func (cpu6502 *Cpu6502) peek16Bugged(address types.Address) types.Word {
 	lsb := address
 	msb := (lsb & 0xFF00) | types.Address(byte(lsb)+1)
 
-	low := cpu6502.memory.Read(lsb)
-	high := cpu6502.memory.Read(msb)
+	low := cpu6502.memory.Peek(lsb)
+	high := cpu6502.memory.Peek(msb)
 
 	return types.CreateWord(low, high)
 }
@@ -92,290 +115,290 @@ func (cpu6502 *Cpu6502) initInstructionsTable() {
 	cpu6502.instructions = [256]cpu.Instruction{
 		cpu.CreateInstruction("BRK", cpu.Implicit, cpu6502.brk, 7, 1),
 		cpu.CreateInstruction("ORA", cpu.IndirectX, cpu6502.ora, 6, 2),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("JAM", cpu.Implicit, cpu6502.jam, 2, 1),
+		cpu.CreateInstruction("SLO", cpu.IndirectX, cpu6502.slo, 8, 2),
+		cpu.CreateInstruction("NOP", cpu.ZeroPage, cpu6502.ign, 3, 2),
 		cpu.CreateInstruction("ORA", cpu.ZeroPage, cpu6502.ora, 3, 2),
 		cpu.CreateInstruction("ASL", cpu.ZeroPage, cpu6502.asl, 5, 2),
-		{},
+		cpu.CreateInstruction("SLO", cpu.ZeroPage, cpu6502.slo, 5, 2),
 		cpu.CreateInstruction("PHP", cpu.Implicit, cpu6502.php, 3, 1),
 		cpu.CreateInstruction("ORA", cpu.Immediate, cpu6502.ora, 2, 2),
 		cpu.CreateInstruction("ASL", cpu.Implicit, cpu6502.asl, 2, 1),
-		{},
-		{},
+		cpu.CreateInstruction("ANC", cpu.Immediate, cpu6502.anc, 2, 2),
+		cpu.CreateInstruction("NOP", cpu.Absolute, cpu6502.ign, 4, 3),
 		cpu.CreateInstruction("ORA", cpu.Absolute, cpu6502.ora, 4, 3),
 		cpu.CreateInstruction("ASL", cpu.Absolute, cpu6502.asl, 6, 3),
-		{},
+		cpu.CreateInstruction("SLO", cpu.Absolute, cpu6502.slo, 6, 3),
 
 		// 0x10
 		cpu.CreateInstruction("BPL", cpu.Relative, cpu6502.bpl, 2, 2),
 		cpu.CreateInstruction("ORA", cpu.IndirectY, cpu6502.ora, 5, 2),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("JAM", cpu.Implicit, cpu6502.jam, 2, 1),
+		cpu.CreateInstruction("SLO", cpu.IndirectY, cpu6502.slo, 8, 2),
+		cpu.CreateInstruction("NOP", cpu.ZeroPageX, cpu6502.ign, 4, 2),
 		cpu.CreateInstruction("ORA", cpu.ZeroPageX, cpu6502.ora, 4, 2),
 		cpu.CreateInstruction("ASL", cpu.ZeroPageX, cpu6502.asl, 6, 2),
-		{},
+		cpu.CreateInstruction("SLO", cpu.ZeroPageX, cpu6502.slo, 6, 2),
 		cpu.CreateInstruction("CLC", cpu.Implicit, cpu6502.clc, 2, 1),
 		cpu.CreateInstruction("ORA", cpu.AbsoluteYIndexed, cpu6502.ora, 4, 3),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("NOP", cpu.Implicit, cpu6502.nop, 2, 1),
+		cpu.CreateInstruction("SLO", cpu.AbsoluteYIndexed, cpu6502.slo, 7, 3),
+		cpu.CreateInstruction("NOP", cpu.AbsoluteXIndexed, cpu6502.ign, 4, 3),
 		cpu.CreateInstruction("ORA", cpu.AbsoluteXIndexed, cpu6502.ora, 4, 3),
 		cpu.CreateInstruction("ASL", cpu.AbsoluteXIndexed, cpu6502.asl, 7, 3),
-		{},
+		cpu.CreateInstruction("SLO", cpu.AbsoluteXIndexed, cpu6502.slo, 7, 3),
 
 		// 0x20
 		cpu.CreateInstruction("JSR", cpu.Absolute, cpu6502.jsr, 6, 3),
 		cpu.CreateInstruction("AND", cpu.IndirectX, cpu6502.and, 6, 2),
-		{},
-		{},
+		cpu.CreateInstruction("JAM", cpu.Implicit, cpu6502.jam, 2, 1),
+		cpu.CreateInstruction("RLA", cpu.IndirectX, cpu6502.rla, 8, 2),
 		cpu.CreateInstruction("BIT", cpu.ZeroPage, cpu6502.bit, 3, 2),
 		cpu.CreateInstruction("AND", cpu.ZeroPage, cpu6502.and, 3, 2),
 		cpu.CreateInstruction("ROL", cpu.ZeroPage, cpu6502.rol, 5, 2),
-		{},
+		cpu.CreateInstruction("RLA", cpu.ZeroPage, cpu6502.rla, 5, 2),
 		cpu.CreateInstruction("PLP", cpu.Implicit, cpu6502.plp, 4, 1),
 		cpu.CreateInstruction("AND", cpu.Immediate, cpu6502.and, 2, 2),
 		cpu.CreateInstruction("ROL", cpu.Implicit, cpu6502.rol, 2, 1),
-		{},
+		cpu.CreateInstruction("ANC", cpu.Immediate, cpu6502.anc, 2, 2),
 		cpu.CreateInstruction("BIT", cpu.Absolute, cpu6502.bit, 4, 3),
 		cpu.CreateInstruction("AND", cpu.Absolute, cpu6502.and, 4, 3),
 		cpu.CreateInstruction("ROL", cpu.Absolute, cpu6502.rol, 6, 3),
-		{},
+		cpu.CreateInstruction("RLA", cpu.Absolute, cpu6502.rla, 6, 3),
 
 		// 0x30
 		cpu.CreateInstruction("BMI", cpu.Relative, cpu6502.bmi, 2, 2),
 		cpu.CreateInstruction("AND", cpu.IndirectY, cpu6502.and, 5, 2),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("JAM", cpu.Implicit, cpu6502.jam, 2, 1),
+		cpu.CreateInstruction("RLA", cpu.IndirectY, cpu6502.rla, 8, 2),
+		cpu.CreateInstruction("NOP", cpu.ZeroPageX, cpu6502.ign, 4, 2),
 		cpu.CreateInstruction("AND", cpu.ZeroPageX, cpu6502.and, 4, 2),
 		cpu.CreateInstruction("ROL", cpu.ZeroPageX, cpu6502.rol, 6, 2),
-		{},
+		cpu.CreateInstruction("RLA", cpu.ZeroPageX, cpu6502.rla, 6, 2),
 		cpu.CreateInstruction("SEC", cpu.Implicit, cpu6502.sec, 2, 1),
 		cpu.CreateInstruction("AND", cpu.AbsoluteYIndexed, cpu6502.and, 4, 3),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("NOP", cpu.Implicit, cpu6502.nop, 2, 1),
+		cpu.CreateInstruction("RLA", cpu.AbsoluteYIndexed, cpu6502.rla, 7, 3),
+		cpu.CreateInstruction("NOP", cpu.AbsoluteXIndexed, cpu6502.ign, 4, 3),
 		cpu.CreateInstruction("AND", cpu.AbsoluteXIndexed, cpu6502.and, 4, 3),
 		cpu.CreateInstruction("ROL", cpu.AbsoluteXIndexed, cpu6502.rol, 7, 3),
-		{},
+		cpu.CreateInstruction("RLA", cpu.AbsoluteXIndexed, cpu6502.rla, 7, 3),
 
 		// 0x40
 		cpu.CreateInstruction("RTI", cpu.Implicit, cpu6502.rti, 6, 1),
 		cpu.CreateInstruction("EOR", cpu.IndirectX, cpu6502.eor, 6, 2),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("JAM", cpu.Implicit, cpu6502.jam, 2, 1),
+		cpu.CreateInstruction("SRE", cpu.IndirectX, cpu6502.sre, 8, 2),
+		cpu.CreateInstruction("NOP", cpu.ZeroPage, cpu6502.ign, 3, 2),
 		cpu.CreateInstruction("EOR", cpu.ZeroPage, cpu6502.eor, 3, 2),
 		cpu.CreateInstruction("LSR", cpu.ZeroPage, cpu6502.lsr, 5, 2),
-		{},
+		cpu.CreateInstruction("SRE", cpu.ZeroPage, cpu6502.sre, 5, 2),
 		cpu.CreateInstruction("PHA", cpu.Implicit, cpu6502.pha, 3, 1),
 		cpu.CreateInstruction("EOR", cpu.Immediate, cpu6502.eor, 2, 2),
 		cpu.CreateInstruction("LSR", cpu.Implicit, cpu6502.lsr, 2, 1),
-		{},
+		cpu.CreateInstruction("ALR", cpu.Immediate, cpu6502.alr, 2, 2),
 		cpu.CreateInstruction("JMP", cpu.Absolute, cpu6502.jmp, 3, 3),
 		cpu.CreateInstruction("EOR", cpu.Absolute, cpu6502.eor, 4, 3),
 		cpu.CreateInstruction("LSR", cpu.Absolute, cpu6502.lsr, 6, 3),
-		{},
+		cpu.CreateInstruction("SRE", cpu.Absolute, cpu6502.sre, 6, 3),
 
 		// 0x50
 		cpu.CreateInstruction("BVC", cpu.Relative, cpu6502.bvc, 2, 2),
 		cpu.CreateInstruction("EOR", cpu.IndirectY, cpu6502.eor, 5, 2),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("JAM", cpu.Implicit, cpu6502.jam, 2, 1),
+		cpu.CreateInstruction("SRE", cpu.IndirectY, cpu6502.sre, 8, 2),
+		cpu.CreateInstruction("NOP", cpu.ZeroPageX, cpu6502.ign, 4, 2),
 		cpu.CreateInstruction("EOR", cpu.ZeroPageX, cpu6502.eor, 4, 2),
 		cpu.CreateInstruction("LSR", cpu.ZeroPageX, cpu6502.lsr, 6, 2),
-		{},
+		cpu.CreateInstruction("SRE", cpu.ZeroPageX, cpu6502.sre, 6, 2),
 		cpu.CreateInstruction("CLI", cpu.Implicit, cpu6502.cli, 2, 1),
 		cpu.CreateInstruction("EOR", cpu.AbsoluteYIndexed, cpu6502.eor, 4, 3),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("NOP", cpu.Implicit, cpu6502.nop, 2, 1),
+		cpu.CreateInstruction("SRE", cpu.AbsoluteYIndexed, cpu6502.sre, 7, 3),
+		cpu.CreateInstruction("NOP", cpu.AbsoluteXIndexed, cpu6502.ign, 4, 3),
 		cpu.CreateInstruction("EOR", cpu.AbsoluteXIndexed, cpu6502.eor, 4, 3),
 		cpu.CreateInstruction("LSR", cpu.AbsoluteXIndexed, cpu6502.lsr, 7, 3),
-		{},
+		cpu.CreateInstruction("SRE", cpu.AbsoluteXIndexed, cpu6502.sre, 7, 3),
 
 		// 0x60
 		cpu.CreateInstruction("RTS", cpu.Implicit, cpu6502.rts, 6, 1),
 		cpu.CreateInstruction("ADC", cpu.IndirectX, cpu6502.adc, 6, 2),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("JAM", cpu.Implicit, cpu6502.jam, 2, 1),
+		cpu.CreateInstruction("RRA", cpu.IndirectX, cpu6502.rra, 8, 2),
+		cpu.CreateInstruction("NOP", cpu.ZeroPage, cpu6502.ign, 3, 2),
 		cpu.CreateInstruction("ADC", cpu.ZeroPage, cpu6502.adc, 3, 2),
 		cpu.CreateInstruction("ROR", cpu.ZeroPage, cpu6502.ror, 5, 2),
-		{},
+		cpu.CreateInstruction("RRA", cpu.ZeroPage, cpu6502.rra, 5, 2),
 		cpu.CreateInstruction("PLA", cpu.Implicit, cpu6502.pla, 4, 1),
 		cpu.CreateInstruction("ADC", cpu.Immediate, cpu6502.adc, 2, 2),
 		cpu.CreateInstruction("ROR", cpu.Implicit, cpu6502.ror, 2, 1),
-		{},
+		cpu.CreateInstruction("ARR", cpu.Immediate, cpu6502.arr, 2, 2),
 		cpu.CreateInstruction("JMP", cpu.Indirect, cpu6502.jmp, 5, 3),
 		cpu.CreateInstruction("ADC", cpu.Absolute, cpu6502.adc, 4, 3),
 		cpu.CreateInstruction("ROR", cpu.Absolute, cpu6502.ror, 6, 3),
-		{},
+		cpu.CreateInstruction("RRA", cpu.Absolute, cpu6502.rra, 6, 3),
 
 		// 0x70
 		cpu.CreateInstruction("BVS", cpu.Relative, cpu6502.bvs, 2, 2),
 		cpu.CreateInstruction("ADC", cpu.IndirectY, cpu6502.adc, 5, 2),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("JAM", cpu.Implicit, cpu6502.jam, 2, 1),
+		cpu.CreateInstruction("RRA", cpu.IndirectY, cpu6502.rra, 8, 2),
+		cpu.CreateInstruction("NOP", cpu.ZeroPageX, cpu6502.ign, 4, 2),
 		cpu.CreateInstruction("ADC", cpu.ZeroPageX, cpu6502.adc, 4, 2),
 		cpu.CreateInstruction("ROR", cpu.ZeroPageX, cpu6502.ror, 6, 2),
-		{},
+		cpu.CreateInstruction("RRA", cpu.ZeroPageX, cpu6502.rra, 6, 2),
 		cpu.CreateInstruction("SEI", cpu.Implicit, cpu6502.sei, 2, 1),
 		cpu.CreateInstruction("ADC", cpu.AbsoluteYIndexed, cpu6502.adc, 4, 3),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("NOP", cpu.Implicit, cpu6502.nop, 2, 1),
+		cpu.CreateInstruction("RRA", cpu.AbsoluteYIndexed, cpu6502.rra, 7, 3),
+		cpu.CreateInstruction("NOP", cpu.AbsoluteXIndexed, cpu6502.ign, 4, 3),
 		cpu.CreateInstruction("ADC", cpu.AbsoluteXIndexed, cpu6502.adc, 4, 3),
 		cpu.CreateInstruction("ROR", cpu.AbsoluteXIndexed, cpu6502.ror, 7, 3),
-		{},
+		cpu.CreateInstruction("RRA", cpu.AbsoluteXIndexed, cpu6502.rra, 7, 3),
 
 		// 0x80
-		{},
+		cpu.CreateInstruction("NOP", cpu.Immediate, cpu6502.ign, 2, 2),
 		cpu.CreateInstruction("STA", cpu.IndirectX, cpu6502.sta, 6, 2),
-		{},
-		{},
+		cpu.CreateInstruction("NOP", cpu.Immediate, cpu6502.ign, 2, 2),
+		cpu.CreateInstruction("SAX", cpu.IndirectX, cpu6502.sax, 6, 2),
 		cpu.CreateInstruction("STY", cpu.ZeroPage, cpu6502.sty, 3, 2),
 		cpu.CreateInstruction("STA", cpu.ZeroPage, cpu6502.sta, 3, 2),
 		cpu.CreateInstruction("STX", cpu.ZeroPage, cpu6502.stx, 3, 2),
-		{},
+		cpu.CreateInstruction("SAX", cpu.ZeroPage, cpu6502.sax, 3, 2),
 		cpu.CreateInstruction("DEY", cpu.Implicit, cpu6502.dey, 2, 1),
-		{},
+		cpu.CreateInstruction("NOP", cpu.Immediate, cpu6502.ign, 2, 2),
 		cpu.CreateInstruction("TXA", cpu.Implicit, cpu6502.txa, 2, 1),
-		{},
+		cpu.CreateInstruction("XAA", cpu.Immediate, cpu6502.xaa, 2, 2),
 		cpu.CreateInstruction("STY", cpu.Absolute, cpu6502.sty, 4, 3),
 		cpu.CreateInstruction("STA", cpu.Absolute, cpu6502.sta, 4, 3),
 		cpu.CreateInstruction("STX", cpu.Absolute, cpu6502.stx, 4, 3),
-		{},
+		cpu.CreateInstruction("SAX", cpu.Absolute, cpu6502.sax, 4, 3),
 
 		// 0x90
 		cpu.CreateInstruction("BCC", cpu.Relative, cpu6502.bcc, 2, 2),
 		cpu.CreateInstruction("STA", cpu.IndirectY, cpu6502.sta, 6, 2),
-		{},
-		{},
+		cpu.CreateInstruction("JAM", cpu.Implicit, cpu6502.jam, 2, 1),
+		cpu.CreateInstruction("SHA", cpu.IndirectY, cpu6502.sha, 6, 2),
 		cpu.CreateInstruction("STY", cpu.ZeroPageX, cpu6502.sty, 4, 2),
 		cpu.CreateInstruction("STA", cpu.ZeroPageX, cpu6502.sta, 4, 2),
 		cpu.CreateInstruction("STX", cpu.ZeroPageY, cpu6502.stx, 4, 2),
-		{},
+		cpu.CreateInstruction("SAX", cpu.ZeroPageY, cpu6502.sax, 4, 2),
 		cpu.CreateInstruction("TYA", cpu.Implicit, cpu6502.tya, 2, 1),
 		cpu.CreateInstruction("STA", cpu.AbsoluteYIndexed, cpu6502.sta, 5, 3),
 		cpu.CreateInstruction("TXS", cpu.Implicit, cpu6502.txs, 2, 1),
-		{},
-		{},
+		cpu.CreateInstruction("TAS", cpu.AbsoluteYIndexed, cpu6502.tas, 5, 3),
+		cpu.CreateInstruction("SHY", cpu.AbsoluteXIndexed, cpu6502.shy, 5, 3),
 		cpu.CreateInstruction("STA", cpu.AbsoluteXIndexed, cpu6502.sta, 5, 3),
-		{},
-		{},
+		cpu.CreateInstruction("SHX", cpu.AbsoluteYIndexed, cpu6502.shx, 5, 3),
+		cpu.CreateInstruction("SHA", cpu.AbsoluteYIndexed, cpu6502.sha, 5, 3),
 
 		// 0xA0
 		cpu.CreateInstruction("LDY", cpu.Immediate, cpu6502.ldy, 2, 2),
 		cpu.CreateInstruction("LDA", cpu.IndirectX, cpu6502.lda, 6, 2),
 		cpu.CreateInstruction("LDX", cpu.Immediate, cpu6502.ldx, 2, 2),
-		{},
+		cpu.CreateInstruction("LAX", cpu.IndirectX, cpu6502.lax, 6, 2),
 		cpu.CreateInstruction("LDY", cpu.ZeroPage, cpu6502.ldy, 3, 2),
 		cpu.CreateInstruction("LDA", cpu.ZeroPage, cpu6502.lda, 3, 2),
 		cpu.CreateInstruction("LDX", cpu.ZeroPage, cpu6502.ldx, 3, 2),
-		{},
+		cpu.CreateInstruction("LAX", cpu.ZeroPage, cpu6502.lax, 3, 2),
 		cpu.CreateInstruction("TAY", cpu.Implicit, cpu6502.tay, 2, 1),
 		cpu.CreateInstruction("LDA", cpu.Immediate, cpu6502.lda, 2, 2),
 		cpu.CreateInstruction("TAX", cpu.Implicit, cpu6502.tax, 2, 1),
-		{},
+		cpu.CreateInstruction("LXA", cpu.Immediate, cpu6502.lxa, 2, 2),
 		cpu.CreateInstruction("LDY", cpu.Absolute, cpu6502.ldy, 4, 3),
 		cpu.CreateInstruction("LDA", cpu.Absolute, cpu6502.lda, 4, 3),
 		cpu.CreateInstruction("LDX", cpu.Absolute, cpu6502.ldx, 4, 3),
-		{},
+		cpu.CreateInstruction("LAX", cpu.Absolute, cpu6502.lax, 4, 3),
 
 		// 0xB0
 		cpu.CreateInstruction("BCS", cpu.Relative, cpu6502.bcs, 2, 2),
 		cpu.CreateInstruction("LDA", cpu.IndirectY, cpu6502.lda, 5, 2),
-		{},
-		{},
+		cpu.CreateInstruction("JAM", cpu.Implicit, cpu6502.jam, 2, 1),
+		cpu.CreateInstruction("LAX", cpu.IndirectY, cpu6502.lax, 5, 2),
 		cpu.CreateInstruction("LDY", cpu.ZeroPageX, cpu6502.ldy, 4, 2),
 		cpu.CreateInstruction("LDA", cpu.ZeroPageX, cpu6502.lda, 4, 2),
 		cpu.CreateInstruction("LDX", cpu.ZeroPageY, cpu6502.ldx, 4, 2),
-		{},
+		cpu.CreateInstruction("LAX", cpu.ZeroPageY, cpu6502.lax, 4, 2),
 		cpu.CreateInstruction("CLV", cpu.Implicit, cpu6502.clv, 2, 1),
 		cpu.CreateInstruction("LDA", cpu.AbsoluteYIndexed, cpu6502.lda, 4, 3),
 		cpu.CreateInstruction("TSX", cpu.Implicit, cpu6502.tsx, 2, 1),
-		{},
+		cpu.CreateInstruction("LAS", cpu.AbsoluteYIndexed, cpu6502.las, 4, 3),
 		cpu.CreateInstruction("LDY", cpu.AbsoluteXIndexed, cpu6502.ldy, 4, 3),
 		cpu.CreateInstruction("LDA", cpu.AbsoluteXIndexed, cpu6502.lda, 4, 3),
 		cpu.CreateInstruction("LDX", cpu.AbsoluteYIndexed, cpu6502.ldx, 4, 3),
-		{},
+		cpu.CreateInstruction("LAX", cpu.AbsoluteYIndexed, cpu6502.lax, 4, 3),
 
 		// 0xC0
 		cpu.CreateInstruction("CPY", cpu.Immediate, cpu6502.cpy, 2, 2),
 		cpu.CreateInstruction("CMP", cpu.IndirectX, cpu6502.cmp, 6, 2),
-		{},
-		{},
+		cpu.CreateInstruction("NOP", cpu.Immediate, cpu6502.ign, 2, 2),
+		cpu.CreateInstruction("DCP", cpu.IndirectX, cpu6502.dcp, 8, 2),
 		cpu.CreateInstruction("CPY", cpu.ZeroPage, cpu6502.cpy, 3, 2),
 		cpu.CreateInstruction("CMP", cpu.ZeroPage, cpu6502.cmp, 3, 2),
 		cpu.CreateInstruction("DEC", cpu.ZeroPage, cpu6502.dec, 5, 2),
-		{},
+		cpu.CreateInstruction("DCP", cpu.ZeroPage, cpu6502.dcp, 5, 2),
 		cpu.CreateInstruction("INY", cpu.Implicit, cpu6502.iny, 2, 1),
 		cpu.CreateInstruction("CMP", cpu.Immediate, cpu6502.cmp, 2, 2),
 		cpu.CreateInstruction("DEX", cpu.Implicit, cpu6502.dex, 2, 1),
-		{},
+		cpu.CreateInstruction("AXS", cpu.Immediate, cpu6502.axs, 2, 2),
 		cpu.CreateInstruction("CPY", cpu.Absolute, cpu6502.cpy, 4, 3),
 		cpu.CreateInstruction("CMP", cpu.Absolute, cpu6502.cmp, 4, 3),
 		cpu.CreateInstruction("DEC", cpu.Absolute, cpu6502.dec, 6, 3),
-		{},
+		cpu.CreateInstruction("DCP", cpu.Absolute, cpu6502.dcp, 6, 3),
 
 		// 0xD0
 		cpu.CreateInstruction("BNE", cpu.Relative, cpu6502.bne, 2, 2),
 		cpu.CreateInstruction("CMP", cpu.IndirectY, cpu6502.cmp, 5, 2),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("JAM", cpu.Implicit, cpu6502.jam, 2, 1),
+		cpu.CreateInstruction("DCP", cpu.IndirectY, cpu6502.dcp, 8, 2),
+		cpu.CreateInstruction("NOP", cpu.ZeroPageX, cpu6502.ign, 4, 2),
 		cpu.CreateInstruction("CMP", cpu.ZeroPageX, cpu6502.cmp, 4, 2),
 		cpu.CreateInstruction("DEC", cpu.ZeroPageX, cpu6502.dec, 6, 2),
-		{},
+		cpu.CreateInstruction("DCP", cpu.ZeroPageX, cpu6502.dcp, 6, 2),
 		cpu.CreateInstruction("CLD", cpu.Implicit, cpu6502.cld, 2, 1),
 		cpu.CreateInstruction("CMP", cpu.AbsoluteYIndexed, cpu6502.cmp, 4, 3),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("NOP", cpu.Implicit, cpu6502.nop, 2, 1),
+		cpu.CreateInstruction("DCP", cpu.AbsoluteYIndexed, cpu6502.dcp, 7, 3),
+		cpu.CreateInstruction("NOP", cpu.AbsoluteXIndexed, cpu6502.ign, 4, 3),
 		cpu.CreateInstruction("CMP", cpu.AbsoluteXIndexed, cpu6502.cmp, 4, 3),
 		cpu.CreateInstruction("DEC", cpu.AbsoluteXIndexed, cpu6502.dec, 7, 3),
-		{},
+		cpu.CreateInstruction("DCP", cpu.AbsoluteXIndexed, cpu6502.dcp, 7, 3),
 
 		// 0xE0
 		cpu.CreateInstruction("CPX", cpu.Immediate, cpu6502.cpx, 2, 2),
 		cpu.CreateInstruction("SBC", cpu.IndirectX, cpu6502.sbc, 6, 2),
-		{},
-		{},
+		cpu.CreateInstruction("NOP", cpu.Immediate, cpu6502.ign, 2, 2),
+		cpu.CreateInstruction("ISC", cpu.IndirectX, cpu6502.isc, 8, 2),
 		cpu.CreateInstruction("CPX", cpu.ZeroPage, cpu6502.cpx, 3, 2),
 		cpu.CreateInstruction("SBC", cpu.ZeroPage, cpu6502.sbc, 3, 2),
 		cpu.CreateInstruction("INC", cpu.ZeroPage, cpu6502.inc, 5, 2),
-		{},
+		cpu.CreateInstruction("ISC", cpu.ZeroPage, cpu6502.isc, 5, 2),
 		cpu.CreateInstruction("INX", cpu.Implicit, cpu6502.inx, 2, 1),
 		cpu.CreateInstruction("SBC", cpu.Immediate, cpu6502.sbc, 2, 2),
 		cpu.CreateInstruction("NOP", cpu.Implicit, cpu6502.nop, 2, 1),
-		{},
+		cpu.CreateInstruction("SBC", cpu.Immediate, cpu6502.sbc, 2, 2),
 		cpu.CreateInstruction("CPX", cpu.Absolute, cpu6502.cpx, 4, 3),
 		cpu.CreateInstruction("SBC", cpu.Absolute, cpu6502.sbc, 4, 3),
 		cpu.CreateInstruction("INC", cpu.Absolute, cpu6502.inc, 6, 3),
-		{},
+		cpu.CreateInstruction("ISC", cpu.Absolute, cpu6502.isc, 6, 3),
 
 		// 0xF0
 		cpu.CreateInstruction("BEQ", cpu.Relative, cpu6502.beq, 2, 2),
 		cpu.CreateInstruction("SBC", cpu.IndirectY, cpu6502.sbc, 5, 2),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("JAM", cpu.Implicit, cpu6502.jam, 2, 1),
+		cpu.CreateInstruction("ISC", cpu.IndirectY, cpu6502.isc, 8, 2),
+		cpu.CreateInstruction("NOP", cpu.ZeroPageX, cpu6502.ign, 4, 2),
 		cpu.CreateInstruction("SBC", cpu.ZeroPageX, cpu6502.sbc, 4, 2),
 		cpu.CreateInstruction("INC", cpu.ZeroPageX, cpu6502.inc, 6, 2),
-		{},
+		cpu.CreateInstruction("ISC", cpu.ZeroPageX, cpu6502.isc, 6, 2),
 		cpu.CreateInstruction("SED", cpu.Implicit, cpu6502.sed, 2, 1),
 		cpu.CreateInstruction("SBC", cpu.AbsoluteYIndexed, cpu6502.sbc, 4, 3),
-		{},
-		{},
-		{},
+		cpu.CreateInstruction("NOP", cpu.Implicit, cpu6502.nop, 2, 1),
+		cpu.CreateInstruction("ISC", cpu.AbsoluteYIndexed, cpu6502.isc, 7, 3),
+		cpu.CreateInstruction("NOP", cpu.AbsoluteXIndexed, cpu6502.ign, 4, 3),
 		cpu.CreateInstruction("SBC", cpu.AbsoluteXIndexed, cpu6502.sbc, 4, 3),
 		cpu.CreateInstruction("INC", cpu.AbsoluteXIndexed, cpu6502.inc, 7, 3),
-		{},
+		cpu.CreateInstruction("ISC", cpu.AbsoluteXIndexed, cpu6502.isc, 7, 3),
 	}
 }
 
@@ -396,6 +419,8 @@ func (cpu6502 *Cpu6502) initAddressModeEvaluators() {
 	}
 }
 
+// evaluateOperandAddress resolves the operand of the instruction located at pc without side effects on the bus,
+// so it can be logged before its addressing cycles run.
 func (cpu6502 *Cpu6502) evaluateOperandAddress(addressMode cpu.AddressMode, pc types.Address) (finalAddress types.Address, operand [3]byte, pageCrossed bool) {
 	if addressMode == cpu.Implicit {
 		finalAddress = 0
@@ -0,0 +1,309 @@
+package nes
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/cpu"
+	"github.com/raulferras/nes-golang/src/nes/types"
+)
+
+// Instructions run one cycle at a time, each cycle doing a single read or write on the bus, like the 6502 does.
+// The first cycle fetches the opcode. Following ones resolve the operand address as dictated by the address mode,
+// including dummy reads of the 6502 while it indexes or fixes the high byte of an address.
+// Then the operation runs on the last cycle, where its own read or write of the operand happens.
+//
+// Read-modify-write instructions read the operand, write it back unmodified while modifying it, and write the result.
+// BRK, JSR, RTI and RTS drive their own cycles.
+// Reference: https://www.nesdev.org/6502_cpu.txt
+
+// operationKind tells how an operation accesses its operand, which determines its cycles
+type operationKind byte
+
+const (
+	readOperation operationKind = iota
+	writeOperation
+	readModifyWriteOperation
+	pullOperation     // PLA, PLP
+	jumpOperation     // JMP, runs along the cycle resolving its address
+	sequenceOperation // BRK, JSR, RTI, RTS
+)
+
+func (cpu6502 *Cpu6502) initOperationKinds() {
+	kinds := map[string]operationKind{
+		"STA": writeOperation, "STX": writeOperation, "STY": writeOperation, "SAX": writeOperation,
+		"SHA": writeOperation, "SHX": writeOperation, "SHY": writeOperation, "TAS": writeOperation,
+		"ASL": readModifyWriteOperation, "LSR": readModifyWriteOperation,
+		"ROL": readModifyWriteOperation, "ROR": readModifyWriteOperation,
+		"INC": readModifyWriteOperation, "DEC": readModifyWriteOperation,
+		"SLO": readModifyWriteOperation, "SRE": readModifyWriteOperation,
+		"RLA": readModifyWriteOperation, "RRA": readModifyWriteOperation,
+		"DCP": readModifyWriteOperation, "ISC": readModifyWriteOperation,
+		"PLA": pullOperation, "PLP": pullOperation,
+		"JMP": jumpOperation,
+		"BRK": sequenceOperation, "JSR": sequenceOperation, "RTI": sequenceOperation, "RTS": sequenceOperation,
+	}
+
+	for opcode, instruction := range cpu6502.instructions {
+		cpu6502.operationKinds[opcode] = kinds[instruction.Name()]
+	}
+}
+
+// startInstruction prepares the cycles of opcode, once fetched
+func (cpu6502 *Cpu6502) startInstruction(opcode byte) {
+	instruction := cpu6502.instructions[opcode]
+
+	cpu6502.opcode = opcode
+	cpu6502.instructionCycle = 1
+	cpu6502.opCyclesLeft = instruction.Cycles()
+	cpu6502.interruptPollCycle = 1
+	cpu6502.operationCycle = 0
+	cpu6502.operandLatched = false
+
+	// Immediate operands are read by the operation itself
+	cpu6502.addressReady = instruction.AddressMode() == cpu.Immediate
+	if cpu6502.addressReady {
+		cpu6502.operandAddress = cpu6502.registers.Pc
+		cpu6502.registers.Pc++
+	}
+}
+
+// runCycle runs any cycle of current instruction after the opcode fetch
+func (cpu6502 *Cpu6502) runCycle() {
+	instruction := cpu6502.instructions[cpu6502.opcode]
+	kind := cpu6502.operationKinds[cpu6502.opcode]
+
+	switch {
+	case kind == sequenceOperation:
+		instruction.Method()(cpu6502.operationArgument())
+	case instruction.AddressMode() == cpu.Implicit:
+		cpu6502.impliedCycle(kind)
+	case instruction.AddressMode() == cpu.Relative:
+		cpu6502.branchCycle()
+	case !cpu6502.addressReady:
+		cpu6502.addressReady = cpu6502.addressingCycle(instruction.AddressMode(), kind)
+		if cpu6502.addressReady && kind == jumpOperation {
+			instruction.Method()(cpu6502.operationArgument())
+		}
+	default:
+		cpu6502.operationCycle++
+		cpu6502.operationCycleOf(kind)
+	}
+}
+
+func (cpu6502 *Cpu6502) operationArgument() cpu.OperationMethodArgument {
+	return cpu.OperationMethodArgument{
+		AddressMode:    cpu6502.instructions[cpu6502.opcode].AddressMode(),
+		OperandAddress: cpu6502.operandAddress,
+	}
+}
+
+func (cpu6502 *Cpu6502) runOperation() {
+	cpu6502.instructions[cpu6502.opcode].Method()(cpu6502.operationArgument())
+}
+
+// impliedCycle runs instructions without operand. The byte after the opcode is read and discarded.
+// Pushes write the stack on the last cycle, pulls read the stack once before incrementing the stack pointer.
+func (cpu6502 *Cpu6502) impliedCycle(kind operationKind) {
+	switch cpu6502.instructionCycle {
+	case 2:
+		cpu6502.memory.Read(cpu6502.registers.Pc)
+	case 3:
+		if kind == pullOperation {
+			cpu6502.memory.Read(cpu6502.registers.StackPointerAddress())
+		}
+	}
+
+	if cpu6502.opCyclesLeft == 1 {
+		cpu6502.runOperation()
+	}
+}
+
+// branchCycle runs branches. Taken branches spend a cycle reading next opcode while adding the offset
+// to PC low byte, and another one reading the wrong page when the high byte needs to be fixed.
+func (cpu6502 *Cpu6502) branchCycle() {
+	switch cpu6502.instructionCycle {
+	case 2:
+		offset := cpu6502.fetch()
+		cpu6502.baseAddress = cpu6502.registers.Pc
+		cpu6502.operandAddress = cpu6502.registers.Pc + types.Address(int8(offset))
+		cpu6502.runOperation()
+	case 3:
+		cpu6502.memory.Read(cpu6502.baseAddress)
+	case 4:
+		cpu6502.readUnfixedAddress()
+	}
+}
+
+// addressingCycle runs a cycle resolving the operand address. Tells if the address is ready for the operation.
+func (cpu6502 *Cpu6502) addressingCycle(addressMode cpu.AddressMode, kind operationKind) bool {
+	switch addressMode {
+	case cpu.ZeroPage:
+		cpu6502.operandAddress = types.Address(cpu6502.fetch())
+		return true
+	case cpu.ZeroPageX:
+		return cpu6502.zeroPageIndexedCycle(cpu6502.registers.X)
+	case cpu.ZeroPageY:
+		return cpu6502.zeroPageIndexedCycle(cpu6502.registers.Y)
+	case cpu.Absolute:
+		return cpu6502.absoluteCycle()
+	case cpu.AbsoluteXIndexed:
+		return cpu6502.absoluteIndexedCycle(cpu6502.registers.X, kind)
+	case cpu.AbsoluteYIndexed:
+		return cpu6502.absoluteIndexedCycle(cpu6502.registers.Y, kind)
+	case cpu.Indirect:
+		return cpu6502.indirectCycle()
+	case cpu.IndirectX:
+		return cpu6502.indirectXCycle()
+	case cpu.IndirectY:
+		return cpu6502.indirectYCycle(kind)
+	}
+
+	return true
+}
+
+func (cpu6502 *Cpu6502) zeroPageIndexedCycle(index byte) bool {
+	if cpu6502.instructionCycle == 2 {
+		cpu6502.baseAddress = types.Address(cpu6502.fetch())
+		return false
+	}
+
+	// Base address is read while the index is added, wrapping around zero page
+	cpu6502.memory.Read(cpu6502.baseAddress)
+	cpu6502.operandAddress = types.Address(byte(cpu6502.baseAddress) + index)
+
+	return true
+}
+
+func (cpu6502 *Cpu6502) absoluteCycle() bool {
+	if cpu6502.instructionCycle == 2 {
+		cpu6502.operandAddress = types.Address(cpu6502.fetch())
+		return false
+	}
+
+	cpu6502.operandAddress |= types.Address(cpu6502.fetch()) << 8
+
+	return true
+}
+
+func (cpu6502 *Cpu6502) absoluteIndexedCycle(index byte, kind operationKind) bool {
+	switch cpu6502.instructionCycle {
+	case 2:
+		cpu6502.baseAddress = types.Address(cpu6502.fetch())
+		return false
+	case 3:
+		cpu6502.baseAddress |= types.Address(cpu6502.fetch()) << 8
+		return cpu6502.indexBaseAddress(index, kind)
+	}
+
+	cpu6502.readUnfixedAddress()
+
+	return true
+}
+
+// indirectCycle resolves JMP (indirect) address. The pointer high byte is read without carrying into next page.
+func (cpu6502 *Cpu6502) indirectCycle() bool {
+	switch cpu6502.instructionCycle {
+	case 2:
+		cpu6502.baseAddress = types.Address(cpu6502.fetch())
+		return false
+	case 3:
+		cpu6502.baseAddress |= types.Address(cpu6502.fetch()) << 8
+		return false
+	case 4:
+		cpu6502.operandAddress = types.Address(cpu6502.memory.Read(cpu6502.baseAddress))
+		return false
+	}
+
+	highPointer := cpu6502.baseAddress&0xFF00 | types.Address(byte(cpu6502.baseAddress)+1)
+	cpu6502.operandAddress |= types.Address(cpu6502.memory.Read(highPointer)) << 8
+
+	return true
+}
+
+func (cpu6502 *Cpu6502) indirectXCycle() bool {
+	switch cpu6502.instructionCycle {
+	case 2:
+		cpu6502.baseAddress = types.Address(cpu6502.fetch())
+		return false
+	case 3:
+		// Pointer is read while X is added to it
+		cpu6502.memory.Read(cpu6502.baseAddress)
+		cpu6502.baseAddress = types.Address(byte(cpu6502.baseAddress) + cpu6502.registers.X)
+		return false
+	case 4:
+		cpu6502.operandAddress = types.Address(cpu6502.memory.Read(cpu6502.baseAddress))
+		return false
+	}
+
+	high := cpu6502.memory.Read(types.Address(byte(cpu6502.baseAddress) + 1))
+	cpu6502.operandAddress |= types.Address(high) << 8
+
+	return true
+}
+
+func (cpu6502 *Cpu6502) indirectYCycle(kind operationKind) bool {
+	switch cpu6502.instructionCycle {
+	case 2:
+		cpu6502.baseAddress = types.Address(cpu6502.fetch())
+		return false
+	case 3:
+		cpu6502.operandAddress = types.Address(cpu6502.memory.Read(cpu6502.baseAddress))
+		return false
+	case 4:
+		high := cpu6502.memory.Read(types.Address(byte(cpu6502.baseAddress) + 1))
+		cpu6502.baseAddress = types.CreateAddress(byte(cpu6502.operandAddress), high)
+		return cpu6502.indexBaseAddress(cpu6502.registers.Y, kind)
+	}
+
+	cpu6502.readUnfixedAddress()
+
+	return true
+}
+
+// indexBaseAddress adds index to the base address. Tells if the address is ready, otherwise next cycle reads
+// the address before its high byte is fixed: always for writes and read-modify-writes, only when crossing a page for reads.
+func (cpu6502 *Cpu6502) indexBaseAddress(index byte, kind operationKind) bool {
+	cpu6502.operandAddress = cpu6502.baseAddress + types.Address(index)
+	if kind != readOperation {
+		return false
+	}
+	if !memoryPageDiffer(cpu6502.baseAddress, cpu6502.operandAddress) {
+		return true
+	}
+
+	cpu6502.opCyclesLeft++
+
+	return false
+}
+
+// readUnfixedAddress does the dummy read of an indexed address whose high byte has not been fixed yet
+func (cpu6502 *Cpu6502) readUnfixedAddress() {
+	cpu6502.memory.Read(cpu6502.baseAddress&0xFF00 | cpu6502.operandAddress&0x00FF)
+}
+
+// operationCycleOf runs the operation cycles, once the operand address is ready
+func (cpu6502 *Cpu6502) operationCycleOf(kind operationKind) {
+	if kind != readModifyWriteOperation {
+		cpu6502.runOperation()
+		return
+	}
+
+	switch cpu6502.operationCycle {
+	case 1:
+		cpu6502.dataLatch = cpu6502.memory.Read(cpu6502.operandAddress)
+	case 2:
+		cpu6502.memory.Write(cpu6502.operandAddress, cpu6502.dataLatch)
+	default:
+		cpu6502.operandLatched = true
+		cpu6502.runOperation()
+	}
+}
+
+// readOperand reads the operand of read-modify-write operations.
+// When run cycle by cycle the operand was already read, so it is taken from the data latch.
+func (cpu6502 *Cpu6502) readOperand(address types.Address) byte {
+	if cpu6502.operandLatched {
+		cpu6502.operandLatched = false
+		return cpu6502.dataLatch
+	}
+
+	return cpu6502.memory.Read(address)
+}
@@ -0,0 +1,142 @@
+package nes
+
+import (
+	"github.com/raulferras/nes-golang/src/mocks"
+	nescpu "github.com/raulferras/nes-golang/src/nes/cpu"
+	"github.com/raulferras/nes-golang/src/nes/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type busAccess struct {
+	address types.Address
+	value   byte
+	write   bool
+}
+
+// recordingMemory records every read and write the CPU does on the bus
+type recordingMemory struct {
+	*mocks.SimpleMemory
+	accesses []busAccess
+}
+
+func (memory *recordingMemory) Read(address types.Address) byte {
+	value := memory.SimpleMemory.Read(address)
+	memory.accesses = append(memory.accesses, busAccess{address, value, false})
+
+	return value
+}
+
+func (memory *recordingMemory) Write(address types.Address, value byte) {
+	memory.SimpleMemory.Write(address, value)
+	memory.accesses = append(memory.accesses, busAccess{address, value, true})
+}
+
+func busRead(address types.Address, value byte) busAccess {
+	return busAccess{address, value, false}
+}
+
+func busWrite(address types.Address, value byte) busAccess {
+	return busAccess{address, value, true}
+}
+
+// aRecordedCPU returns a CPU running program from 0x0200, along the memory recording its accesses
+func aRecordedCPU(program ...byte) (*Cpu6502, *recordingMemory) {
+	memory := &recordingMemory{SimpleMemory: mocks.NewSimpleMemory()}
+	cpu := CreateCPU(memory, nescpu.NewDebugger(false, ""))
+	for i, value := range program {
+		memory.SimpleMemory.Write(types.Address(0x0200+i), value)
+	}
+	cpu.registers.Pc = 0x0200
+	cpu.registers.Sp = 0xFD
+
+	return cpu, memory
+}
+
+func TestCpu_does_one_bus_access_per_cycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		program  []byte
+		setup    func(cpu *Cpu6502)
+		expected []busAccess
+	}{
+		{
+			"LDA absolute,X without page cross",
+			[]byte{0xBD, 0x10, 0x03},
+			func(cpu *Cpu6502) { cpu.registers.X = 0x01 },
+			[]busAccess{busRead(0x0200, 0xBD), busRead(0x0201, 0x10), busRead(0x0202, 0x03), busRead(0x0311, 0x00)},
+		},
+		{
+			"LDA absolute,X reads wrong page before fixing high byte",
+			[]byte{0xBD, 0xF0, 0x03},
+			func(cpu *Cpu6502) { cpu.registers.X = 0x20 },
+			[]busAccess{busRead(0x0200, 0xBD), busRead(0x0201, 0xF0), busRead(0x0202, 0x03), busRead(0x0310, 0x00), busRead(0x0410, 0x00)},
+		},
+		{
+			"STA absolute,X always does a dummy read",
+			[]byte{0x9D, 0x10, 0x03},
+			func(cpu *Cpu6502) { cpu.registers.X = 0x01; cpu.registers.A = 0x42 },
+			[]busAccess{busRead(0x0200, 0x9D), busRead(0x0201, 0x10), busRead(0x0202, 0x03), busRead(0x0311, 0x00), busWrite(0x0311, 0x42)},
+		},
+		{
+			"INC zero page writes back the unmodified value first",
+			[]byte{0xE6, 0x40},
+			func(cpu *Cpu6502) { cpu.memory.Write(0x0040, 0x07) },
+			[]busAccess{busRead(0x0200, 0xE6), busRead(0x0201, 0x40), busRead(0x0040, 0x07), busWrite(0x0040, 0x07), busWrite(0x0040, 0x08)},
+		},
+		{
+			"LDA zero page,X reads base address while indexing",
+			[]byte{0xB5, 0xF0},
+			func(cpu *Cpu6502) { cpu.registers.X = 0x20 },
+			[]busAccess{busRead(0x0200, 0xB5), busRead(0x0201, 0xF0), busRead(0x00F0, 0x00), busRead(0x0010, 0x00)},
+		},
+		{
+			"LDA (indirect),Y reads wrong page before fixing high byte",
+			[]byte{0xB1, 0x40},
+			func(cpu *Cpu6502) {
+				cpu.registers.Y = 0x20
+				cpu.memory.Write(0x0040, 0xF0)
+				cpu.memory.Write(0x0041, 0x03)
+			},
+			[]busAccess{
+				busRead(0x0200, 0xB1), busRead(0x0201, 0x40), busRead(0x0040, 0xF0), busRead(0x0041, 0x03),
+				busRead(0x0310, 0x00), busRead(0x0410, 0x00),
+			},
+		},
+		{
+			"PLA reads the stack before incrementing stack pointer",
+			[]byte{0x68},
+			func(cpu *Cpu6502) { cpu.memory.Write(0x01FE, 0x42) },
+			[]busAccess{busRead(0x0200, 0x68), busRead(0x0201, 0x00), busRead(0x01FD, 0x00), busRead(0x01FE, 0x42)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu, memory := aRecordedCPU(tt.program...)
+			tt.setup(cpu)
+			memory.accesses = nil
+
+			cycles := 0
+			for cpu.Tick(); !cpu.Complete(); cpu.Tick() {
+				cycles++
+			}
+			cycles++
+
+			assert.Equal(t, tt.expected, memory.accesses)
+			assert.Equal(t, len(tt.expected), cycles, "every cycle should access the bus once")
+		})
+	}
+}
+
+func TestCpu_JSR_cycles(t *testing.T) {
+	cpu, memory := aRecordedCPU(0x20, 0x55, 0x05) // JSR $0555
+
+	runInstruction(cpu)
+
+	assert.Equal(t, []busAccess{
+		busRead(0x0200, 0x20), busRead(0x0201, 0x55), busRead(0x01FD, 0x00),
+		busWrite(0x01FD, 0x02), busWrite(0x01FC, 0x02), busRead(0x0202, 0x05),
+	}, memory.accesses)
+	assert.Equal(t, types.Address(0x0555), cpu.registers.Pc)
+}
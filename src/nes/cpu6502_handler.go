@@ -26,55 +26,109 @@ func (cpu6502 *Cpu6502) ResetToAddress(programCounter types.Address) {
 	cpu6502.cycle = 7
 }
 
+// Tick runs a single CPU cycle, which does exactly one read or write on the bus.
+// Returns how many cycles are left to complete current instruction, and its state when it has just been fetched.
 func (cpu6502 *Cpu6502) Tick() (byte, cpu.CpuState) {
-	var state cpu.CpuState
+	state := cpu.CreateWaitingState()
 
 	if cpu6502.opCyclesLeft == 0 {
-		registersCopy := *cpu6502.Registers()
+		if cpu6502.hardwareInterrupt {
+			cpu6502.startInterruptSequence()
+		} else {
+			state = cpu6502.fetchOpcode()
+		}
+	} else {
+		cpu6502.instructionCycle++
+		cpu6502.runCycle()
+	}
 
-		opcode := cpu6502.memory.Read(cpu6502.registers.Pc)
-		instruction := cpu6502.instructions[opcode]
-		cpu6502.opCyclesLeft = instruction.Cycles()
+	cpu6502.cycle++
+	cpu6502.opCyclesLeft--
 
-		if instruction.Method() == nil {
-			msg := fmt.Errorf("opcode 0x%X not implemented", opcode)
-			cpu6502.Stop()
-			panic(msg)
-		}
+	return cpu6502.opCyclesLeft, state
+}
 
-		operandAddress, operand, pageCrossed := cpu6502.evaluateOperandAddress(
-			instruction.AddressMode(),
-			cpu6502.registers.Pc+1,
-		)
-		step := cpu.OperationMethodArgument{
-			instruction.AddressMode(),
-			operandAddress,
-		}
+// fetchOpcode runs the first cycle of an instruction
+func (cpu6502 *Cpu6502) fetchOpcode() cpu.CpuState {
+	var state cpu.CpuState
+	if cpu6502.debugger.Enabled {
+		state = cpu6502.instructionState()
+	}
 
-		state = cpu.CreateState(
-			registersCopy,
-			[3]byte{opcode, operand[0], operand[1]},
-			instruction,
-			step,
-			cpu6502.cycle,
-		)
+	opcode := cpu6502.fetch()
+	if cpu6502.instructions[opcode].Method() == nil {
+		msg := fmt.Errorf("opcode 0x%X not implemented", opcode)
+		cpu6502.Stop()
+		panic(msg)
+	}
+	cpu6502.startInstruction(opcode)
 
-		cpu6502.registers.Pc += types.Address(instruction.Size())
+	return state
+}
 
-		opMightNeedExtraCycle := instruction.Method()(step)
+// instructionState describes the instruction about to be fetched, for logging purposes
+func (cpu6502 *Cpu6502) instructionState() cpu.CpuState {
+	opcode := cpu6502.memory.Peek(cpu6502.registers.Pc)
+	instruction := cpu6502.instructions[opcode]
+	operandAddress, operand, _ := cpu6502.evaluateOperandAddress(
+		instruction.AddressMode(),
+		cpu6502.registers.Pc+1,
+	)
+
+	return cpu.CreateState(
+		*cpu6502.Registers(),
+		[3]byte{opcode, operand[0], operand[1]},
+		instruction,
+		cpu.OperationMethodArgument{AddressMode: instruction.AddressMode(), OperandAddress: operandAddress},
+		cpu6502.cycle,
+	)
+}
 
-		if pageCrossed && opMightNeedExtraCycle {
-			cpu6502.opCyclesLeft++
-		}
+// nextBusAccess tells which address next cycle accesses, and if it writes it, without running the cycle.
+// The cycle is run against a bus probe and CPU state is restored afterwards.
+func (cpu6502 *Cpu6502) nextBusAccess() (types.Address, bool) {
+	saved := *cpu6502
+	probe := &busProbe{bus: cpu6502.memory}
+	cpu6502.memory = probe
+	cpu6502.Tick()
+	*cpu6502 = saved
 
-		cpu6502.cycle += uint32(cpu6502.opCyclesLeft)
-	} else {
-		state = cpu.CreateWaitingState()
-	}
+	return probe.address, probe.write
+}
 
-	cpu6502.opCyclesLeft--
+// stall spends a cycle halted by the DMA unit
+func (cpu6502 *Cpu6502) stall() {
+	cpu6502.cycle++
+}
 
-	return cpu6502.opCyclesLeft, state
+// busProbe records the first access of a CPU cycle, without reaching the bus
+type busProbe struct {
+	bus      Memory
+	accessed bool
+	address  types.Address
+	write    bool
+}
+
+func (probe *busProbe) Peek(address types.Address) byte {
+	return probe.bus.Peek(address)
+}
+
+func (probe *busProbe) Read(address types.Address) byte {
+	probe.record(address, false)
+	return 0
+}
+
+func (probe *busProbe) Write(address types.Address, _ byte) {
+	probe.record(address, true)
+}
+
+func (probe *busProbe) record(address types.Address, write bool) {
+	if probe.accessed {
+		return
+	}
+	probe.accessed = true
+	probe.address = address
+	probe.write = write
 }
 
 func (cpu6502 *Cpu6502) Stop() {
@@ -5,25 +5,6 @@ import (
 	"github.com/raulferras/nes-golang/src/nes/types"
 )
 
-// Non Maskable Interrupt
-func (cpu6502 *Cpu6502) nmi() {
-
-	cpu6502.pushStack(byte(cpu6502.registers.Pc >> 8))
-	cpu6502.pushStack(byte(cpu6502.registers.Pc))
-	cpu6502.pushStack(cpu6502.registers.Status)
-
-	cpu6502.registers.Pc = cpu6502.read16(0xFFFA)
-}
-
-func (cpu6502 *Cpu6502) irq() {
-
-	cpu6502.pushStack(byte(cpu6502.registers.Pc >> 8))
-	cpu6502.pushStack(byte(cpu6502.registers.Pc))
-	cpu6502.pushStack(cpu6502.registers.Status)
-
-	cpu6502.registers.Pc = cpu6502.read16(0xFFFE)
-}
-
 func (cpu6502 *Cpu6502) evalImplicit(programCounter types.Address) (finalAddress types.Address, opcodeOperand [3]byte, cycles int, pageCrossed bool) {
 	finalAddress = 0
 	cycles = 0
@@ -44,7 +25,7 @@ func (cpu6502 *Cpu6502) evalImmediate(programCounter types.Address) (address typ
 
 func (cpu6502 *Cpu6502) evalZeroPage(programCounter types.Address) (address types.Address, opcodeOperand [3]byte, cycles int, pageCrossed bool) {
 	// 2 bytes
-	var low = cpu6502.memory.Read(programCounter)
+	var low = cpu6502.memory.Peek(programCounter)
 
 	address = types.Address(low)
 	opcodeOperand = [3]byte{low}
@@ -54,7 +35,7 @@ func (cpu6502 *Cpu6502) evalZeroPage(programCounter types.Address) (address type
 
 func (cpu6502 *Cpu6502) evalZeroPageX(programCounter types.Address) (address types.Address, opcodeOperand [3]byte, cycles int, pageCrossed bool) {
 	registers := cpu6502.registers
-	var low = cpu6502.memory.Read(programCounter) + registers.X
+	var low = cpu6502.memory.Peek(programCounter) + registers.X
 
 	address = types.Address(low) & 0xFF
 	opcodeOperand = [3]byte{low}
@@ -64,7 +45,7 @@ func (cpu6502 *Cpu6502) evalZeroPageX(programCounter types.Address) (address typ
 
 func (cpu6502 *Cpu6502) evalZeroPageY(programCounter types.Address) (address types.Address, opcodeOperand [3]byte, cycles int, pageCrossed bool) {
 	registers := cpu6502.registers
-	var low = cpu6502.memory.Read(programCounter) + registers.Y
+	var low = cpu6502.memory.Peek(programCounter) + registers.Y
 
 	address = types.Address(low) & 0xFF
 	opcodeOperand = [3]byte{low}
@@ -72,11 +53,11 @@ func (cpu6502 *Cpu6502) evalZeroPageY(programCounter types.Address) (address typ
 }
 
 func (cpu6502 *Cpu6502) evalAbsolute(programCounter types.Address) (address types.Address, opcodeOperand [3]byte, cycles int, pageCrossed bool) {
-	low := cpu6502.memory.Read(programCounter)
+	low := cpu6502.memory.Peek(programCounter)
 	programCounter += 1
 
 	// Bug: Missing incrementing programCounter
-	high := cpu6502.memory.Read(programCounter)
+	high := cpu6502.memory.Peek(programCounter)
 
 	address = types.CreateAddress(low, high)
 	opcodeOperand = [3]byte{low, high}
@@ -85,9 +66,9 @@ func (cpu6502 *Cpu6502) evalAbsolute(programCounter types.Address) (address type
 }
 
 func (cpu6502 *Cpu6502) evalAbsoluteXIndexed(programCounter types.Address) (address types.Address, opcodeOperand [3]byte, cycles int, pageCrossed bool) {
-	low := cpu6502.memory.Read(programCounter)
+	low := cpu6502.memory.Peek(programCounter)
 
-	high := cpu6502.memory.Read(programCounter + 1)
+	high := cpu6502.memory.Peek(programCounter + 1)
 
 	address = types.CreateAddress(low, high)
 	address += types.Address(cpu6502.registers.X)
@@ -99,8 +80,8 @@ func (cpu6502 *Cpu6502) evalAbsoluteXIndexed(programCounter types.Address) (addr
 }
 
 func (cpu6502 *Cpu6502) evalAbsoluteYIndexed(programCounter types.Address) (address types.Address, opcodeOperand [3]byte, cycles int, pageCrossed bool) {
-	low := cpu6502.memory.Read(programCounter)
-	high := cpu6502.memory.Read(programCounter + 1)
+	low := cpu6502.memory.Peek(programCounter)
+	high := cpu6502.memory.Peek(programCounter + 1)
 
 	address = types.CreateAddress(low, high)
 	address += types.Address(cpu6502.registers.Y)
@@ -127,25 +108,25 @@ func (cpu6502 *Cpu6502) evalAbsoluteYIndexed(programCounter types.Address) (addr
 // in the 2A03 used by the NES.
 func (cpu6502 *Cpu6502) evalIndirect(programCounter types.Address) (address types.Address, opcodeOperand [3]byte, cycles int, pageCrossed bool) {
 	// Get Pointer types.Address
-	ptrLow := cpu6502.memory.Read(programCounter)
-	ptrHigh := cpu6502.memory.Read(programCounter + 1)
+	ptrLow := cpu6502.memory.Peek(programCounter)
+	ptrHigh := cpu6502.memory.Peek(programCounter + 1)
 
 	ptrAddress := types.CreateAddress(ptrLow, ptrHigh)
-	address = cpu6502.read16Bugged(ptrAddress)
+	address = cpu6502.peek16Bugged(ptrAddress)
 	opcodeOperand = [3]byte{ptrLow, ptrHigh}
 
 	return
 }
 
 func (cpu6502 *Cpu6502) evalIndirectX(programCounter types.Address) (address types.Address, opcodeOperand [3]byte, cycles int, pageCrossed bool) {
-	operand := cpu6502.memory.Read(programCounter)
+	operand := cpu6502.memory.Peek(programCounter)
 	opcodeOperand = [3]byte{operand}
 
 	operand += cpu6502.registers.X
 	operand &= 0xFF
 
-	effectiveLow := cpu6502.memory.Read(types.Address(operand))
-	effectiveHigh := cpu6502.memory.Read(types.Address(operand + 1)) // automatic warp around
+	effectiveLow := cpu6502.memory.Peek(types.Address(operand))
+	effectiveHigh := cpu6502.memory.Peek(types.Address(operand + 1)) // automatic warp around
 
 	address = types.CreateAddress(effectiveLow, effectiveHigh)
 
@@ -153,10 +134,10 @@ func (cpu6502 *Cpu6502) evalIndirectX(programCounter types.Address) (address typ
 }
 
 func (cpu6502 *Cpu6502) evalIndirectY(programCounter types.Address) (address types.Address, opcodeOperand [3]byte, cycles int, pageCrossed bool) {
-	operand := cpu6502.memory.Read(programCounter)
+	operand := cpu6502.memory.Peek(programCounter)
 
-	lo := cpu6502.memory.Read(types.Address(operand))
-	hi := cpu6502.memory.Read(types.Address(operand + 1)) // automatic warp around
+	lo := cpu6502.memory.Peek(types.Address(operand))
+	hi := cpu6502.memory.Peek(types.Address(operand + 1)) // automatic warp around
 
 	address = types.CreateAddress(lo, hi)
 	address += types.Word(cpu6502.registers.Y)
@@ -167,7 +148,7 @@ func (cpu6502 *Cpu6502) evalIndirectY(programCounter types.Address) (address typ
 }
 
 func (cpu6502 *Cpu6502) evalRelative(programCounter types.Address) (address types.Address, opcodeOperand [3]byte, cycles int, pageCrossed bool) {
-	operand := cpu6502.memory.Read(programCounter)
+	operand := cpu6502.memory.Peek(programCounter)
 
 	address = programCounter + 1
 	if operand < 0x80 {
@@ -201,9 +182,14 @@ func (cpu6502 *Cpu6502) evalRelative(programCounter types.Address) (address type
 	https://forums.nesdev.com/viewtopic.php?t=6331
 */
 func (cpu6502 *Cpu6502) adc(info cpu.OperationMethodArgument) bool {
+	cpu6502.addWithCarry(cpu6502.memory.Read(info.OperandAddress))
+
+	return true
+}
+
+func (cpu6502 *Cpu6502) addWithCarry(value byte) {
 	carryIn := cpu6502.registers.CarryFlag()
 	a := cpu6502.registers.A
-	value := cpu6502.memory.Read(info.OperandAddress)
 	adc := uint16(a) + uint16(value) + uint16(carryIn)
 	adc8 := cpu6502.registers.A + value + cpu6502.registers.CarryFlag()
 
@@ -219,8 +205,6 @@ func (cpu6502 *Cpu6502) adc(info cpu.OperationMethodArgument) bool {
 	} else {
 		cpu6502.registers.SetOverflowFlag(false)
 	}
-
-	return true
 }
 
 //	Performs a logical AND on the operand and the Accumulator and stores the result in the Accumulator
@@ -263,7 +247,7 @@ func (cpu6502 *Cpu6502) asl(info cpu.OperationMethodArgument) bool {
 		cpu6502.registers.UpdateNegativeFlag(cpu6502.registers.A)
 		cpu6502.registers.UpdateZeroFlag(cpu6502.registers.A)
 	} else {
-		value := cpu6502.memory.Read(info.OperandAddress)
+		value := cpu6502.readOperand(info.OperandAddress)
 		cpu6502.registers.SetCarryFlag(value>>7&0x01 == 1)
 		value = value << 1
 		cpu6502.memory.Write(info.OperandAddress, value)
@@ -278,6 +262,9 @@ func (cpu6502 *Cpu6502) addBranchCycles(info cpu.OperationMethodArgument) bool {
 	cpu6502.opCyclesLeft++
 	if memoryPageDiffer(cpu6502.registers.Pc, info.OperandAddress) {
 		cpu6502.opCyclesLeft++
+	} else {
+		// Taken branches not crossing a page do not poll interrupts on their last cycle
+		cpu6502.interruptPollCycle = 2
 	}
 
 	return false
@@ -437,17 +424,38 @@ func (cpu6502 *Cpu6502) bpl(info cpu.OperationMethodArgument) bool {
 	implied       BRK           00    1     7
 */
 func (cpu6502 *Cpu6502) brk(info cpu.OperationMethodArgument) bool {
-	// Store PC in stack
-	pc := cpu6502.registers.Pc + 1
-	cpu6502.pushStack(types.HighNibble(pc))
-	cpu6502.pushStack(types.LowNibble(pc))
-
-	// Push status with Break flag set
-	cpu6502.pushStack(cpu6502.registers.Status | 0b00010000)
-
-	cpu6502.registers.SetInterruptFlag(true)
-
-	cpu6502.registers.Pc = cpu6502.read16(0xFFFE)
+	switch cpu6502.instructionCycle {
+	case 2:
+		// Padding byte after BRK is read and skipped. NMI and IRQ sequences leave PC untouched.
+		cpu6502.memory.Read(cpu6502.registers.Pc)
+		if !cpu6502.hardwareInterrupt {
+			cpu6502.registers.Pc++
+			cpu6502.interruptVector = cpu.IRQVectorAddress
+		}
+	case 3:
+		cpu6502.pushStack(types.HighNibble(cpu6502.registers.Pc))
+	case 4:
+		cpu6502.pushStack(types.LowNibble(cpu6502.registers.Pc))
+	case 5:
+		// Push status with Break flag set, unless an interrupt line started the sequence
+		status := cpu6502.registers.Status | 0b00110000
+		if cpu6502.hardwareInterrupt {
+			status &^= 0b00010000
+		}
+		cpu6502.pushStack(status)
+		cpu6502.registers.SetInterruptFlag(true)
+
+		// An NMI detected before this point takes over the vector of BRK and IRQ sequences
+		if cpu6502.interruptVector == cpu.IRQVectorAddress && cpu6502.nmiPending {
+			cpu6502.nmiPending = false
+			cpu6502.interruptVector = cpu.NMIVectorAddress
+		}
+	case 6:
+		cpu6502.operandAddress = types.Address(cpu6502.memory.Read(cpu6502.interruptVector))
+	case 7:
+		high := cpu6502.memory.Read(cpu6502.interruptVector + 1)
+		cpu6502.registers.Pc = types.CreateAddress(byte(cpu6502.operandAddress), high)
+	}
 
 	return false
 }
@@ -574,7 +582,7 @@ func (cpu6502 *Cpu6502) cmp(info cpu.OperationMethodArgument) bool {
 	operand := cpu6502.memory.Read(info.OperandAddress)
 	cpu6502.compare(cpu6502.registers.A, operand)
 
-	return false
+	return true
 }
 
 /*
@@ -631,7 +639,7 @@ func (cpu6502 *Cpu6502) compare(register byte, operand byte) {
 
 func (cpu6502 *Cpu6502) dec(info cpu.OperationMethodArgument) bool {
 	address := info.OperandAddress
-	operand := cpu6502.memory.Read(address)
+	operand := cpu6502.readOperand(address)
 
 	operand--
 	cpu6502.memory.Write(address, operand)
@@ -734,7 +742,7 @@ func (cpu6502 *Cpu6502) eor(info cpu.OperationMethodArgument) bool {
 	Absolute,X    INC oper,X    FE    3     7
 */
 func (cpu6502 *Cpu6502) inc(info cpu.OperationMethodArgument) bool {
-	value := cpu6502.memory.Read(info.OperandAddress)
+	value := cpu6502.readOperand(info.OperandAddress)
 	value += 1
 
 	cpu6502.memory.Write(info.OperandAddress, value)
@@ -805,11 +813,21 @@ func (cpu6502 *Cpu6502) jmp(info cpu.OperationMethodArgument) bool {
 	Absolute      JSR oper      20    3     6
 */
 func (cpu6502 *Cpu6502) jsr(info cpu.OperationMethodArgument) bool {
-	pc := cpu6502.registers.Pc - 1
-	cpu6502.pushStack(byte(pc >> 8))
-	cpu6502.pushStack(byte(pc & 0xFF))
-
-	cpu6502.registers.Pc = info.OperandAddress
+	switch cpu6502.instructionCycle {
+	case 2:
+		cpu6502.operandAddress = types.Address(cpu6502.fetch())
+	case 3:
+		// Internal operation, stack is read while the address low byte is stored
+		cpu6502.memory.Read(cpu6502.registers.StackPointerAddress())
+	case 4:
+		// PC points to the address high byte, which is the return address minus one
+		cpu6502.pushStack(byte(cpu6502.registers.Pc >> 8))
+	case 5:
+		cpu6502.pushStack(byte(cpu6502.registers.Pc & 0xFF))
+	case 6:
+		high := cpu6502.memory.Read(cpu6502.registers.Pc)
+		cpu6502.registers.Pc = types.CreateAddress(byte(cpu6502.operandAddress), high)
+	}
 
 	return false
 }
@@ -898,7 +916,7 @@ func (cpu6502 *Cpu6502) lsr(info cpu.OperationMethodArgument) bool {
 	if info.AddressMode == cpu.Implicit {
 		value = cpu6502.registers.A
 	} else {
-		value = cpu6502.memory.Read(info.OperandAddress)
+		value = cpu6502.readOperand(info.OperandAddress)
 	}
 
 	//cpu6502.Registers.CarryFlag = value & 0x01
@@ -1048,7 +1066,7 @@ func (cpu6502 *Cpu6502) rol(info cpu.OperationMethodArgument) bool {
 		cpu6502.registers.A |= cpu6502.registers.CarryFlag()
 		value = cpu6502.registers.A
 	} else {
-		value = cpu6502.memory.Read(info.OperandAddress)
+		value = cpu6502.readOperand(info.OperandAddress)
 		newCarry = value & 0x80 >> 7
 		value <<= 1
 		value |= cpu6502.registers.CarryFlag()
@@ -1084,7 +1102,7 @@ func (cpu6502 *Cpu6502) ror(info cpu.OperationMethodArgument) bool {
 		cpu6502.registers.A |= cpu6502.registers.CarryFlag() << 7
 		value = cpu6502.registers.A
 	} else {
-		value = cpu6502.memory.Read(info.OperandAddress)
+		value = cpu6502.readOperand(info.OperandAddress)
 		newCarry = value & 0x01
 		value >>= 1
 		value |= cpu6502.registers.CarryFlag() << 7
@@ -1109,12 +1127,20 @@ func (cpu6502 *Cpu6502) ror(info cpu.OperationMethodArgument) bool {
 	implied       RTI           40    1     6
 */
 func (cpu6502 *Cpu6502) rti(info cpu.OperationMethodArgument) bool {
-	statusRegister := cpu6502.popStack()
-	cpu6502.registers.LoadStatusRegisterIgnoring5and4(statusRegister)
-
-	lsb := cpu6502.popStack()
-	msb := cpu6502.popStack()
-	cpu6502.registers.Pc = types.CreateAddress(lsb, msb)
+	switch cpu6502.instructionCycle {
+	case 2:
+		cpu6502.memory.Read(cpu6502.registers.Pc)
+	case 3:
+		cpu6502.memory.Read(cpu6502.registers.StackPointerAddress())
+	case 4:
+		statusRegister := cpu6502.popStack()
+		cpu6502.registers.LoadStatusRegisterIgnoring5and4(statusRegister)
+	case 5:
+		cpu6502.operandAddress = types.Address(cpu6502.popStack())
+	case 6:
+		msb := cpu6502.popStack()
+		cpu6502.registers.Pc = types.CreateAddress(byte(cpu6502.operandAddress), msb)
+	}
 
 	return false
 }
@@ -1129,9 +1155,20 @@ func (cpu6502 *Cpu6502) rti(info cpu.OperationMethodArgument) bool {
 	implied       RTS           60    1     6
 */
 func (cpu6502 *Cpu6502) rts(info cpu.OperationMethodArgument) bool {
-	lsb := cpu6502.popStack()
-	msb := cpu6502.popStack()
-	cpu6502.registers.Pc = types.CreateAddress(lsb, msb) + 1
+	switch cpu6502.instructionCycle {
+	case 2:
+		cpu6502.memory.Read(cpu6502.registers.Pc)
+	case 3:
+		cpu6502.memory.Read(cpu6502.registers.StackPointerAddress())
+	case 4:
+		cpu6502.operandAddress = types.Address(cpu6502.popStack())
+	case 5:
+		cpu6502.operandAddress |= types.Address(cpu6502.popStack()) << 8
+	case 6:
+		// Pulled address is read while being incremented
+		cpu6502.memory.Read(cpu6502.operandAddress)
+		cpu6502.registers.Pc = cpu6502.operandAddress + 1
+	}
 
 	return false
 }
@@ -1153,7 +1190,12 @@ func (cpu6502 *Cpu6502) rts(info cpu.OperationMethodArgument) bool {
 	(Indirect),Y  SBC (oper),Y  F1    2     5*
 */
 func (cpu6502 *Cpu6502) sbc(info cpu.OperationMethodArgument) bool {
-	value := cpu6502.memory.Read(info.OperandAddress)
+	cpu6502.subtractWithBorrow(cpu6502.memory.Read(info.OperandAddress))
+
+	return true
+}
+
+func (cpu6502 *Cpu6502) subtractWithBorrow(value byte) {
 	borrow := (1 - cpu6502.registers.CarryFlag()) & 0x01 // == !CarryFlag
 	a := cpu6502.registers.A
 	result := a - value - borrow
@@ -1174,8 +1216,6 @@ func (cpu6502 *Cpu6502) sbc(info cpu.OperationMethodArgument) bool {
 	} else {
 		cpu6502.registers.SetCarryFlag(true)
 	}
-
-	return true
 }
 
 /*
@@ -16,14 +16,21 @@ import (
 	"testing"
 )
 
+// TestCpuInstructions runs Tom Harte's ProcessorTests for the NES 6502. They are not part of the repository,
+// copy the JSON files into assets/tests/tomharte-processortests/nes6502/v1 to run them.
 func TestCpuInstructions(t *testing.T) {
-	for _, filename := range findTestsFiles() {
+	files := findTestsFiles()
+	if len(files) == 0 {
+		t.Skip("ProcessorTests not found in assets/tests/tomharte-processortests/nes6502/v1")
+	}
+
+	for _, filename := range files {
 		//if i > 1 {
 		//	break
 		//}
 
-		if isUnofficialOpcode(filename) {
-			t.Log("Skipped unofficial " + filename)
+		if isUnstableOpcode(filename) {
+			t.Log("Skipped unstable " + filename)
 			continue
 		}
 
@@ -39,6 +46,9 @@ func TestCpuInstructions(t *testing.T) {
 
 				t.Run(operation.Name()+" "+cpuTest.Name, func(t *testing.T) {
 					cpu.Tick()
+					for !cpu.Complete() {
+						cpu.Tick()
+					}
 
 					assertExpectedCpuStatus(t, cpuTest.Final, cpu, cpuTest.Name+"("+operation.Name()+")")
 				})
@@ -47,116 +57,35 @@ func TestCpuInstructions(t *testing.T) {
 	}
 }
 
-func isUnofficialOpcode(filename string) bool {
-	unofficialList := []string{
+// isUnstableOpcode tells if the test file covers an opcode whose result depends on the chip,
+// or that halts the CPU. Those are not emulated the same way the tests were recorded.
+func isUnstableOpcode(filename string) bool {
+	unstableList := []string{
+		// JAM
 		"02.json",
-		"03.json",
-		"04.json",
-		"07.json",
-		"0b.json",
-		"0c.json",
-		"0f.json",
 		"12.json",
-		"13.json",
-		"14.json",
-		"17.json",
-		"1a.json",
-		"1b.json",
-		"1c.json",
-		"1f.json",
 		"22.json",
-		"23.json",
-		"27.json",
-		"2b.json",
-		"2f.json",
 		"32.json",
-		"33.json",
-		"34.json",
-		"37.json",
-		"3a.json",
-		"3b.json",
-		"3c.json",
-		"3f.json",
 		"42.json",
-		"43.json",
-		"44.json",
-		"47.json",
-		"4b.json",
-		"4f.json",
 		"52.json",
-		"53.json",
-		"54.json",
-		"57.json",
-		"5a.json",
-		"5b.json",
-		"5c.json",
-		"5f.json",
 		"62.json",
-		"63.json",
-		"64.json",
-		"67.json",
-		"6b.json",
-		"6f.json",
 		"72.json",
-		"73.json",
-		"74.json",
-		"77.json",
-		"7a.json",
-		"7b.json",
-		"7c.json",
-		"7f.json",
-		"80.json",
-		"82.json",
-		"83.json",
-		"87.json",
-		"89.json",
-		"8b.json",
-		"8f.json",
 		"92.json",
+		"b2.json",
+		"d2.json",
+		"f2.json",
+		// XAA, LXA
+		"8b.json",
+		"ab.json",
+		// SHA, TAS, SHY, SHX
 		"93.json",
-		"97.json",
 		"9b.json",
 		"9c.json",
 		"9e.json",
 		"9f.json",
-		"a3.json",
-		"a7.json",
-		"ab.json",
-		"af.json",
-		"b2.json",
-		"b3.json",
-		"b7.json",
-		"bb.json",
-		"bf.json",
-		"c2.json",
-		"c3.json",
-		"c7.json",
-		"cb.json",
-		"cf.json",
-		"d2.json",
-		"d3.json",
-		"d4.json",
-		"d7.json",
-		"da.json",
-		"db.json",
-		"dc.json",
-		"df.json",
-		"e2.json",
-		"e3.json",
-		"e7.json",
-		"eb.json",
-		"ef.json",
-		"f2.json",
-		"f3.json",
-		"f4.json",
-		"f7.json",
-		"fa.json",
-		"fb.json",
-		"fc.json",
-		"ff.json",
 	}
 
-	for _, banned := range unofficialList {
+	for _, banned := range unstableList {
 		if strings.HasSuffix(filename, banned) {
 			return true
 		}
@@ -47,6 +47,7 @@ func TestCpuNmi(t *testing.T) {
 			cpu.memory.Write(0xFFFB, byte(test.addressAtVector>>8))
 
 			cpu.nmi()
+			runInstruction(cpu)
 
 			assert.Equal(t, test.addressAtVector, cpu.registers.Pc)
 
@@ -77,8 +78,10 @@ func TestCpuIRQ(t *testing.T) {
 			cpu.memory.Write(0xFFFF, byte(test.addressAtVector>>8))
 
 			cpu.irq()
+			runInstruction(cpu)
 
 			assert.Equal(t, test.addressAtVector, cpu.registers.Pc)
+			assert.Equal(t, byte(1), cpu.registers.InterruptFlag(), "irq should mask further irqs")
 
 			assert.Equal(t, test.status, cpu.popStack(), "unexpected status on stack")
 
@@ -420,18 +423,21 @@ func TestBRK(t *testing.T) {
 	cpu := CreateCPUWithGamePak()
 	cpu.registers.Pc = programCounter
 	cpu.registers.Status = 0b11100011
+	cpu.memory.Write(programCounter, 0x00) // BRK opcode
 	cpu.memory.Write(types.Address(0xFFFE), types.LowNibble(expectedPc))
 	cpu.memory.Write(types.Address(0xFFFF), types.HighNibble(expectedPc))
 
-	cpu.brk(nescpu.OperationMethodArgument{nescpu.Implicit, 0x0000})
+	runInstruction(cpu)
 
-	assert.Equal(t, programCounter+1, cpu.read16(0x1FE))
+	// BRK skips the padding byte following its opcode
+	returnAddress := programCounter + 2
+	assert.Equal(t, returnAddress, cpu.read16(0x1FE))
 	// Stored status Registers in stack should be...
 	assert.Equal(t, byte(0b11110011), cpu.memory.Read(0x1FD))
 	assert.Equal(t, byte(1), cpu.registers.InterruptFlag())
 	assert.Equal(t, byte(0xF3), cpu.popStack(), "unexpected StatusRegister pushed in stack")
-	assert.Equal(t, types.LowNibble(programCounter+1), cpu.popStack(), "unexpected low nibble in stack pointer")
-	assert.Equal(t, types.HighNibble(programCounter+1), cpu.popStack(), "unexpected high nibble in stack pointer")
+	assert.Equal(t, types.LowNibble(returnAddress), cpu.popStack(), "unexpected low nibble in stack pointer")
+	assert.Equal(t, types.HighNibble(returnAddress), cpu.popStack(), "unexpected high nibble in stack pointer")
 
 	assert.Equal(t, expectedPc, cpu.registers.Pc)
 }
@@ -758,11 +764,12 @@ func TestJSR(t *testing.T) {
 	cpu.memory.Write(types.Address(0x202), 0x55) // LSB
 	cpu.memory.Write(types.Address(0x203), 0x05) // MSB
 
-	cpu.registers.Pc = 0x0203
-	cpu.jsr(nescpu.OperationMethodArgument{nescpu.Absolute, 0x202})
+	cpu.registers.Pc = 0x0201
+	runInstruction(cpu)
 
-	assert.Equal(t, types.Address(0x0202), cpu.registers.Pc)
-	assert.Equal(t, byte(0x02), cpu.popStack())
+	assert.Equal(t, types.Address(0x0555), cpu.registers.Pc)
+	// Pushed return address points to the last byte of JSR
+	assert.Equal(t, byte(0x03), cpu.popStack())
 	assert.Equal(t, byte(0x02), cpu.popStack())
 }
 
@@ -1066,8 +1073,10 @@ func TestRTI(t *testing.T) {
 	cpu.pushStack(types.LowNibble(pc))
 	// Push a StatusRegister into stack
 	cpu.pushStack(0xFF)
+	cpu.registers.Pc = 0x0600
+	cpu.memory.Write(0x0600, 0x40) // RTI opcode
 
-	cpu.rti(nescpu.OperationMethodArgument{AddressMode: nescpu.Implicit, OperandAddress: 0xFF})
+	runInstruction(cpu)
 
 	assert.Equal(t, pc, cpu.registers.Pc)
 	assert.Equal(t, byte(0xeF), cpu.registers.Status)
@@ -1079,8 +1088,10 @@ func TestRTS(t *testing.T) {
 	pc := types.Address(0x532)
 	cpu.pushStack(types.HighNibble(pc))
 	cpu.pushStack(types.LowNibble(pc))
+	cpu.registers.Pc = 0x0600
+	cpu.memory.Write(0x0600, 0x60) // RTS opcode
 
-	cpu.rts(nescpu.OperationMethodArgument{nescpu.Implicit, 0x00})
+	runInstruction(cpu)
 
 	expectedProgramCounter := types.Address(0x533)
 	assert.Equal(t, expectedProgramCounter, cpu.registers.Pc)
@@ -0,0 +1,88 @@
+package nes
+
+import "github.com/raulferras/nes-golang/src/nes/cpu"
+
+// IRQSource identifies each device wired to the CPU IRQ line.
+// The line is asserted while any of them pulls it down, and each one acknowledges its own IRQ.
+type IRQSource byte
+
+const (
+	IRQFrameCounter IRQSource = 1 << iota
+	IRQDMC
+	IRQMapper
+)
+
+// NMI and IRQ run the same sequence as BRK
+const opcodeBRK = 0x00
+
+// SetNMI updates the NMI line. NMI is edge triggered: it only becomes pending when the line goes from not asserted to asserted.
+func (cpu6502 *Cpu6502) SetNMI(asserted bool) {
+	if asserted && !cpu6502.nmiLine {
+		cpu6502.nmiPending = true
+	}
+	cpu6502.nmiLine = asserted
+}
+
+// SetIRQ asserts or releases the IRQ line on behalf of source. IRQ is level triggered.
+func (cpu6502 *Cpu6502) SetIRQ(source IRQSource, asserted bool) {
+	if asserted {
+		cpu6502.irqLine |= source
+	} else {
+		cpu6502.irqLine &^= source
+	}
+}
+
+// IRQ tells if any source is asserting the IRQ line
+func (cpu6502 *Cpu6502) IRQ() bool {
+	return cpu6502.irqLine != 0
+}
+
+// pollInterrupts has to be called after every CPU cycle, once interrupt lines are updated.
+// Interrupts are polled on the second to last cycle of each instruction, and serviced once it completes.
+// I flag is checked when polling, so CLI, SEI and PLP, which change it on their last cycle, take effect after next instruction.
+func (cpu6502 *Cpu6502) pollInterrupts() {
+	if cpu6502.interruptVector != 0 {
+		// Interrupt sequences do not poll, first instruction of the handler always runs
+		if cpu6502.Complete() {
+			cpu6502.interruptVector = 0
+			cpu6502.hardwareInterrupt = false
+		}
+		return
+	}
+
+	if cpu6502.opCyclesLeft == cpu6502.interruptPollCycle {
+		cpu6502.nmiRequested = cpu6502.nmiPending
+		cpu6502.irqRequested = cpu6502.IRQ() && cpu6502.registers.InterruptFlag() == 0
+	}
+	if !cpu6502.Complete() {
+		return
+	}
+
+	if cpu6502.nmiRequested {
+		cpu6502.nmiPending = false
+		cpu6502.nmi()
+	} else if cpu6502.irqRequested {
+		cpu6502.irq()
+	}
+	cpu6502.nmiRequested = false
+	cpu6502.irqRequested = false
+}
+
+// nmi makes next cycle start the Non Maskable Interrupt sequence. Status is pushed with B flag clear.
+func (cpu6502 *Cpu6502) nmi() {
+	cpu6502.interruptVector = cpu.NMIVectorAddress
+	cpu6502.hardwareInterrupt = true
+}
+
+// irq makes next cycle start the Interrupt Request sequence. Status is pushed with B flag clear.
+func (cpu6502 *Cpu6502) irq() {
+	cpu6502.interruptVector = cpu.IRQVectorAddress
+	cpu6502.hardwareInterrupt = true
+}
+
+// startInterruptSequence runs the first cycle of NMI and IRQ sequences, where the opcode fetched is discarded.
+// Following cycles are the ones of BRK.
+func (cpu6502 *Cpu6502) startInterruptSequence() {
+	cpu6502.memory.Read(cpu6502.registers.Pc)
+	cpu6502.startInstruction(opcodeBRK)
+}
@@ -0,0 +1,204 @@
+package nes
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const testNMIHandler = types.Address(0x3000)
+const testIRQHandler = types.Address(0x4000)
+
+// anInterruptibleCPU returns a CPU running program from 0x0200, followed by NOPs. Interrupt handlers are NOPs too.
+func anInterruptibleCPU(program ...byte) *Cpu6502 {
+	cpu := CreateCPUWithGamePak()
+	for _, start := range []types.Address{0x0200, testNMIHandler, testIRQHandler} {
+		for address := start; address < start+0x10; address++ {
+			cpu.memory.Write(address, 0xEA)
+		}
+	}
+	for i, value := range program {
+		cpu.memory.Write(types.Address(0x0200+i), value)
+	}
+	cpu.memory.Write(0xFFFA, byte(testNMIHandler&0xFF))
+	cpu.memory.Write(0xFFFB, byte(testNMIHandler>>8))
+	cpu.memory.Write(0xFFFE, byte(testIRQHandler&0xFF))
+	cpu.memory.Write(0xFFFF, byte(testIRQHandler>>8))
+	cpu.registers.Pc = 0x0200
+	cpu.registers.Sp = 0xFD
+
+	return cpu
+}
+
+// runInstruction runs CPU cycles until current instruction, or interrupt sequence, completes
+func runInstruction(cpu *Cpu6502) {
+	runCPUCycle(cpu)
+	for !cpu.Complete() {
+		runCPUCycle(cpu)
+	}
+}
+
+func runCPUCycle(cpu *Cpu6502) {
+	cpu.Tick()
+	cpu.pollInterrupts()
+}
+
+func TestCpu_IRQ_line_is_wired_or(t *testing.T) {
+	cpu := anInterruptibleCPU()
+
+	cpu.SetIRQ(IRQFrameCounter, true)
+	cpu.SetIRQ(IRQMapper, true)
+	cpu.SetIRQ(IRQFrameCounter, false)
+	assert.True(t, cpu.IRQ(), "mapper is still asserting IRQ")
+
+	cpu.SetIRQ(IRQMapper, false)
+	assert.False(t, cpu.IRQ())
+}
+
+func TestCpu_IRQ_is_serviced_once_instruction_completes(t *testing.T) {
+	cpu := anInterruptibleCPU()
+	cpu.registers.SetInterruptFlag(false)
+	cpu.SetIRQ(IRQDMC, true)
+
+	runInstruction(cpu)
+	assert.Equal(t, types.Address(0x0201), cpu.registers.Pc, "IRQ should wait for the instruction to complete")
+
+	runInstruction(cpu)
+	assert.Equal(t, testIRQHandler, cpu.registers.Pc)
+	assert.Equal(t, byte(0x0201&0xFF), cpu.memory.Read(0x01FC), "should return to next instruction")
+	assert.Equal(t, byte(0), cpu.memory.Read(0x01FB)&0x10, "B flag should be clear")
+}
+
+func TestCpu_IRQ_is_masked_by_I_flag(t *testing.T) {
+	cpu := anInterruptibleCPU()
+	cpu.registers.SetInterruptFlag(true)
+	cpu.SetIRQ(IRQDMC, true)
+
+	runInstruction(cpu)
+	runInstruction(cpu)
+
+	assert.Equal(t, types.Address(0x0202), cpu.registers.Pc)
+}
+
+func TestCpu_CLI_takes_effect_after_next_instruction(t *testing.T) {
+	cpu := anInterruptibleCPU(0x58) // CLI
+	cpu.registers.SetInterruptFlag(true)
+	cpu.SetIRQ(IRQFrameCounter, true)
+
+	runInstruction(cpu)
+	assert.Equal(t, types.Address(0x0201), cpu.registers.Pc, "IRQ should not be serviced right after CLI")
+
+	runInstruction(cpu)
+	runInstruction(cpu)
+	assert.Equal(t, testIRQHandler, cpu.registers.Pc)
+}
+
+func TestCpu_SEI_lets_a_last_IRQ_through(t *testing.T) {
+	cpu := anInterruptibleCPU(0x78) // SEI
+	cpu.registers.SetInterruptFlag(false)
+	cpu.SetIRQ(IRQFrameCounter, true)
+
+	runInstruction(cpu)
+	runInstruction(cpu)
+
+	assert.Equal(t, testIRQHandler, cpu.registers.Pc)
+}
+
+func TestCpu_taken_branch_without_page_cross_delays_interrupts(t *testing.T) {
+	cpu := anInterruptibleCPU(0xF0, 0x00) // BEQ +0
+	cpu.registers.SetZeroFlag(true)
+
+	runCPUCycle(cpu)
+	runCPUCycle(cpu)
+	cpu.SetNMI(true)
+	runCPUCycle(cpu)
+	assert.Equal(t, types.Address(0x0202), cpu.registers.Pc, "NMI should wait for next instruction")
+
+	runInstruction(cpu)
+	runInstruction(cpu)
+	assert.Equal(t, testNMIHandler, cpu.registers.Pc)
+}
+
+func TestCpu_NMI_is_edge_triggered(t *testing.T) {
+	cpu := anInterruptibleCPU()
+	cpu.SetNMI(true)
+
+	runInstruction(cpu)
+	runInstruction(cpu)
+	assert.Equal(t, testNMIHandler, cpu.registers.Pc)
+
+	runInstruction(cpu)
+	runInstruction(cpu)
+	runInstruction(cpu)
+	assert.Equal(t, testNMIHandler+3, cpu.registers.Pc, "NMI line still asserted should not trigger again")
+}
+
+func TestCpu_NMI_hijacks_BRK(t *testing.T) {
+	cpu := anInterruptibleCPU(0x00) // BRK
+
+	runCPUCycle(cpu)
+	runCPUCycle(cpu)
+	cpu.SetNMI(true)
+	runInstruction(cpu)
+
+	assert.Equal(t, testNMIHandler, cpu.registers.Pc)
+	assert.Equal(t, byte(0x10), cpu.memory.Read(0x01FB)&0x10, "B flag should be set, as pushed by BRK")
+	assert.False(t, cpu.nmiPending, "NMI should have been serviced")
+}
+
+func TestCpu_late_NMI_does_not_hijack_BRK(t *testing.T) {
+	cpu := anInterruptibleCPU(0x00) // BRK
+
+	for i := 0; i < 5; i++ {
+		runCPUCycle(cpu)
+	}
+	cpu.SetNMI(true)
+	runInstruction(cpu)
+	assert.Equal(t, testIRQHandler, cpu.registers.Pc)
+
+	runInstruction(cpu)
+	runInstruction(cpu)
+	assert.Equal(t, testNMIHandler, cpu.registers.Pc, "NMI should be serviced after first instruction of the handler")
+}
+
+// The APU frame counter raises a real IRQ while interrupts are disabled, and the program then runs CLI
+// followed by the instruction under test. The IRQ handler stores X into $00 and counts IRQs into $01.
+func TestNes_CLI_latency_with_APU_frame_IRQ(t *testing.T) {
+	tests := []struct {
+		name      string
+		afterCLI  byte
+		expectedX byte
+	}{
+		{"IRQ is taken after the instruction following CLI", 0xE8, 1}, // INX
+		{"SEI following CLI lets the IRQ through", 0x78, 0},           // SEI
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := []byte{
+				0x78,       // SEI
+				0xA9, 0x00, //       LDA #0
+				0x8D, 0x17, 0x40, // STA $4017, 4 step sequence with frame IRQ
+				0xA2, 0x40, //       LDX #$40, wait for ~80000 cycles
+				0xA0, 0x00, //       outer: LDY #0
+				0x88,       //       inner: DEY
+				0xD0, 0xFD, //       BNE inner
+				0xCA,       //       DEX
+				0xD0, 0xF8, //       BNE outer
+				0x58,             // CLI
+				tt.afterCLI,      //
+				0xE8,             // INX
+				0x4C, 0x13, 0x80, // JMP *
+				0x86, 0x00, //       irq: STX $00
+				0xE6, 0x01, //       INC $01
+				0x4C, 0x1A, 0x80, // JMP *
+			}
+			nes := aNesRunningProgram(nromPRG(program, 0x8016, 0x8016))
+
+			runFrames(nes, 6)
+
+			assert.Equal(t, byte(1), nes.bus.Peek(0x01), "IRQ should be taken once")
+			assert.Equal(t, tt.expectedX, nes.bus.Peek(0x00))
+		})
+	}
+}
@@ -0,0 +1,462 @@
+package nes
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/cpu"
+	"github.com/raulferras/nes-golang/src/nes/types"
+)
+
+// Unofficial opcodes.
+// The 6502 decodes every byte, so the 105 undocumented slots still do something:
+// most of them combine two official operations that share the same decoding lines.
+// https://www.nesdev.org/wiki/CPU_unofficial_opcodes
+// https://www.nesdev.org/undocumented_opcodes.txt
+
+// Magic constant ORed into A by the unstable XAA and LXA. It varies between chips.
+const unstableMagic = 0xEE
+
+/*
+	NOP (IGN)  Read and ignore
+	---                           N Z C I D V
+								  - - - - - -
+
+	addressing    assembler    opc           bytes  cycles
+	--------------------------------------------
+	Immediate     NOP #oper     80,82,89,C2,E2  2     2
+	zeropage      NOP oper      04,44,64        2     3
+	zeropage,X    NOP oper,X    14,34,...,F4    2     4
+	Absolute      NOP oper      0C              3     4
+	Absolute,X    NOP oper,X    1C,3C,...,FC    3     4*
+*/
+func (cpu6502 *Cpu6502) ign(info cpu.OperationMethodArgument) bool {
+	cpu6502.memory.Read(info.OperandAddress)
+
+	return true
+}
+
+/*
+	JAM  Halts the CPU
+	The data bus is filled with 0xFF and only a reset brings the CPU back.
+
+	addressing    assembler    opc                        bytes  cycles
+	--------------------------------------------
+	implied       JAM           02,12,22,...,B2,D2,F2       1     2
+*/
+func (cpu6502 *Cpu6502) jam(info cpu.OperationMethodArgument) bool {
+	// Keep fetching the same opcode forever
+	cpu6502.registers.Pc--
+
+	return false
+}
+
+/*
+	LAX  Load Accumulator and Index X with Memory
+	M -> A -> X                   N Z C I D V
+								  + + - - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	zeropage      LAX oper      A7    2     3
+	zeropage,Y    LAX oper,Y    B7    2     4
+	Absolute      LAX oper      AF    3     4
+	Absolute,Y    LAX oper,Y    BF    3     4*
+	(Indirect,X)  LAX (oper,X)  A3    2     6
+	(Indirect),Y  LAX (oper),Y  B3    2     5*
+*/
+func (cpu6502 *Cpu6502) lax(info cpu.OperationMethodArgument) bool {
+	cpu6502.registers.A = cpu6502.memory.Read(info.OperandAddress)
+	cpu6502.registers.X = cpu6502.registers.A
+	cpu6502.registers.UpdateZeroFlag(cpu6502.registers.A)
+	cpu6502.registers.UpdateNegativeFlag(cpu6502.registers.A)
+
+	return true
+}
+
+/*
+	LXA  (A OR Magic) AND oper -> A -> X. Unstable
+	                              N Z C I D V
+								  + + - - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	Immediate     LXA #oper     AB    2     2
+*/
+func (cpu6502 *Cpu6502) lxa(info cpu.OperationMethodArgument) bool {
+	value := cpu6502.memory.Read(info.OperandAddress)
+	cpu6502.registers.A = (cpu6502.registers.A | unstableMagic) & value
+	cpu6502.registers.X = cpu6502.registers.A
+	cpu6502.registers.UpdateZeroFlag(cpu6502.registers.A)
+	cpu6502.registers.UpdateNegativeFlag(cpu6502.registers.A)
+
+	return false
+}
+
+/*
+	SAX  Store A AND X
+	A AND X -> M                  N Z C I D V
+								  - - - - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	zeropage      SAX oper      87    2     3
+	zeropage,Y    SAX oper,Y    97    2     4
+	Absolute      SAX oper      8F    3     4
+	(Indirect,X)  SAX (oper,X)  83    2     6
+*/
+func (cpu6502 *Cpu6502) sax(info cpu.OperationMethodArgument) bool {
+	cpu6502.memory.Write(info.OperandAddress, cpu6502.registers.A&cpu6502.registers.X)
+
+	return false
+}
+
+/*
+	DCP  DEC oper + CMP oper
+	M - 1 -> M, A - M             N Z C I D V
+								  + + + - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	zeropage      DCP oper      C7    2     5
+	zeropage,X    DCP oper,X    D7    2     6
+	Absolute      DCP oper      CF    3     6
+	Absolute,X    DCP oper,X    DF    3     7
+	Absolute,Y    DCP oper,Y    DB    3     7
+	(Indirect,X)  DCP (oper,X)  C3    2     8
+	(Indirect),Y  DCP (oper),Y  D3    2     8
+*/
+func (cpu6502 *Cpu6502) dcp(info cpu.OperationMethodArgument) bool {
+	value := cpu6502.readOperand(info.OperandAddress) - 1
+	cpu6502.memory.Write(info.OperandAddress, value)
+	cpu6502.compare(cpu6502.registers.A, value)
+
+	return false
+}
+
+/*
+	ISC (ISB)  INC oper + SBC oper
+	M + 1 -> M, A - M - C -> A    N Z C I D V
+								  + + + - - +
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	zeropage      ISC oper      E7    2     5
+	zeropage,X    ISC oper,X    F7    2     6
+	Absolute      ISC oper      EF    3     6
+	Absolute,X    ISC oper,X    FF    3     7
+	Absolute,Y    ISC oper,Y    FB    3     7
+	(Indirect,X)  ISC (oper,X)  E3    2     8
+	(Indirect),Y  ISC (oper),Y  F3    2     8
+*/
+func (cpu6502 *Cpu6502) isc(info cpu.OperationMethodArgument) bool {
+	value := cpu6502.readOperand(info.OperandAddress) + 1
+	cpu6502.memory.Write(info.OperandAddress, value)
+	cpu6502.subtractWithBorrow(value)
+
+	return false
+}
+
+/*
+	SLO  ASL oper + ORA oper
+	M = C <- [76543210] <- 0, A OR M -> A     N Z C I D V
+											  + + + - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	zeropage      SLO oper      07    2     5
+	zeropage,X    SLO oper,X    17    2     6
+	Absolute      SLO oper      0F    3     6
+	Absolute,X    SLO oper,X    1F    3     7
+	Absolute,Y    SLO oper,Y    1B    3     7
+	(Indirect,X)  SLO (oper,X)  03    2     8
+	(Indirect),Y  SLO (oper),Y  13    2     8
+*/
+func (cpu6502 *Cpu6502) slo(info cpu.OperationMethodArgument) bool {
+	value := cpu6502.readOperand(info.OperandAddress)
+	cpu6502.registers.SetCarryFlag(value&0x80 == 0x80)
+	value <<= 1
+	cpu6502.memory.Write(info.OperandAddress, value)
+
+	cpu6502.registers.A |= value
+	cpu6502.registers.UpdateZeroFlag(cpu6502.registers.A)
+	cpu6502.registers.UpdateNegativeFlag(cpu6502.registers.A)
+
+	return false
+}
+
+/*
+	RLA  ROL oper + AND oper
+	M = C <- [76543210] <- C, A AND M -> A    N Z C I D V
+											  + + + - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	zeropage      RLA oper      27    2     5
+	zeropage,X    RLA oper,X    37    2     6
+	Absolute      RLA oper      2F    3     6
+	Absolute,X    RLA oper,X    3F    3     7
+	Absolute,Y    RLA oper,Y    3B    3     7
+	(Indirect,X)  RLA (oper,X)  23    2     8
+	(Indirect),Y  RLA (oper),Y  33    2     8
+*/
+func (cpu6502 *Cpu6502) rla(info cpu.OperationMethodArgument) bool {
+	value := cpu6502.readOperand(info.OperandAddress)
+	carryIn := cpu6502.registers.CarryFlag()
+	cpu6502.registers.SetCarryFlag(value&0x80 == 0x80)
+	value = value<<1 | carryIn
+	cpu6502.memory.Write(info.OperandAddress, value)
+
+	cpu6502.registers.A &= value
+	cpu6502.registers.UpdateZeroFlag(cpu6502.registers.A)
+	cpu6502.registers.UpdateNegativeFlag(cpu6502.registers.A)
+
+	return false
+}
+
+/*
+	SRE  LSR oper + EOR oper
+	M = 0 -> [76543210] -> C, A EOR M -> A    N Z C I D V
+											  + + + - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	zeropage      SRE oper      47    2     5
+	zeropage,X    SRE oper,X    57    2     6
+	Absolute      SRE oper      4F    3     6
+	Absolute,X    SRE oper,X    5F    3     7
+	Absolute,Y    SRE oper,Y    5B    3     7
+	(Indirect,X)  SRE (oper,X)  43    2     8
+	(Indirect),Y  SRE (oper),Y  53    2     8
+*/
+func (cpu6502 *Cpu6502) sre(info cpu.OperationMethodArgument) bool {
+	value := cpu6502.readOperand(info.OperandAddress)
+	cpu6502.registers.SetCarryFlag(value&0x01 == 0x01)
+	value >>= 1
+	cpu6502.memory.Write(info.OperandAddress, value)
+
+	cpu6502.registers.A ^= value
+	cpu6502.registers.UpdateZeroFlag(cpu6502.registers.A)
+	cpu6502.registers.UpdateNegativeFlag(cpu6502.registers.A)
+
+	return false
+}
+
+/*
+	RRA  ROR oper + ADC oper
+	M = C -> [76543210] -> C, A + M + C -> A, C    N Z C I D V
+												   + + + - - +
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	zeropage      RRA oper      67    2     5
+	zeropage,X    RRA oper,X    77    2     6
+	Absolute      RRA oper      6F    3     6
+	Absolute,X    RRA oper,X    7F    3     7
+	Absolute,Y    RRA oper,Y    7B    3     7
+	(Indirect,X)  RRA (oper,X)  63    2     8
+	(Indirect),Y  RRA (oper),Y  73    2     8
+*/
+func (cpu6502 *Cpu6502) rra(info cpu.OperationMethodArgument) bool {
+	value := cpu6502.readOperand(info.OperandAddress)
+	carryIn := cpu6502.registers.CarryFlag()
+	cpu6502.registers.SetCarryFlag(value&0x01 == 0x01)
+	value = value>>1 | carryIn<<7
+	cpu6502.memory.Write(info.OperandAddress, value)
+
+	cpu6502.addWithCarry(value)
+
+	return false
+}
+
+/*
+	ANC  AND oper + set C as ASL
+	A AND M -> A, N -> C          N Z C I D V
+								  + + + - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	Immediate     ANC #oper     0B    2     2
+	Immediate     ANC #oper     2B    2     2
+*/
+func (cpu6502 *Cpu6502) anc(info cpu.OperationMethodArgument) bool {
+	cpu6502.registers.A &= cpu6502.memory.Read(info.OperandAddress)
+	cpu6502.registers.UpdateZeroFlag(cpu6502.registers.A)
+	cpu6502.registers.UpdateNegativeFlag(cpu6502.registers.A)
+	cpu6502.registers.SetCarryFlag(cpu6502.registers.A&0x80 == 0x80)
+
+	return false
+}
+
+/*
+	ALR (ASR)  AND oper + LSR
+	A AND M -> A, 0 -> [76543210] -> C    N Z C I D V
+										  + + + - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	Immediate     ALR #oper     4B    2     2
+*/
+func (cpu6502 *Cpu6502) alr(info cpu.OperationMethodArgument) bool {
+	value := cpu6502.registers.A & cpu6502.memory.Read(info.OperandAddress)
+	cpu6502.registers.SetCarryFlag(value&0x01 == 0x01)
+	cpu6502.registers.A = value >> 1
+	cpu6502.registers.UpdateZeroFlag(cpu6502.registers.A)
+	cpu6502.registers.UpdateNegativeFlag(cpu6502.registers.A)
+
+	return false
+}
+
+/*
+	ARR  AND oper + ROR
+	A AND M -> A, C -> [76543210] -> C    N Z C I D V
+										  + + + - - +
+
+	Carry is bit 6 of the result, and Overflow is bit 6 XOR bit 5.
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	Immediate     ARR #oper     6B    2     2
+*/
+func (cpu6502 *Cpu6502) arr(info cpu.OperationMethodArgument) bool {
+	value := cpu6502.registers.A & cpu6502.memory.Read(info.OperandAddress)
+	cpu6502.registers.A = value>>1 | cpu6502.registers.CarryFlag()<<7
+	cpu6502.registers.UpdateZeroFlag(cpu6502.registers.A)
+	cpu6502.registers.UpdateNegativeFlag(cpu6502.registers.A)
+
+	bit6 := cpu6502.registers.A >> 6 & 0x01
+	bit5 := cpu6502.registers.A >> 5 & 0x01
+	cpu6502.registers.SetCarryFlag(bit6 == 1)
+	cpu6502.registers.SetOverflowFlag(bit6^bit5 == 1)
+
+	return false
+}
+
+/*
+	AXS (SBX)  CMP and DEX at once
+	(A AND X) - M -> X            N Z C I D V
+								  + + + - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	Immediate     AXS #oper     CB    2     2
+*/
+func (cpu6502 *Cpu6502) axs(info cpu.OperationMethodArgument) bool {
+	value := cpu6502.memory.Read(info.OperandAddress)
+	andResult := cpu6502.registers.A & cpu6502.registers.X
+	cpu6502.compare(andResult, value)
+	cpu6502.registers.X = andResult - value
+
+	return false
+}
+
+/*
+	XAA (ANE)  (A OR Magic) AND X AND oper -> A. Unstable
+	                              N Z C I D V
+								  + + - - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	Immediate     XAA #oper     8B    2     2
+*/
+func (cpu6502 *Cpu6502) xaa(info cpu.OperationMethodArgument) bool {
+	value := cpu6502.memory.Read(info.OperandAddress)
+	cpu6502.registers.A = (cpu6502.registers.A | unstableMagic) & cpu6502.registers.X & value
+	cpu6502.registers.UpdateZeroFlag(cpu6502.registers.A)
+	cpu6502.registers.UpdateNegativeFlag(cpu6502.registers.A)
+
+	return false
+}
+
+/*
+	LAS (LAR)  M AND SP -> A, X, SP
+	                              N Z C I D V
+								  + + - - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	Absolute,Y    LAS oper,Y    BB    3     4*
+*/
+func (cpu6502 *Cpu6502) las(info cpu.OperationMethodArgument) bool {
+	value := cpu6502.memory.Read(info.OperandAddress) & cpu6502.registers.Sp
+	cpu6502.registers.A = value
+	cpu6502.registers.X = value
+	cpu6502.registers.Sp = value
+	cpu6502.registers.UpdateZeroFlag(value)
+	cpu6502.registers.UpdateNegativeFlag(value)
+
+	return true
+}
+
+/*
+	SHA (AHX)  A AND X AND (H + 1) -> M. Unstable
+	                              N Z C I D V
+								  - - - - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	Absolute,Y    SHA oper,Y    9F    3     5
+	(Indirect),Y  SHA (oper),Y  93    2     6
+*/
+func (cpu6502 *Cpu6502) sha(info cpu.OperationMethodArgument) bool {
+	cpu6502.storeAndHighByte(info, cpu6502.registers.A&cpu6502.registers.X, cpu6502.registers.Y)
+
+	return false
+}
+
+/*
+	SHX (SXA)  X AND (H + 1) -> M. Unstable
+	                              N Z C I D V
+								  - - - - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	Absolute,Y    SHX oper,Y    9E    3     5
+*/
+func (cpu6502 *Cpu6502) shx(info cpu.OperationMethodArgument) bool {
+	cpu6502.storeAndHighByte(info, cpu6502.registers.X, cpu6502.registers.Y)
+
+	return false
+}
+
+/*
+	SHY (SYA)  Y AND (H + 1) -> M. Unstable
+	                              N Z C I D V
+								  - - - - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	Absolute,X    SHY oper,X    9C    3     5
+*/
+func (cpu6502 *Cpu6502) shy(info cpu.OperationMethodArgument) bool {
+	cpu6502.storeAndHighByte(info, cpu6502.registers.Y, cpu6502.registers.X)
+
+	return false
+}
+
+/*
+	TAS (XAS)  A AND X -> SP, A AND X AND (H + 1) -> M. Unstable
+	                              N Z C I D V
+								  - - - - - -
+
+	addressing    assembler    opc  bytes  cycles
+	--------------------------------------------
+	Absolute,Y    TAS oper,Y    9B    3     5
+*/
+func (cpu6502 *Cpu6502) tas(info cpu.OperationMethodArgument) bool {
+	cpu6502.registers.Sp = cpu6502.registers.A & cpu6502.registers.X
+	cpu6502.storeAndHighByte(info, cpu6502.registers.Sp, cpu6502.registers.Y)
+
+	return false
+}
+
+// storeAndHighByte writes value AND (high byte of the base address + 1).
+// When indexing crosses a page, the high byte of the target address is replaced by the written value.
+func (cpu6502 *Cpu6502) storeAndHighByte(info cpu.OperationMethodArgument, value byte, index byte) {
+	address := info.OperandAddress
+	base := address - types.Address(index)
+	value &= byte(base>>8) + 1
+
+	if memoryPageDiffer(base, address) {
+		address = types.CreateAddress(byte(address), value)
+	}
+
+	cpu6502.memory.Write(address, value)
+}
@@ -0,0 +1,270 @@
+package nes
+
+import (
+	nescpu "github.com/raulferras/nes-golang/src/nes/cpu"
+	"github.com/raulferras/nes-golang/src/nes/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAllOpcodesAreImplemented(t *testing.T) {
+	cpu := CreateCPUWithGamePak()
+
+	for opcode := 0; opcode < 256; opcode++ {
+		instruction := cpu.GetOperation(byte(opcode))
+		assert.NotNil(t, instruction.Method(), "opcode 0x%02X has no method", opcode)
+		assert.NotZero(t, instruction.Cycles(), "opcode 0x%02X has no cycles", opcode)
+		assert.NotZero(t, instruction.Size(), "opcode 0x%02X has no size", opcode)
+	}
+}
+
+func TestUnofficialOpcodesCycles(t *testing.T) {
+	cases := []struct {
+		name           string
+		program        []byte
+		x              byte
+		y              byte
+		expectedCycles byte
+	}{
+		{"NOP implied", []byte{0x1A}, 0, 0, 2},
+		{"NOP immediate", []byte{0x80, 0x00}, 0, 0, 2},
+		{"NOP zero page", []byte{0x04, 0x10}, 0, 0, 3},
+		{"NOP absolute,X", []byte{0x1C, 0x00, 0x03}, 0x10, 0, 4},
+		{"NOP absolute,X crossing page", []byte{0x1C, 0xF8, 0x03}, 0x10, 0, 5},
+		{"LAX (indirect),Y crossing page", []byte{0xB3, 0x10}, 0, 0xFF, 6},
+		{"LAS absolute,Y crossing page", []byte{0xBB, 0xF8, 0x03}, 0, 0x10, 5},
+		{"DCP absolute,Y never adds a cycle", []byte{0xDB, 0xF8, 0x03}, 0, 0x10, 7},
+		{"ISC (indirect),Y", []byte{0xF3, 0x10}, 0, 0xFF, 8},
+		{"SHA absolute,Y crossing page", []byte{0x9F, 0xF8, 0x03}, 0xFF, 0x10, 5},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu := CreateCPUWithGamePak()
+			cpu.ResetToAddress(0x0200)
+			for i, value := range tt.program {
+				cpu.memory.Write(0x0200+types.Address(i), value)
+			}
+			// Pointer used by indirect modes
+			cpu.memory.Write(0x10, 0x80)
+			cpu.memory.Write(0x11, 0x03)
+			cpu.registers.X = tt.x
+			cpu.registers.Y = tt.y
+
+			cycles := byte(0)
+			for {
+				cycles++
+				if left, _ := cpu.Tick(); left == 0 {
+					break
+				}
+			}
+
+			assert.Equal(t, tt.expectedCycles, cycles)
+			assert.Equal(t, types.Address(0x0200+len(tt.program)), cpu.registers.Pc)
+		})
+	}
+}
+
+func TestLAX(t *testing.T) {
+	cpu := CreateCPUWithGamePak()
+	cpu.memory.Write(0x100, 0x80)
+
+	extraCycle := cpu.lax(nescpu.OperationMethodArgument{AddressMode: nescpu.Absolute, OperandAddress: 0x100})
+
+	assert.Equal(t, byte(0x80), cpu.registers.A)
+	assert.Equal(t, byte(0x80), cpu.registers.X)
+	assert.Equal(t, byte(1), cpu.registers.NegativeFlag())
+	assert.Equal(t, byte(0), cpu.registers.ZeroFlag())
+	assert.True(t, extraCycle)
+}
+
+func TestSAX(t *testing.T) {
+	cpu := CreateCPUWithGamePak()
+	cpu.registers.A = 0b11001100
+	cpu.registers.X = 0b10101010
+	cpu.registers.Status = 0
+
+	cpu.sax(nescpu.OperationMethodArgument{AddressMode: nescpu.Absolute, OperandAddress: 0x100})
+
+	assert.Equal(t, byte(0b10001000), cpu.memory.Read(0x100))
+	assert.Equal(t, byte(0), cpu.registers.Status, "SAX does not affect flags")
+}
+
+func TestDCP(t *testing.T) {
+	cases := []struct {
+		name          string
+		a             byte
+		value         byte
+		expectedValue byte
+		expectedCarry byte
+		expectedZero  byte
+	}{
+		{"A greater than M-1", 0x10, 0x05, 0x04, 1, 0},
+		{"A equals M-1", 0x04, 0x05, 0x04, 1, 1},
+		{"A less than M-1", 0x01, 0x00, 0xFF, 0, 0},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu := CreateCPUWithGamePak()
+			cpu.registers.A = tt.a
+			cpu.memory.Write(0x100, tt.value)
+
+			cpu.dcp(nescpu.OperationMethodArgument{AddressMode: nescpu.Absolute, OperandAddress: 0x100})
+
+			assert.Equal(t, tt.expectedValue, cpu.memory.Read(0x100))
+			assert.Equal(t, tt.expectedCarry, cpu.registers.CarryFlag())
+			assert.Equal(t, tt.expectedZero, cpu.registers.ZeroFlag())
+		})
+	}
+}
+
+func TestISC(t *testing.T) {
+	cpu := CreateCPUWithGamePak()
+	cpu.registers.A = 0x10
+	cpu.registers.SetCarryFlag(true)
+	cpu.memory.Write(0x100, 0x04)
+
+	cpu.isc(nescpu.OperationMethodArgument{AddressMode: nescpu.Absolute, OperandAddress: 0x100})
+
+	assert.Equal(t, byte(0x05), cpu.memory.Read(0x100))
+	assert.Equal(t, byte(0x0B), cpu.registers.A)
+	assert.Equal(t, byte(1), cpu.registers.CarryFlag())
+}
+
+func TestShiftAndCombineOpcodes(t *testing.T) {
+	cases := []struct {
+		name          string
+		method        func(cpu *Cpu6502) nescpu.OperationMethod
+		a             byte
+		carry         bool
+		value         byte
+		expectedValue byte
+		expectedA     byte
+		expectedCarry byte
+	}{
+		{"SLO", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.slo }, 0x01, false, 0x81, 0x02, 0x03, 1},
+		{"RLA", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.rla }, 0x0F, true, 0x81, 0x03, 0x03, 1},
+		{"SRE", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.sre }, 0xFF, false, 0x03, 0x01, 0xFE, 1},
+		{"RRA", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.rra }, 0x10, true, 0x02, 0x81, 0x91, 0},
+		{"RRA carry from ROR goes into ADC", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.rra }, 0x10, false, 0x03, 0x01, 0x12, 0},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu := CreateCPUWithGamePak()
+			cpu.registers.A = tt.a
+			cpu.registers.SetCarryFlag(tt.carry)
+			cpu.memory.Write(0x100, tt.value)
+
+			extraCycle := tt.method(cpu)(nescpu.OperationMethodArgument{AddressMode: nescpu.Absolute, OperandAddress: 0x100})
+
+			assert.Equal(t, tt.expectedValue, cpu.memory.Read(0x100), "unexpected value in memory")
+			assert.Equal(t, tt.expectedA, cpu.registers.A, "unexpected register A")
+			assert.Equal(t, tt.expectedCarry, cpu.registers.CarryFlag(), "unexpected carry")
+			assert.False(t, extraCycle)
+		})
+	}
+}
+
+func TestImmediateUnofficialOpcodes(t *testing.T) {
+	cases := []struct {
+		name             string
+		method           func(cpu *Cpu6502) nescpu.OperationMethod
+		a                byte
+		x                byte
+		carry            bool
+		operand          byte
+		expectedA        byte
+		expectedX        byte
+		expectedCarry    byte
+		expectedOverflow byte
+	}{
+		{"ANC negative sets carry", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.anc }, 0xF0, 0, false, 0x80, 0x80, 0, 1, 0},
+		{"ANC positive clears carry", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.anc }, 0xF0, 0, true, 0x70, 0x70, 0, 0, 0},
+		{"ALR", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.alr }, 0xFF, 0, false, 0x03, 0x01, 0, 1, 0},
+		{"ARR bit 6 set", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.arr }, 0xFF, 0, false, 0x80, 0x40, 0, 1, 1},
+		{"ARR bits 6 and 5 set", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.arr }, 0xFF, 0, true, 0x60, 0xB0, 0, 0, 1},
+		{"ARR carry in", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.arr }, 0xFF, 0, true, 0xC0, 0xE0, 0, 1, 0},
+		{"AXS", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.axs }, 0x0F, 0x07, false, 0x02, 0x0F, 0x05, 1, 0},
+		{"AXS borrow", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.axs }, 0x0F, 0x07, true, 0x08, 0x0F, 0xFF, 0, 0},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu := CreateCPUWithGamePak()
+			cpu.registers.A = tt.a
+			cpu.registers.X = tt.x
+			cpu.registers.SetCarryFlag(tt.carry)
+			cpu.registers.SetOverflowFlag(false)
+			cpu.memory.Write(0x100, tt.operand)
+
+			tt.method(cpu)(nescpu.OperationMethodArgument{AddressMode: nescpu.Immediate, OperandAddress: 0x100})
+
+			assert.Equal(t, tt.expectedA, cpu.registers.A, "unexpected register A")
+			assert.Equal(t, tt.expectedX, cpu.registers.X, "unexpected register X")
+			assert.Equal(t, tt.expectedCarry, cpu.registers.CarryFlag(), "unexpected carry")
+			assert.Equal(t, tt.expectedOverflow, cpu.registers.OverflowFlag(), "unexpected overflow")
+		})
+	}
+}
+
+func TestLAS(t *testing.T) {
+	cpu := CreateCPUWithGamePak()
+	cpu.registers.Sp = 0xF3
+	cpu.memory.Write(0x100, 0x3F)
+
+	extraCycle := cpu.las(nescpu.OperationMethodArgument{AddressMode: nescpu.AbsoluteYIndexed, OperandAddress: 0x100})
+
+	assert.Equal(t, byte(0x33), cpu.registers.A)
+	assert.Equal(t, byte(0x33), cpu.registers.X)
+	assert.Equal(t, byte(0x33), cpu.registers.Sp)
+	assert.True(t, extraCycle)
+}
+
+func TestUnstableHighByteStores(t *testing.T) {
+	cases := []struct {
+		name            string
+		method          func(cpu *Cpu6502) nescpu.OperationMethod
+		addressMode     nescpu.AddressMode
+		a               byte
+		x               byte
+		y               byte
+		operandAddress  types.Address
+		expectedAddress types.Address
+		expectedValue   byte
+		expectedSp      byte
+	}{
+		{"SHA", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.sha }, nescpu.AbsoluteYIndexed, 0xFF, 0xF7, 0x10, 0x0310, 0x0310, 0x04, 0xFF},
+		{"SHX", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.shx }, nescpu.AbsoluteYIndexed, 0, 0xFF, 0x10, 0x0310, 0x0310, 0x04, 0xFF},
+		{"SHY", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.shy }, nescpu.AbsoluteXIndexed, 0, 0x10, 0xFF, 0x0510, 0x0510, 0x06, 0xFF},
+		{"SHX crossing page corrupts high byte", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.shx }, nescpu.AbsoluteYIndexed, 0, 0x02, 0x10, 0x0608, 0x0208, 0x02, 0xFF},
+		{"TAS", func(cpu *Cpu6502) nescpu.OperationMethod { return cpu.tas }, nescpu.AbsoluteYIndexed, 0xF3, 0x3F, 0x10, 0x0210, 0x0210, 0x03, 0x33},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu := CreateCPUWithGamePak()
+			cpu.registers.A = tt.a
+			cpu.registers.X = tt.x
+			cpu.registers.Y = tt.y
+
+			tt.method(cpu)(nescpu.OperationMethodArgument{AddressMode: tt.addressMode, OperandAddress: tt.operandAddress})
+
+			assert.Equal(t, tt.expectedValue, cpu.memory.Read(tt.expectedAddress))
+			assert.Equal(t, tt.expectedSp, cpu.registers.Sp)
+		})
+	}
+}
+
+func TestJAMHaltsTheCPU(t *testing.T) {
+	cpu := CreateCPUWithGamePak()
+	cpu.ResetToAddress(0x0200)
+	cpu.memory.Write(0x0200, 0x02)
+
+	for i := 0; i < 10; i++ {
+		cpu.Tick()
+	}
+
+	assert.Equal(t, types.Address(0x0200), cpu.registers.Pc)
+}
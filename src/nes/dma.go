@@ -0,0 +1,130 @@
+package nes
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/apu"
+	"github.com/raulferras/nes-golang/src/nes/ppu"
+	"github.com/raulferras/nes-golang/src/nes/types"
+)
+
+// dma is the DMA unit of the 2A03. It halts the CPU to copy a page of CPU memory into PPU OAM (OAM DMA, started
+// by writing the page number into 0x4014), and to fetch the sample bytes played by the APU DMC (DMC DMA).
+//
+// The CPU can only be halted on a read cycle, and the read it was doing is repeated along the halt cycle.
+// CPU cycles alternate between get cycles, which read, and put cycles, which write. DMA transfers read on get cycles,
+// so an alignment cycle is spent when a transfer is ready on a put cycle. Halt, dummy and alignment cycles
+// read again the address the CPU was halted at.
+//
+//	OAM DMA: halt, [alignment], 256 get/put pairs writing into OAMDATA. 513 or 514 cycles.
+//	DMC DMA: halt, dummy, [alignment], get. 3 or 4 cycles.
+//
+// When both run along, OAM DMA cycles count as the halt and dummy cycles of the DMC DMA,
+// whose get then takes the place of an OAM DMA get.
+// Reference: https://www.nesdev.org/wiki/DMA
+type dma struct {
+	bus *CPUMemory
+	apu *apu.Apu2a03
+
+	halted      bool          // CPU is halted, CPU cycles belong to the DMA unit
+	haltCycle   bool          // Next cycle is the one halting the CPU
+	haltAddress types.Address // Address the CPU was reading when halted
+
+	oamActive bool
+	oamPage   byte
+	oamCycles uint16 // Get and put cycles done by current OAM DMA
+	oamValue  byte   // Byte read on last get cycle, written on next put cycle
+
+	dmcActive     bool
+	dmcNeedsHalt  bool
+	dmcNeedsDummy bool
+}
+
+// An OAM DMA reads and writes 256 bytes
+const oamDMACycles = 2 * ppu.OAMDATA_SIZE
+
+func newDMA(bus *CPUMemory, apu *apu.Apu2a03) *dma {
+	return &dma{bus: bus, apu: apu}
+}
+
+// requestOAM starts copying page into OAM, through OAMDATA. OAM is written from current OAMADDR.
+func (d *dma) requestOAM(page byte) {
+	d.oamActive = true
+	d.oamPage = page
+	d.oamCycles = 0
+}
+
+// pollDMC starts a DMC DMA when the DMC sample buffer is waiting for its next byte
+func (d *dma) pollDMC() {
+	if d.dmcActive {
+		return
+	}
+	if _, requested := d.apu.DMCSampleRequest(); requested {
+		d.dmcActive = true
+		d.dmcNeedsHalt = true
+		d.dmcNeedsDummy = true
+	}
+}
+
+// wantsToHalt tells if a transfer is waiting for the CPU to be halted
+func (d *dma) wantsToHalt() bool {
+	return !d.halted && (d.oamActive || d.dmcActive)
+}
+
+// halt takes the CPU over. address is the one the CPU was about to read.
+func (d *dma) halt(address types.Address) {
+	d.halted = true
+	d.haltCycle = true
+	d.haltAddress = address
+}
+
+// tick runs a CPU cycle while the CPU is halted
+func (d *dma) tick(getCycle bool) {
+	if d.haltCycle {
+		d.haltCycle = false
+		d.dmcNeedsHalt = false
+		d.bus.Read(d.haltAddress)
+		return
+	}
+
+	dmcReady := d.dmcActive && !d.dmcNeedsHalt && !d.dmcNeedsDummy
+	if d.dmcNeedsHalt {
+		d.dmcNeedsHalt = false
+	} else if d.dmcNeedsDummy {
+		d.dmcNeedsDummy = false
+	}
+
+	switch {
+	case getCycle && dmcReady:
+		d.fetchDMCSample()
+	case getCycle && d.oamActive:
+		d.oamValue = d.bus.Read(types.CreateAddress(byte(d.oamCycles/2), d.oamPage))
+		d.oamCycles++
+	case !getCycle && d.oamActive && d.oamCycles%2 == 1:
+		d.bus.Write(ppu.OAMDATA, d.oamValue)
+		d.oamCycles++
+		d.oamActive = d.oamCycles < oamDMACycles
+	default:
+		d.dummyRead()
+	}
+
+	d.halted = d.oamActive || d.dmcActive
+}
+
+// fetchDMCSample hands the DMC its next sample byte. Nothing is read if the DMC was disabled meanwhile.
+func (d *dma) fetchDMCSample() {
+	d.dmcActive = false
+	if address, requested := d.apu.DMCSampleRequest(); requested {
+		d.apu.LoadDMCSample(d.bus.Read(address))
+	} else {
+		d.dummyRead()
+	}
+}
+
+// dummyRead repeats the read the CPU was halted at.
+// Controllers only notice the first of consecutive reads, so it is the halt cycle which clocks their shift register
+// an extra time, making games lose a button when DMC DMA halts a controller read.
+func (d *dma) dummyRead() {
+	if d.haltAddress == CONTROLLER_1_ADDRESS || d.haltAddress == CONTROLLER_2_ADDRESS {
+		return
+	}
+	d.bus.Read(d.haltAddress)
+}
@@ -0,0 +1,118 @@
+package nes
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/apu"
+	"github.com/raulferras/nes-golang/src/nes/ppu"
+	"github.com/raulferras/nes-golang/src/nes/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// haltedCycles runs the console until the DMA unit releases the CPU, and tells how many CPU cycles it kept
+func haltedCycles(nes *Nes) int {
+	cycles := 0
+	for cycles == 0 || nes.dma.halted {
+		tickCPUCycles(nes, 1)
+		cycles++
+	}
+
+	return cycles
+}
+
+// startDMC makes the DMC request the single byte of a sample
+func startDMC(nes *Nes) {
+	nes.bus.Write(apu.DMC_ADDRESS, 0x00)
+	nes.bus.Write(apu.DMC_LENGTH, 0x00)
+	nes.bus.Write(apu.STATUS, 0x10)
+}
+
+func TestDMA_OAM_halts_the_CPU_513_or_514_cycles(t *testing.T) {
+	tests := []struct {
+		name           string
+		haltCycle      uint64
+		expectedCycles int
+	}{
+		{"halted on a put cycle", 1, 513},
+		{"halted on a get cycle needs alignment", 0, 514},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nes := aNopNes()
+			nes.bus.Write(ppu.OAMDMA, 0x02)
+			nes.cpuClockCounter = tt.haltCycle
+
+			assert.Equal(t, tt.expectedCycles, haltedCycles(nes))
+		})
+	}
+}
+
+func TestDMA_OAM_writes_from_current_OAMADDR(t *testing.T) {
+	nes := aNopNes()
+	// PPU ignores register writes until warmed up
+	for i := 0; i <= ppu.PPU_CYCLES_TO_WARMUP; i++ {
+		nes.ppu.Tick()
+	}
+	for i := 0; i < 0x100; i++ {
+		nes.bus.Write(types.Address(0x0300+i), byte(i))
+	}
+	nes.bus.Write(0x2000|ppu.OAMADDR, 0x10)
+
+	nes.bus.Write(ppu.OAMDMA, 0x03)
+	haltedCycles(nes)
+
+	nes.bus.Write(0x2000|ppu.OAMADDR, 0x11)
+	assert.Equal(t, byte(0x01), nes.bus.Read(0x2000|ppu.OAMDATA))
+	nes.bus.Write(0x2000|ppu.OAMADDR, 0x0F)
+	assert.Equal(t, byte(0xFF), nes.bus.Read(0x2000|ppu.OAMDATA), "transfer should wrap around OAM")
+}
+
+func TestDMA_DMC_halts_the_CPU_3_or_4_cycles(t *testing.T) {
+	tests := []struct {
+		name           string
+		haltCycle      uint64
+		expectedCycles int
+	}{
+		{"halted on a get cycle", 0, 3},
+		{"halted on a put cycle needs alignment", 1, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nes := aNopNes()
+			startDMC(nes)
+			nes.cpuClockCounter = tt.haltCycle
+
+			assert.Equal(t, tt.expectedCycles, haltedCycles(nes))
+			_, requested := nes.apu.DMCSampleRequest()
+			assert.False(t, requested, "sample byte should have been fetched")
+		})
+	}
+}
+
+func TestDMA_DMC_during_OAM_DMA_takes_2_more_cycles(t *testing.T) {
+	nes := aNopNes()
+	nes.bus.Write(ppu.OAMDMA, 0x02)
+	nes.cpuClockCounter = 1
+
+	tickCPUCycles(nes, 100)
+	startDMC(nes)
+
+	assert.Equal(t, 513+2-100, haltedCycles(nes))
+}
+
+func TestDMA_DMC_halting_a_controller_read_clocks_it_twice(t *testing.T) {
+	nes := aNopNes()
+	nes.bus.Write(0x0100, 0xAD) // LDA $4016
+	nes.bus.Write(0x0101, 0x16)
+	nes.bus.Write(0x0102, 0x40)
+	nes.UpdateController(1, ControllerState{A: true})
+	nes.bus.Write(CONTROLLER_1_ADDRESS, 1)
+
+	// DMC requests its sample right when LDA is about to read the controller
+	tickCPUCycles(nes, 3)
+	startDMC(nes)
+	tickCPUCycles(nes, 5)
+
+	assert.Equal(t, byte(0), nes.Cpu.registers.A, "A button should have been lost")
+}
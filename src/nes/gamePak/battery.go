@@ -0,0 +1,59 @@
+package gamePak
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SaveFilePath returns the file where battery backed PRG RAM of a rom is persisted:
+// the rom path with its extension replaced by .sav
+func SaveFilePath(romPath string) string {
+	return strings.TrimSuffix(romPath, filepath.Ext(romPath)) + ".sav"
+}
+
+// HasBattery tells if the GamePak PRG RAM keeps its contents when the console is powered off.
+func (gamePak *GamePak) HasBattery() bool {
+	return gamePak.header.HasPersistentMemory() && len(gamePak.prgRAM) > 0
+}
+
+// LoadBatteryRAM restores PRG RAM from the save file. A missing save file is not an error,
+// the game just has not saved yet. Roms loaded with CreateGamePakFromROMFile have it already loaded.
+func (gamePak *GamePak) LoadBatteryRAM() error {
+	if !gamePak.HasBattery() || gamePak.savePath == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(gamePak.savePath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	copy(gamePak.prgRAM, data)
+	gamePak.prgRAMDirty = false
+
+	return nil
+}
+
+// FlushBatteryRAM writes PRG RAM into the save file, if it changed since last flush.
+// Data is written to a temporary file first, so a crash never leaves a half written save.
+func (gamePak *GamePak) FlushBatteryRAM() error {
+	if !gamePak.HasBattery() || gamePak.savePath == "" || !gamePak.prgRAMDirty {
+		return nil
+	}
+
+	tmpPath := gamePak.savePath + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, gamePak.prgRAM, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, gamePak.savePath); err != nil {
+		return err
+	}
+
+	gamePak.prgRAMDirty = false
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package gamePak
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func createBatteryGamePakForTest(t *testing.T) GamePak {
+	dir, err := ioutil.TempDir("", "nes-battery")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	cartridge := CreateGamePak(CreateINes1Header(2, 1, 0x02, 0, 0, 0, 0), make([]byte, 0x8000), make([]byte, 0x2000))
+	cartridge.savePath = SaveFilePath(filepath.Join(dir, "game.nes"))
+
+	return cartridge
+}
+
+func TestSaveFilePath(t *testing.T) {
+	assert.Equal(t, "roms/zelda.sav", SaveFilePath("roms/zelda.nes"))
+	assert.Equal(t, "roms/zelda.sav", SaveFilePath("roms/zelda"))
+}
+
+func TestGamePak_flushes_and_loads_battery_ram(t *testing.T) {
+	cartridge := createBatteryGamePakForTest(t)
+	cartridge.WritePrgROM(0x6000, 0xCA)
+	cartridge.WritePrgROM(0x7FFF, 0xFE)
+
+	assert.NoError(t, cartridge.FlushBatteryRAM())
+
+	saved, err := ioutil.ReadFile(cartridge.savePath)
+	assert.NoError(t, err)
+	assert.Equal(t, 0x2000, len(saved))
+
+	reloaded := CreateGamePak(cartridge.header, make([]byte, 0x8000), make([]byte, 0x2000))
+	reloaded.savePath = cartridge.savePath
+	assert.NoError(t, reloaded.LoadBatteryRAM())
+	assert.Equal(t, byte(0xCA), reloaded.ReadPrgROM(0x6000))
+	assert.Equal(t, byte(0xFE), reloaded.ReadPrgROM(0x7FFF))
+}
+
+func TestGamePak_missing_save_file_is_not_an_error(t *testing.T) {
+	cartridge := createBatteryGamePakForTest(t)
+
+	assert.NoError(t, cartridge.LoadBatteryRAM())
+}
+
+func TestGamePak_only_flushes_when_ram_changed(t *testing.T) {
+	cartridge := createBatteryGamePakForTest(t)
+
+	assert.NoError(t, cartridge.FlushBatteryRAM())
+
+	_, err := os.Stat(cartridge.savePath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGamePak_without_battery_does_not_persist(t *testing.T) {
+	cartridge := createBatteryGamePakForTest(t)
+	cartridge.header = CreateINes1Header(2, 1, 0, 0, 0, 0, 0)
+	cartridge.WritePrgROM(0x6000, 0xCA)
+
+	assert.NoError(t, cartridge.FlushBatteryRAM())
+
+	_, err := os.Stat(cartridge.savePath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCreateGamePakFromROMFile_loads_save_file_before_trainer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nes-battery")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	romPath := filepath.Join(dir, "game.nes")
+	assert.NoError(t, ioutil.WriteFile(romPath, romForTest(0x02, 0, 1, 1, true), 0644))
+	assert.NoError(t, ioutil.WriteFile(SaveFilePath(romPath), bytes.Repeat([]byte{0x5A}, 0x2000), 0644))
+
+	cartridge, err := CreateGamePakFromROMFile(romPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0x5A), cartridge.ReadPrgROM(0x6000))
+	assert.Equal(t, byte(0xEE), cartridge.ReadPrgROM(0x7000), "trainer should not be overwritten by save file")
+	assert.Equal(t, byte(0xEE), cartridge.ReadPrgROM(0x71FF))
+	assert.Equal(t, byte(0x5A), cartridge.ReadPrgROM(0x7200))
+}
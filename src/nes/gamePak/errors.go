@@ -0,0 +1,51 @@
+package gamePak
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrBadMagic is returned when a rom does not start with "NES\x1A"
+var ErrBadMagic = errors.New("not an iNES rom, bad magic number")
+
+// TruncatedError is returned when a rom is shorter than its header says.
+// Section is one of "header", "trainer", "PRG ROM" or "CHR ROM".
+type TruncatedError struct {
+	Section  string
+	Expected int
+	Actual   int
+}
+
+func (err TruncatedError) Error() string {
+	return fmt.Sprintf("rom is truncated: %s should be %d bytes, found %d", err.Section, err.Expected, err.Actual)
+}
+
+// UnsupportedMapperError is returned when the rom needs a mapper this emulator does not implement
+type UnsupportedMapperError struct {
+	Mapper    uint16
+	SubMapper byte
+}
+
+func (err UnsupportedMapperError) Error() string {
+	return fmt.Sprintf("mapper %d (submapper %d) not supported", err.Mapper, err.SubMapper)
+}
+
+// TrainerError is returned when a rom has a trainer but the cartridge has no PRG RAM at 0x7000 to hold it
+type TrainerError struct {
+	PRGRAMSize int
+}
+
+func (err TrainerError) Error() string {
+	return fmt.Sprintf("rom has a 512 byte trainer, but only %d bytes of PRG RAM to load it into", err.PRGRAMSize)
+}
+
+// PRGROMSizeError is returned when PRG ROM is smaller than the banks its mapper switches
+type PRGROMSizeError struct {
+	Mapper   uint16
+	Size     int
+	BankSize int
+}
+
+func (err PRGROMSizeError) Error() string {
+	return fmt.Sprintf("PRG ROM of %d bytes is smaller than the %d byte banks of mapper %d", err.Size, err.BankSize, err.Mapper)
+}
@@ -1,18 +1,59 @@
 package gamePak
 
 import (
+	"bytes"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"os"
 )
 
 func CreateGamePak(header Header, prgROM []byte, chrROM []byte) GamePak {
-	mapper := CreateMapper(header, prgROM, chrROM)
-	return GamePak{
-		header,
-		mapper,
-		prgROM,
-		chrROM}
+	gamePak, err := newGamePak(header, prgROM, chrROM)
+	if err != nil {
+		panic(err)
+	}
+
+	return gamePak
+}
+
+func newGamePak(header Header, prgROM []byte, chrROM []byte) (GamePak, error) {
+	mapper, err := newMapper(header, prgROM, chrROM)
+	if err != nil {
+		return GamePak{}, err
+	}
+
+	gamePak := GamePak{
+		header: header,
+		mapper: mapper,
+		prgROM: prgROM,
+		chrROM: chrROM,
+		prgRAM: make([]byte, header.PRGRAMSize()+header.PRGNVRAMSize()),
+		hash:   romHash(prgROM, chrROM),
+	}
+
+	if observer, ok := mapper.(PPUAddressObserver); ok {
+		gamePak.ppuAddressObserver = observer
+	}
+	if observer, ok := mapper.(CPUCycleObserver); ok {
+		gamePak.cpuCycleObserver = observer
+	}
+	if irqSource, ok := mapper.(IRQSource); ok {
+		gamePak.irqSource = irqSource
+	}
+	if controller, ok := mapper.(PRGRAMController); ok {
+		gamePak.prgRAMController = controller
+	}
+	if nameTableMapper, ok := mapper.(NameTableMapper); ok {
+		gamePak.nameTableMapper = nameTableMapper
+	}
+	if statefulMapper, ok := mapper.(StatefulMapper); ok {
+		gamePak.statefulMapper = statefulMapper
+	}
+	if header.Mirroring() == FourScreenMirroring {
+		gamePak.fourScreenVRAM = make([]byte, FourScreenVRAMSize)
+	}
 
+	return gamePak, nil
 }
 
 func NewGamePakWithINes(flag6 byte, flag7 byte, flag8 byte, flag9 byte, flag10 byte, prgROM []byte, chrROM []byte) GamePak {
@@ -21,49 +62,100 @@ func NewGamePakWithINes(flag6 byte, flag7 byte, flag8 byte, flag9 byte, flag10 b
 		header: CreateINes1Header(byte(len(prgROM)/16), byte(len(chrROM)/8), flag6, flag7, flag8, flag9, flag10),
 		prgROM: prgROM,
 		chrROM: chrROM,
+		hash:   romHash(prgROM, chrROM),
 	}
 }
 
-func CreateGamePakFromROMFile(romFilePath string) GamePak {
-	data, err := ioutil.ReadFile(romFilePath)
+// CreateGamePakFromROMFile loads an iNES or NES 2.0 rom file.
+// Battery backed PRG RAM of the GamePak is persisted next to the rom, see SaveFilePath.
+func CreateGamePakFromROMFile(romFilePath string) (GamePak, error) {
+	file, err := os.Open(romFilePath)
 	if err != nil {
-		fmt.Println("File reading error", err)
-	}
-
-	// ReadPrgROM INesHeader
-	inesHeader := CreateINes1Header(
-		data[4],
-		data[5],
-		data[6],
-		data[7],
-		data[8],
-		data[9],
-		data[10],
-	)
+		return GamePak{}, err
+	}
+	defer file.Close()
+
+	gamePak, err := loadGamePak(file, SaveFilePath(romFilePath))
+	if err != nil {
+		return GamePak{}, fmt.Errorf("%s: %w", romFilePath, err)
+	}
 
-	prgLength := int(inesHeader.ProgramSize())*0x4000 + 16
-	prgROM := data[16:prgLength]
+	return gamePak, nil
+}
+
+// LoadGamePak reads an iNES or NES 2.0 rom
+//
+//	Header:  16 bytes
+//	Trainer: 512 bytes, if present. Loaded into PRG RAM at 0x7000
+//	PRG ROM: sized from header
+//	CHR ROM: sized from header. Boards without it get CHR RAM from the mapper
+func LoadGamePak(reader io.Reader) (GamePak, error) {
+	return loadGamePak(reader, "")
+}
+
+// loadGamePak reads a rom whose battery backed RAM is persisted into savePath, none when empty.
+// The save file is loaded before the trainer, so it never overwrites the trainer at 0x7000.
+func loadGamePak(reader io.Reader, savePath string) (GamePak, error) {
+	data, err := readSection(reader, "header", 16)
+	if err != nil {
+		return GamePak{}, err
+	}
+	if !bytes.Equal(data[0:4], []byte("NES\x1A")) {
+		return GamePak{}, ErrBadMagic
+	}
+	inesHeader := NewINesHeader(data)
+
+	var trainer []byte
+	if inesHeader.HasTrainer() {
+		if trainer, err = readSection(reader, "trainer", 512); err != nil {
+			return GamePak{}, err
+		}
+	}
+
+	prgROM, err := readSection(reader, "PRG ROM", inesHeader.ProgramROMLength())
+	if err != nil {
+		return GamePak{}, err
+	}
 
 	var chrROM []byte
-	chrLength := int(inesHeader.CHRSize())
-	if chrLength == 0 {
-		chrLength = 8 * 0x2000
-		chrROM = make([]byte, chrLength)
-	} else {
-		chrLength *= 0x2000
-		chrROM = data[prgLength : chrLength+prgLength]
-	}
-	return CreateGamePak(
-		inesHeader,
-		prgROM,
-		chrROM,
-	)
+	if inesHeader.CHRROMLength() > 0 {
+		if chrROM, err = readSection(reader, "CHR ROM", inesHeader.CHRROMLength()); err != nil {
+			return GamePak{}, err
+		}
+	}
+
+	gamePak, err := newGamePak(inesHeader, prgROM, chrROM)
+	if err != nil {
+		return GamePak{}, err
+	}
+
+	gamePak.savePath = savePath
+	if err := gamePak.LoadBatteryRAM(); err != nil {
+		return GamePak{}, err
+	}
+	if trainer != nil {
+		if err := gamePak.loadTrainer(trainer); err != nil {
+			return GamePak{}, err
+		}
+	}
+
+	return gamePak, nil
+}
+
+func readSection(reader io.Reader, section string, length int) ([]byte, error) {
+	data := make([]byte, length)
+	read, err := io.ReadFull(reader, data)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, TruncatedError{Section: section, Expected: length, Actual: read}
+	}
+
+	return data, err
 }
 
 func NewDummyGamePak(chrROM []byte) *GamePak {
 	pak := CreateGamePak(
 		CreateINes1Header(1, 1, 0, 0, 0, 0, 0),
-		make([]byte, 100),
+		make([]byte, 0x4000),
 		chrROM,
 	)
 
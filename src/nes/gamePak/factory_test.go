@@ -0,0 +1,77 @@
+package gamePak
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func romForTest(flags6 byte, flags7 byte, prgBanks byte, chrBanks byte, trainer bool) []byte {
+	rom := []byte{'N', 'E', 'S', 0x1A, prgBanks, chrBanks, flags6, flags7, 0, 0, 0, 0, 0, 0, 0, 0}
+	if trainer {
+		rom[6] |= 0x04
+		rom = append(rom, bytes.Repeat([]byte{0xEE}, 512)...)
+	}
+	rom = append(rom, bankedROM(int(prgBanks)*0x4000, 0x4000)...)
+	rom = append(rom, bankedROM(int(chrBanks)*0x2000, 0x2000)...)
+
+	return rom
+}
+
+func TestLoadGamePak(t *testing.T) {
+	rom := romForTest(0x01, 0, 2, 1, false)
+
+	cartridge, err := LoadGamePak(bytes.NewReader(rom))
+
+	assert.NoError(t, err)
+	assert.Equal(t, VerticalMirroring, cartridge.Mirroring())
+	assert.Equal(t, byte(0), cartridge.ReadPrgROM(0x8000))
+	assert.Equal(t, byte(1), cartridge.ReadPrgROM(0xC000))
+}
+
+func TestLoadGamePak_maps_trainer_at_0x7000(t *testing.T) {
+	rom := romForTest(0, 0, 1, 1, true)
+
+	cartridge, err := LoadGamePak(bytes.NewReader(rom))
+
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0xEE), cartridge.ReadPrgROM(0x7000))
+	assert.Equal(t, byte(0xEE), cartridge.ReadPrgROM(0x71FF))
+	assert.Equal(t, byte(0), cartridge.ReadPrgROM(0x7200))
+	assert.Equal(t, byte(0), cartridge.ReadPrgROM(0x8000), "trainer should not be read as PRG ROM")
+}
+
+func TestLoadGamePak_errors(t *testing.T) {
+	nes2WithoutPRGRAM := romForTest(0, 0x08, 1, 1, true)
+
+	tests := []struct {
+		name     string
+		rom      []byte
+		expected error
+	}{
+		{"bad magic", append([]byte("NES\x00"), romForTest(0, 0, 1, 1, false)[4:]...), ErrBadMagic},
+		{"truncated header", []byte("NES\x1A"), TruncatedError{Section: "header", Expected: 16, Actual: 4}},
+		{"truncated trainer", romForTest(0, 0, 1, 1, true)[:100], TruncatedError{Section: "trainer", Expected: 512, Actual: 84}},
+		{"truncated PRG ROM", romForTest(0, 0, 2, 1, false)[:0x4010], TruncatedError{Section: "PRG ROM", Expected: 0x8000, Actual: 0x4000}},
+		{"truncated CHR ROM", romForTest(0, 0, 1, 1, false)[:0x4010], TruncatedError{Section: "CHR ROM", Expected: 0x2000, Actual: 0}},
+		{"unsupported mapper", romForTest(0xF0, 0xF0, 1, 1, false), UnsupportedMapperError{Mapper: 255}},
+		{"empty PRG ROM", romForTest(0, 0, 0, 1, false), PRGROMSizeError{Mapper: 0, Size: 0, BankSize: 0x4000}},
+		{"PRG ROM smaller than a bank", romForTest(0x70, 0, 1, 1, false), PRGROMSizeError{Mapper: 7, Size: 0x4000, BankSize: 0x8000}},
+		{"trainer without PRG RAM", nes2WithoutPRGRAM, TrainerError{PRGRAMSize: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadGamePak(bytes.NewReader(tt.rom))
+
+			assert.True(t, errors.Is(err, tt.expected), "expected %v, got %v", tt.expected, err)
+		})
+	}
+}
+
+func TestCreateGamePakFromROMFile_returns_error_for_missing_file(t *testing.T) {
+	_, err := CreateGamePakFromROMFile("missing.nes")
+
+	assert.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package gamePak
 
 import (
+	"crypto/sha1"
+	"github.com/raulferras/nes-golang/src/nes/savestate"
 	"github.com/raulferras/nes-golang/src/nes/types"
 )
 
@@ -10,6 +12,9 @@ const GAMEPAK_HIGH_RANGE = 0xFFFF
 
 const GAMEPAK_ROM_LOWER_BANK_START = 0x8000
 
+const PRG_RAM_LOW_RANGE = 0x6000
+const PRG_RAM_HIGH_RANGE = 0x7FFF
+
 type GamePakInterface interface {
 	Header() Header
 	ReadPrgROM(address types.Address) byte
@@ -23,17 +28,104 @@ type GamePak struct {
 	mapper Mapper
 	prgROM []byte
 	chrROM []byte
+	prgRAM []byte
+	hash   [sha1.Size]byte
+
+	// Battery backed PRG RAM is persisted into savePath
+	savePath    string
+	prgRAMDirty bool
+
+	// Four screen boards carry 4KB of VRAM, replacing console VRAM for all nametables
+	fourScreenVRAM []byte
+
+	ppuAddressObserver PPUAddressObserver
+	cpuCycleObserver   CPUCycleObserver
+	irqSource          IRQSource
+	prgRAMController   PRGRAMController
+	nameTableMapper    NameTableMapper
+	statefulMapper     StatefulMapper
 }
 
 func (gamePak *GamePak) Header() Header {
 	return gamePak.header
 }
 
+// ROMHash identifies the rom by the SHA-1 of its PRG and CHR ROM, header and trainer apart
+func (gamePak *GamePak) ROMHash() [sha1.Size]byte {
+	return gamePak.hash
+}
+
+func romHash(prgROM []byte, chrROM []byte) [sha1.Size]byte {
+	hash := sha1.New()
+	hash.Write(prgROM)
+	hash.Write(chrROM)
+
+	var sum [sha1.Size]byte
+	copy(sum[:], hash.Sum(nil))
+
+	return sum
+}
+
+// Mirroring returns the nametable mirroring currently selected by the mapper.
+// Some mappers are able to change it at runtime. Four screen boards ignore it.
+func (gamePak *GamePak) Mirroring() byte {
+	if gamePak.fourScreenVRAM != nil {
+		return FourScreenMirroring
+	}
+
+	return gamePak.mapper.Mirroring()
+}
+
+// CIRAMPage returns the console VRAM page, 0 or 1, backing nametable 0 -> 3 when mirroring is MapperMirroring
+func (gamePak *GamePak) CIRAMPage(nameTable byte) byte {
+	if gamePak.nameTableMapper == nil {
+		return 0
+	}
+
+	return gamePak.nameTableMapper.CIRAMPage(nameTable) & 0x01
+}
+
+// ReadNameTable reads a nametable address from cartridge memory.
+// It returns false when the address is backed by console VRAM (CIRAM) instead.
+func (gamePak *GamePak) ReadNameTable(address types.Address) (byte, bool) {
+	if gamePak.fourScreenVRAM != nil {
+		return gamePak.fourScreenVRAM[address%FourScreenVRAMSize], true
+	}
+	if gamePak.nameTableMapper != nil {
+		return gamePak.nameTableMapper.ReadNameTable(address)
+	}
+
+	return 0, false
+}
+
+// WriteNameTable writes a nametable address into cartridge memory.
+// It returns false when the address is backed by console VRAM (CIRAM) instead.
+func (gamePak *GamePak) WriteNameTable(address types.Address, value byte) bool {
+	if gamePak.fourScreenVRAM != nil {
+		gamePak.fourScreenVRAM[address%FourScreenVRAMSize] = value
+		return true
+	}
+	if gamePak.nameTableMapper != nil {
+		return gamePak.nameTableMapper.WriteNameTable(address, value)
+	}
+
+	return false
+}
+
 func (gamePak *GamePak) ReadPrgROM(address types.Address) byte {
+	if address >= PRG_RAM_LOW_RANGE && address <= PRG_RAM_HIGH_RANGE {
+		return gamePak.readPRGRAM(address)
+	}
+
 	return gamePak.mapper.ReadPrgROM(address)
 }
 
 func (gamePak *GamePak) WritePrgROM(address types.Address, value byte) {
+	if address >= PRG_RAM_LOW_RANGE && address <= PRG_RAM_HIGH_RANGE {
+		gamePak.writePRGRAM(address, value)
+	}
+
+	// Some mappers have registers in the PRG RAM range, so the mapper also sees these writes
 	gamePak.mapper.WritePrgROM(address, value)
 }
 
@@ -44,3 +136,79 @@ func (gamePak *GamePak) ReadCHRROM(address types.Address) byte {
 func (gamePak *GamePak) WriteCHRRAM(address types.Address, value byte) {
 	gamePak.mapper.WriteChrROM(address, value)
 }
+
+// OnPPUAddress lets the mapper know about an address driven by the PPU into its address bus.
+func (gamePak *GamePak) OnPPUAddress(address types.Address, ppuCycle uint64) {
+	if gamePak.ppuAddressObserver != nil {
+		gamePak.ppuAddressObserver.OnPPUAddress(address, ppuCycle)
+	}
+}
+
+// OnCPUCycle lets the mapper know about a new CPU cycle, before the CPU accesses the bus.
+func (gamePak *GamePak) OnCPUCycle(cpuCycle uint64) {
+	if gamePak.cpuCycleObserver != nil {
+		gamePak.cpuCycleObserver.OnCPUCycle(cpuCycle)
+	}
+}
+
+// IRQ tells if the mapper is asserting the CPU IRQ line.
+func (gamePak *GamePak) IRQ() bool {
+	if gamePak.irqSource == nil {
+		return false
+	}
+
+	return gamePak.irqSource.IRQ()
+}
+
+// PRG RAM is mirrored along 0x6000 -> 0x7FFF when smaller than 8KB.
+// Reads return 0 when there is no PRG RAM or the mapper disabled it.
+func (gamePak *GamePak) readPRGRAM(address types.Address) byte {
+	if len(gamePak.prgRAM) == 0 {
+		return 0
+	}
+	if gamePak.prgRAMController != nil && !gamePak.prgRAMController.PRGRAMEnabled() {
+		return 0
+	}
+
+	return gamePak.prgRAM[int(address-PRG_RAM_LOW_RANGE)%len(gamePak.prgRAM)]
+}
+
+func (gamePak *GamePak) writePRGRAM(address types.Address, value byte) {
+	if len(gamePak.prgRAM) == 0 {
+		return
+	}
+	if gamePak.prgRAMController != nil && !gamePak.prgRAMController.PRGRAMWritable() {
+		return
+	}
+
+	offset := int(address-PRG_RAM_LOW_RANGE) % len(gamePak.prgRAM)
+	if gamePak.prgRAM[offset] != value {
+		gamePak.prgRAM[offset] = value
+		gamePak.prgRAMDirty = true
+	}
+}
+
+// loadTrainer copies a rom trainer into PRG RAM at 0x7000 -> 0x71FF
+func (gamePak *GamePak) loadTrainer(trainer []byte) error {
+	if len(gamePak.prgRAM) < len(trainer) {
+		return TrainerError{PRGRAMSize: len(gamePak.prgRAM)}
+	}
+
+	for i, value := range trainer {
+		gamePak.prgRAM[(0x7000-PRG_RAM_LOW_RANGE+i)%len(gamePak.prgRAM)] = value
+	}
+
+	return nil
+}
+
+// SyncState saves or loads cartridge memories and mapper state
+func (gamePak *GamePak) SyncState(state *savestate.Stream) {
+	state.SyncMemory(gamePak.prgRAM)
+	state.SyncMemory(gamePak.fourScreenVRAM)
+	if gamePak.statefulMapper != nil {
+		gamePak.statefulMapper.SyncState(state)
+	}
+	if state.Loading() {
+		gamePak.prgRAMDirty = true
+	}
+}
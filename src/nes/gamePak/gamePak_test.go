@@ -1,5 +1,12 @@
 package gamePak
 
+import (
+	"crypto/sha1"
+	"github.com/raulferras/nes-golang/src/nes/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
 /*
 func Test_read_gamePak_with_trainer(t *testing.T) {
 	gamePak := aSampleGamePak(true)
@@ -7,3 +14,104 @@ func Test_read_gamePak_with_trainer(t *testing.T) {
 	value := gamePak.read(GAMEPAK_ROM_LOWER_BANK_START)
 	assert.Equal(t, byte(0x02), value)
 }*/
+
+func TestGamePak_prg_ram_is_readable_and_writable(t *testing.T) {
+	cartridge := CreateGamePak(CreateINes1Header(1, 1, 0, 0, 0, 0, 0), make([]byte, 0x4000), make([]byte, 0x2000))
+
+	cartridge.WritePrgROM(0x6000, 0x12)
+	cartridge.WritePrgROM(0x7FFF, 0x34)
+
+	assert.Equal(t, byte(0x12), cartridge.ReadPrgROM(0x6000))
+	assert.Equal(t, byte(0x34), cartridge.ReadPrgROM(0x7FFF))
+}
+
+func TestGamePak_small_prg_ram_is_mirrored(t *testing.T) {
+	header := nes2Header(map[int]byte{10: 0x05}) // 2KB
+	cartridge := CreateGamePak(header, make([]byte, 0x8000), make([]byte, 0x2000))
+
+	cartridge.WritePrgROM(0x6001, 0x56)
+
+	assert.Equal(t, byte(0x56), cartridge.ReadPrgROM(0x6801))
+	assert.Equal(t, byte(0x56), cartridge.ReadPrgROM(0x7801))
+}
+
+func TestGamePak_without_prg_ram_reads_zero(t *testing.T) {
+	header := nes2Header(map[int]byte{})
+	cartridge := CreateGamePak(header, make([]byte, 0x8000), make([]byte, 0x2000))
+
+	cartridge.WritePrgROM(0x6000, 0x56)
+
+	assert.Equal(t, byte(0), cartridge.ReadPrgROM(0x6000))
+}
+
+func TestGamePak_four_screen_boards_carry_nametable_vram(t *testing.T) {
+	cartridge := CreateGamePak(CreateINes1Header(1, 1, 0b1000, 0, 0, 0, 0), make([]byte, 0x4000), make([]byte, 0x2000))
+
+	assert.True(t, cartridge.WriteNameTable(0x2C00, 0x12))
+
+	value, ok := cartridge.ReadNameTable(0x2C00)
+	assert.True(t, ok)
+	assert.Equal(t, byte(0x12), value)
+	assert.Equal(t, FourScreenMirroring, cartridge.Mirroring())
+}
+
+func TestGamePak_nametables_are_in_console_vram_by_default(t *testing.T) {
+	cartridge := CreateGamePak(CreateINes1Header(1, 1, 0, 0, 0, 0, 0), make([]byte, 0x4000), make([]byte, 0x2000))
+
+	_, ok := cartridge.ReadNameTable(0x2000)
+	assert.False(t, ok)
+	assert.False(t, cartridge.WriteNameTable(0x2000, 0x12))
+}
+
+// nameTableMapperMock keeps nametable 3 in cartridge memory, and maps nametables 0-2 into console VRAM page 1
+type nameTableMapperMock struct {
+	Mapper000
+	ram [0x400]byte
+}
+
+func (mapper *nameTableMapperMock) Mirroring() byte {
+	return MapperMirroring
+}
+
+func (mapper *nameTableMapperMock) CIRAMPage(nameTable byte) byte {
+	return 1
+}
+
+func (mapper *nameTableMapperMock) ReadNameTable(address types.Address) (byte, bool) {
+	if (address>>10)&0x03 != 3 {
+		return 0, false
+	}
+	return mapper.ram[address&0x3FF], true
+}
+
+func (mapper *nameTableMapperMock) WriteNameTable(address types.Address, value byte) bool {
+	if (address>>10)&0x03 != 3 {
+		return false
+	}
+	mapper.ram[address&0x3FF] = value
+	return true
+}
+
+func TestGamePak_mapper_can_replace_console_vram(t *testing.T) {
+	mapper := &nameTableMapperMock{}
+	cartridge := GamePak{mapper: mapper, nameTableMapper: mapper}
+
+	assert.False(t, cartridge.WriteNameTable(0x2000, 0x12))
+	assert.True(t, cartridge.WriteNameTable(0x2C05, 0x34))
+
+	value, ok := cartridge.ReadNameTable(0x2C05)
+	assert.True(t, ok)
+	assert.Equal(t, byte(0x34), value)
+	assert.Equal(t, byte(0x34), mapper.ram[0x05])
+	assert.Equal(t, MapperMirroring, cartridge.Mirroring())
+	assert.Equal(t, byte(1), cartridge.CIRAMPage(0))
+}
+
+func TestGamePak_rom_hash_covers_prg_and_chr_rom(t *testing.T) {
+	header := CreateINes1Header(1, 1, 0, 0, 0, 0, 0)
+	cartridge := CreateGamePak(header, bankedROM(0x4000, 0x4000), make([]byte, 0x2000))
+	other := CreateGamePak(header, bankedROM(0x4000, 0x4000), bankedROM(0x2000, 0x1000))
+
+	assert.Equal(t, sha1.Sum(append(bankedROM(0x4000, 0x4000), make([]byte, 0x2000)...)), cartridge.ROMHash())
+	assert.NotEqual(t, cartridge.ROMHash(), other.ROMHash())
+}
@@ -3,17 +3,47 @@ package gamePak
 type Header interface {
 	ProgramSize() byte
 	CHRSize() byte
+	ProgramROMLength() int
+	CHRROMLength() int
 	Mirroring() byte
 	HasPersistentMemory() bool
 	HasTrainer() bool
 	IgnoreMirroringControl() bool
 
-	MapperNumber() byte
-	PRGRAM() byte
+	IsNES2() bool
+	MapperNumber() uint16
+	SubMapper() byte
+	PRGRAMSize() int
+	PRGNVRAMSize() int
+	CHRRAMSize() int
+	CHRNVRAMSize() int
 	TvSystem() byte
+	ConsoleType() byte
+	DefaultExpansionDevice() byte
 }
 
 const HorizontalMirroring = byte(0b00)
 const VerticalMirroring = byte(0b01)
 const OneScreenMirroring = byte(0b10)
 const FourScreenMirroring = byte(0b11)
+
+// OneScreenUpperMirroring is only selectable by mappers. OneScreenMirroring uses the lower nametable.
+const OneScreenUpperMirroring = byte(0b100)
+
+// MapperMirroring is only selectable by mappers choosing the console VRAM page of each nametable, see NameTableMapper.
+const MapperMirroring = byte(0b101)
+
+// FourScreenVRAMSize is the VRAM four screen boards carry, one KB per nametable
+const FourScreenVRAMSize = 0x1000
+
+// CPU/PPU timings
+const TvSystemNTSC = byte(0)
+const TvSystemPAL = byte(1)
+const TvSystemMultiRegion = byte(2)
+const TvSystemDendy = byte(3)
+
+// Console types
+const ConsoleNES = byte(0)
+const ConsoleVsSystem = byte(1)
+const ConsolePlaychoice10 = byte(2)
+const ConsoleFamicloneDecimalMode = byte(3)
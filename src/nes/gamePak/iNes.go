@@ -8,6 +8,11 @@ type INesHeader struct {
 	flags8     byte
 	flags9     byte
 	flags10    byte
+	flags11    byte
+	flags12    byte
+	flags13    byte
+	flags14    byte
+	flags15    byte
 }
 
 /*
@@ -20,6 +25,23 @@ Flags6
 |||||+--- 1: 512-byte trainer at $7000-$71FF (stored before PRG prgROM)
 ||||+---- 1: Ignore mirroring control or above mirroring bit; instead provide four-screen VRAM
 ++++----- Lower nybble of mapper number
+
+Flags7
+76543210
+||||||||
+||||||++- Console type (0: NES/Famicom, 1: Vs. System, 2: Playchoice 10, 3: Extended, see Flags13)
+||||++--- NES 2.0 identifier, 0b10 when the rest of the header follows the NES 2.0 format
+++++----- Middle nybble of mapper number
+
+NES 2.0 only
+Flags8:  Upper nybble of mapper number (bits 0-3), submapper (bits 4-7)
+Flags9:  Upper bits of PRG ROM size (bits 0-3) and CHR ROM size (bits 4-7)
+Flags10: PRG RAM shift count (bits 0-3), PRG NVRAM shift count (bits 4-7)
+Flags11: CHR RAM shift count (bits 0-3), CHR NVRAM shift count (bits 4-7)
+Flags12: CPU/PPU timing (bits 0-1)
+Flags13: Vs. System PPU and hardware types, or extended console type (bits 0-3)
+Flags14: Number of miscellaneous ROMs
+Flags15: Default expansion device (bits 0-5)
 */
 func (ines INesHeader) ProgramSize() byte {
 	return ines.prgROMSize
@@ -29,44 +51,214 @@ func (ines INesHeader) CHRSize() byte {
 	return ines.chrROMSize
 }
 
+// ProgramROMLength returns the PRG ROM size in bytes
+func (ines INesHeader) ProgramROMLength() int {
+	if !ines.IsNES2() {
+		return int(ines.prgROMSize) * 0x4000
+	}
+
+	return nes2ROMLength(ines.prgROMSize, ines.flags9&0x0F, 0x4000)
+}
+
+// CHRROMLength returns the CHR ROM size in bytes
+func (ines INesHeader) CHRROMLength() int {
+	if !ines.IsNES2() {
+		return int(ines.chrROMSize) * 0x2000
+	}
+
+	return nes2ROMLength(ines.chrROMSize, ines.flags9>>4, 0x2000)
+}
+
 func (ines INesHeader) Mirroring() byte {
-	mirroring := byte(ines.flags6 & 0b11)
+	// Bit 1 is the battery flag, only bits 0 and 3 describe the nametable arrangement
+	if ines.flags6&0x08 == 0x08 {
+		return FourScreenMirroring
+	}
 
-	return mirroring
+	return ines.flags6 & 0x01
 }
 
 func (ines INesHeader) HasTrainer() bool {
-	if ines.flags6&0x06 == 0x06 {
-		return true
+	return ines.flags6&0x04 == 0x04
+}
+
+func (ines INesHeader) HasPersistentMemory() bool {
+	return ines.flags6&0x02 == 0x02
+}
+
+func (ines INesHeader) IgnoreMirroringControl() bool {
+	return ines.flags6&0x08 == 0x08
+}
+
+func (ines INesHeader) IsNES2() bool {
+	return ines.flags7&0x0C == 0x08
+}
+
+func (ines INesHeader) MapperNumber() uint16 {
+	mapper := uint16(ines.flags6 >> 4)
+	if ines.isArchaic() {
+		// Old dumping tools wrote garbage like "DiskDude!" from byte 7 onwards
+		return mapper
 	}
 
-	return false
+	mapper |= uint16(ines.flags7 & 0xF0)
+	if ines.IsNES2() {
+		mapper |= uint16(ines.flags8&0x0F) << 8
+	}
+
+	return mapper
 }
 
-func (ines INesHeader) HasPersistentMemory() bool {
-	if ines.flags6&0x04 == 0x04 {
-		return true
+func (ines INesHeader) SubMapper() byte {
+	if !ines.IsNES2() {
+		return 0
 	}
 
-	return false
+	return ines.flags8 >> 4
 }
 
-func (ines INesHeader) IgnoreMirroringControl() bool {
-	panic("implement me")
+// PRGRAMSize returns the volatile PRG RAM size in bytes.
+// iNES 1.0 headers store it in 8KB units, where 0 means 8KB for compatibility.
+func (ines INesHeader) PRGRAMSize() int {
+	if ines.IsNES2() {
+		return nes2RAMLength(ines.flags10 & 0x0F)
+	}
+	if ines.HasPersistentMemory() {
+		return 0
+	}
+
+	return ines.ines1PRGRAMSize()
 }
 
-func (ines INesHeader) PRGRAM() byte {
-	panic("implement me")
+// PRGNVRAMSize returns the battery backed PRG RAM size in bytes
+func (ines INesHeader) PRGNVRAMSize() int {
+	if ines.IsNES2() {
+		return nes2RAMLength(ines.flags10 >> 4)
+	}
+	if !ines.HasPersistentMemory() {
+		return 0
+	}
+
+	return ines.ines1PRGRAMSize()
+}
+
+// CHRRAMSize returns the volatile CHR RAM size in bytes.
+// iNES 1.0 headers assume 8KB of CHR RAM when there is no CHR ROM.
+func (ines INesHeader) CHRRAMSize() int {
+	if ines.IsNES2() {
+		return nes2RAMLength(ines.flags11 & 0x0F)
+	}
+	if ines.chrROMSize == 0 {
+		return 0x2000
+	}
+
+	return 0
 }
 
-func (ines INesHeader) MapperNumber() byte {
-	return (ines.flags6 >> 4) | (ines.flags7 & 0xF0)
+// CHRNVRAMSize returns the battery backed CHR RAM size in bytes
+func (ines INesHeader) CHRNVRAMSize() int {
+	if ines.IsNES2() {
+		return nes2RAMLength(ines.flags11 >> 4)
+	}
+
+	return 0
 }
 
+// TvSystem returns the CPU/PPU timing the game expects, one of TvSystemNTSC, TvSystemPAL, TvSystemMultiRegion or TvSystemDendy
 func (ines INesHeader) TvSystem() byte {
+	if ines.IsNES2() {
+		return ines.flags12 & 0x03
+	}
+	if ines.isArchaic() {
+		return TvSystemNTSC
+	}
+
 	return ines.flags9 & 0x01
 }
 
+// ConsoleType returns one of the Console* constants.
+// Values above ConsolePlaychoice10 are only available in NES 2.0 headers.
+func (ines INesHeader) ConsoleType() byte {
+	if ines.isArchaic() {
+		return ConsoleNES
+	}
+
+	consoleType := ines.flags7 & 0x03
+	if consoleType == 0x03 {
+		if !ines.IsNES2() {
+			return ConsoleNES
+		}
+		return ines.flags13 & 0x0F
+	}
+
+	return consoleType
+}
+
+// DefaultExpansionDevice returns the NES 2.0 id of the device plugged in the expansion port, 0 when unspecified
+func (ines INesHeader) DefaultExpansionDevice() byte {
+	if !ines.IsNES2() {
+		return 0
+	}
+
+	return ines.flags15 & 0x3F
+}
+
+func (ines INesHeader) isArchaic() bool {
+	if ines.IsNES2() {
+		return false
+	}
+
+	return ines.flags7&0x0C != 0 || ines.flags12|ines.flags13|ines.flags14|ines.flags15 != 0
+}
+
+func (ines INesHeader) ines1PRGRAMSize() int {
+	if ines.isArchaic() || ines.flags8 == 0 {
+		return 0x2000
+	}
+
+	return int(ines.flags8) * 0x2000
+}
+
+// nes2ROMLength decodes a NES 2.0 ROM size. When the upper nybble is 0xF the lower byte
+// is written in exponent-multiplier notation: EEEEEEMM => 2^E * (MM*2+1)
+func nes2ROMLength(lsb byte, msb byte, unit int) int {
+	if msb != 0x0F {
+		return (int(msb)<<8 | int(lsb)) * unit
+	}
+
+	exponent := lsb >> 2
+	multiplier := int(lsb&0x03)*2 + 1
+
+	return (1 << exponent) * multiplier
+}
+
+// nes2RAMLength decodes a NES 2.0 RAM shift count: 0 means no RAM, otherwise 64 << shift bytes
+func nes2RAMLength(shift byte) int {
+	if shift == 0 {
+		return 0
+	}
+
+	return 64 << shift
+}
+
+// NewINesHeader parses the 16 bytes at the start of an iNES or NES 2.0 rom file
+func NewINesHeader(data []byte) INesHeader {
+	return INesHeader{
+		prgROMSize: data[4],
+		chrROMSize: data[5],
+		flags6:     data[6],
+		flags7:     data[7],
+		flags8:     data[8],
+		flags9:     data[9],
+		flags10:    data[10],
+		flags11:    data[11],
+		flags12:    data[12],
+		flags13:    data[13],
+		flags14:    data[14],
+		flags15:    data[15],
+	}
+}
+
 func CreateINes1Header(prgRomSize byte, chrRomSize byte, flag6 byte, flag7 byte, flag8 byte, flag9 byte, flag10 byte) INesHeader {
 	// If the header CHR-ROM value is 0, we should assume that 8KB of CHR-RAM is available.
 	return INesHeader{
@@ -0,0 +1,118 @@
+package gamePak
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func nes2Header(bytes map[int]byte) INesHeader {
+	data := []byte{'N', 'E', 'S', 0x1A, 2, 1, 0, 0x08, 0, 0, 0, 0, 0, 0, 0, 0}
+	for index, value := range bytes {
+		data[index] = value
+	}
+
+	return NewINesHeader(data)
+}
+
+func TestINesHeader_ines1_fields(t *testing.T) {
+	header := CreateINes1Header(2, 0, 0x1E, 0x40, 0, 0x01, 0)
+
+	assert.False(t, header.IsNES2())
+	assert.Equal(t, uint16(0x41), header.MapperNumber())
+	assert.Equal(t, byte(0), header.SubMapper())
+	assert.True(t, header.HasPersistentMemory())
+	assert.True(t, header.HasTrainer())
+	assert.True(t, header.IgnoreMirroringControl())
+	assert.Equal(t, 0x8000, header.ProgramROMLength())
+	assert.Equal(t, 0, header.PRGRAMSize())
+	assert.Equal(t, 0x2000, header.PRGNVRAMSize(), "battery backed ram defaults to 8KB")
+	assert.Equal(t, 0x2000, header.CHRRAMSize(), "no CHR ROM implies 8KB of CHR RAM")
+	assert.Equal(t, TvSystemPAL, header.TvSystem())
+}
+
+func TestINesHeader_ignores_upper_mapper_nybble_on_archaic_headers(t *testing.T) {
+	data := []byte{'N', 'E', 'S', 0x1A, 2, 1, 0x10, 'D', 'i', 's', 'k', 'D', 'u', 'd', 'e', '!'}
+	header := NewINesHeader(data)
+
+	assert.False(t, header.IsNES2())
+	assert.Equal(t, uint16(1), header.MapperNumber())
+	assert.Equal(t, TvSystemNTSC, header.TvSystem())
+	assert.Equal(t, 0x2000, header.PRGRAMSize())
+}
+
+func TestINesHeader_nes2_mapper_and_submapper(t *testing.T) {
+	header := nes2Header(map[int]byte{6: 0x40, 7: 0x58, 8: 0x31})
+
+	assert.True(t, header.IsNES2())
+	assert.Equal(t, uint16(0x154), header.MapperNumber())
+	assert.Equal(t, byte(3), header.SubMapper())
+}
+
+func TestINesHeader_nes2_rom_sizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		bytes    map[int]byte
+		prgBytes int
+		chrBytes int
+	}{
+		{"plain sizes", map[int]byte{4: 2, 5: 1}, 0x8000, 0x2000},
+		{"upper nybbles", map[int]byte{4: 0x00, 5: 0x00, 9: 0x21}, 0x100 * 0x4000, 0x200 * 0x2000},
+		{"exponent multiplier", map[int]byte{4: 0x0D<<2 | 0x01, 5: 0x07 << 2, 9: 0xFF}, 3 << 0x0D, 1 << 0x07},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := nes2Header(tt.bytes)
+
+			assert.Equal(t, tt.prgBytes, header.ProgramROMLength())
+			assert.Equal(t, tt.chrBytes, header.CHRROMLength())
+		})
+	}
+}
+
+func TestINesHeader_nes2_ram_sizes(t *testing.T) {
+	header := nes2Header(map[int]byte{10: 0x97, 11: 0x07})
+
+	assert.Equal(t, 0x2000, header.PRGRAMSize())
+	assert.Equal(t, 0x8000, header.PRGNVRAMSize())
+	assert.Equal(t, 0x2000, header.CHRRAMSize())
+	assert.Equal(t, 0, header.CHRNVRAMSize())
+
+	header = nes2Header(map[int]byte{})
+	assert.Equal(t, 0, header.PRGRAMSize(), "shift count 0 means no ram")
+}
+
+func TestINesHeader_nes2_timing_console_and_expansion_device(t *testing.T) {
+	tests := []struct {
+		name            string
+		bytes           map[int]byte
+		tvSystem        byte
+		consoleType     byte
+		expansionDevice byte
+	}{
+		{"NTSC NES", map[int]byte{}, TvSystemNTSC, ConsoleNES, 0},
+		{"PAL", map[int]byte{12: 0x01}, TvSystemPAL, ConsoleNES, 0},
+		{"multi region", map[int]byte{12: 0x02}, TvSystemMultiRegion, ConsoleNES, 0},
+		{"Dendy", map[int]byte{12: 0x03}, TvSystemDendy, ConsoleNES, 0},
+		{"Vs. System with zapper", map[int]byte{7: 0x09, 15: 0x08}, TvSystemNTSC, ConsoleVsSystem, 0x08},
+		{"extended console type", map[int]byte{7: 0x0B, 13: 0x03}, TvSystemNTSC, ConsoleFamicloneDecimalMode, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := nes2Header(tt.bytes)
+
+			assert.Equal(t, tt.tvSystem, header.TvSystem())
+			assert.Equal(t, tt.consoleType, header.ConsoleType())
+			assert.Equal(t, tt.expansionDevice, header.DefaultExpansionDevice())
+		})
+	}
+}
+
+func TestGamePak_memories_are_sized_from_nes2_header(t *testing.T) {
+	header := nes2Header(map[int]byte{5: 0, 6: 0x10, 10: 0x08, 11: 0x08})
+	cartridge := CreateGamePak(header, make([]byte, 0x8000), nil)
+
+	assert.Equal(t, 0x4000, len(cartridge.prgRAM))
+	assert.Equal(t, 0x4000, len(cartridge.mapper.(*Mapper001).chrROM))
+}
@@ -1,13 +1,14 @@
 package gamePak
 
 import (
-	"fmt"
+	"github.com/raulferras/nes-golang/src/nes/savestate"
 	"github.com/raulferras/nes-golang/src/nes/types"
 )
 
 type Mapper interface {
 	PrgBanks() byte
 	ChrBanks() byte
+	Mirroring() byte
 
 	ReadPrgROM(address types.Address) byte
 	WritePrgROM(address types.Address, value byte)
@@ -15,11 +16,113 @@ type Mapper interface {
 	WriteChrROM(address types.Address, value byte)
 }
 
+// PPUAddressObserver is implemented by mappers that need to watch the addresses
+// the PPU puts on its address bus, like MMC3 counting scanlines through A12.
+type PPUAddressObserver interface {
+	OnPPUAddress(address types.Address, ppuCycle uint64)
+}
+
+// CPUCycleObserver is implemented by mappers that need to know the CPU cycle,
+// like MMC1 ignoring writes on consecutive cycles.
+type CPUCycleObserver interface {
+	OnCPUCycle(cpuCycle uint64)
+}
+
+// PRGRAMController is implemented by mappers able to disable or write protect
+// the PRG RAM the cartridge maps at 0x6000 -> 0x7FFF.
+type PRGRAMController interface {
+	PRGRAMEnabled() bool
+	PRGRAMWritable() bool
+}
+
+// IRQSource is implemented by mappers able to raise IRQs on the CPU.
+type IRQSource interface {
+	IRQ() bool
+}
+
+// NameTableMapper is implemented by mappers wiring the PPU nametables themselves, like MMC5 or Namco 163.
+// CIRAMPage is used when the mapper selects MapperMirroring. ReadNameTable and WriteNameTable return true
+// when the mapper disables console VRAM (CIRAM) for that address and answers from cartridge memory.
+type NameTableMapper interface {
+	CIRAMPage(nameTable byte) byte
+	ReadNameTable(address types.Address) (byte, bool)
+	WriteNameTable(address types.Address, value byte) bool
+}
+
+// StatefulMapper is implemented by mappers with state to keep in save states: bank registers, IRQ counters, CHR RAM.
+type StatefulMapper interface {
+	SyncState(state *savestate.Stream)
+}
+
 func CreateMapper(header Header, prgROM []byte, chrROM []byte) Mapper {
+	mapper, err := newMapper(header, prgROM, chrROM)
+	if err != nil {
+		panic(err)
+	}
+
+	return mapper
+}
+
+// prgBankSizes holds the size of the PRG ROM banks each mapper switches. PRG ROM must hold a bank at least.
+var prgBankSizes = map[uint16]int{
+	0:  0x4000,
+	1:  0x4000,
+	2:  0x4000,
+	3:  0x4000,
+	4:  0x2000,
+	7:  0x8000,
+	11: 0x8000,
+	34: 0x8000,
+	66: 0x8000,
+}
+
+func newMapper(header Header, prgROM []byte, chrROM []byte) (Mapper, error) {
+	bankSize, supported := prgBankSizes[header.MapperNumber()]
+	if supported && len(prgROM) < bankSize {
+		return nil, PRGROMSizeError{Mapper: header.MapperNumber(), Size: len(prgROM), BankSize: bankSize}
+	}
+
 	switch header.MapperNumber() {
 	case 0:
-		return CreateMapper000(header, prgROM, chrROM)
+		return CreateMapper000(header, prgROM, chrROM), nil
+	case 1:
+		return CreateMapper001(header, prgROM, chrROM), nil
+	case 2:
+		return CreateMapper002(header, prgROM, chrROM), nil
+	case 3:
+		return CreateMapper003(header, prgROM, chrROM), nil
+	case 4:
+		return CreateMapper004(header, prgROM, chrROM), nil
+	case 7:
+		return CreateMapper007(header, prgROM, chrROM), nil
+	case 11:
+		return CreateMapper011(header, prgROM, chrROM), nil
+	case 34:
+		return CreateMapper034(header, prgROM, chrROM), nil
+	case 66:
+		return CreateMapper066(header, prgROM, chrROM), nil
+	}
+
+	return nil, UnsupportedMapperError{Mapper: header.MapperNumber(), SubMapper: header.SubMapper()}
+}
+
+// busConflict emulates boards where the ROM is not disabled while the CPU writes to it.
+// Both drive the data bus at the same time, and the mapper latches the AND of the two values.
+func busConflict(mapper Mapper, address types.Address, value byte) byte {
+	return value & mapper.ReadPrgROM(address)
+}
+
+// chrMemory returns the CHR memory a mapper should use. Boards without CHR ROM carry CHR RAM,
+// 8KB unless the header says otherwise.
+func chrMemory(header Header, chrROM []byte) []byte {
+	if header.CHRSize() != 0 {
+		return chrROM
+	}
+
+	size := header.CHRRAMSize() + header.CHRNVRAMSize()
+	if size < 0x2000 {
+		size = 0x2000
 	}
 
-	panic(fmt.Sprintf("mapper %d not supported", header.MapperNumber()))
+	return make([]byte, size)
 }
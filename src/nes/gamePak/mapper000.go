@@ -1,6 +1,9 @@
 package gamePak
 
-import "github.com/raulferras/nes-golang/src/nes/types"
+import (
+	"github.com/raulferras/nes-golang/src/nes/savestate"
+	"github.com/raulferras/nes-golang/src/nes/types"
+)
 
 // if PRGROM is 16KB
 //     CPU Address Bus          PRG ROM
@@ -16,6 +19,7 @@ type Mapper000 struct {
 	prgROM      []byte
 	chrROM      []byte
 	hasCHRRAM   bool
+	mirroring   byte
 }
 
 func CreateMapper000(header Header, prgROM []byte, chrROM []byte) *Mapper000 {
@@ -24,13 +28,9 @@ func CreateMapper000(header Header, prgROM []byte, chrROM []byte) *Mapper000 {
 		prgROMBanks: header.ProgramSize(),
 		chrROMBanks: header.CHRSize(),
 		prgROM:      prgROM,
-		chrROM:      chrROM,
+		chrROM:      chrMemory(header, chrROM),
 		hasCHRRAM:   header.CHRSize() == 0,
-	}
-
-	if header.CHRSize() == 0 {
-		chrLength := 8 * 0x2000
-		mapper0.chrROM = make([]byte, chrLength)
+		mirroring:   header.Mirroring(),
 	}
 
 	return &mapper0
@@ -44,6 +44,10 @@ func (mapper *Mapper000) ChrBanks() byte {
 	return mapper.chrROMBanks
 }
 
+func (mapper *Mapper000) Mirroring() byte {
+	return mapper.mirroring
+}
+
 func (mapper *Mapper000) ReadPrgROM(address types.Address) byte {
 	if !satisfiableAddress(address) {
 		return 0
@@ -81,3 +85,10 @@ func satisfiableAddress(address types.Address) bool {
 
 	return false
 }
+
+// SyncState saves or loads CHR RAM, when the board has it
+func (mapper *Mapper000) SyncState(state *savestate.Stream) {
+	if mapper.hasCHRRAM {
+		state.SyncMemory(mapper.chrROM)
+	}
+}
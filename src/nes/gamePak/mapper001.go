@@ -0,0 +1,222 @@
+package gamePak
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/savestate"
+	"github.com/raulferras/nes-golang/src/nes/types"
+)
+
+// Mapper001 MMC1
+// The CPU talks to the mapper through a 5 bit serial shift register.
+// Writes to 0x8000 -> 0xFFFF shift bit 0 of the value in. On the fifth write the
+// register is copied into one of the internal registers, selected by bits 13 and 14 of the address.
+// Writing a value with bit 7 set resets the shift register.
+// Writes on the cycle following another write are ignored, so read-modify-write instructions only
+// get their first write through. Games rely on it to reset the mapper with INC on a 0xFF byte of ROM.
+//
+//	CPU Address Bus          GamePak
+//	0x6000 -> 0x7FFF: PRG RAM, provided by the GamePak. The mapper can disable it
+//	0x8000 -> 0xBFFF: 16KB PRG ROM bank, switchable or fixed to first bank
+//	0xC000 -> 0xFFFF: 16KB PRG ROM bank, switchable or fixed to last bank
+//
+//	PPU Address Bus
+//	0x0000 -> 0x0FFF: 4KB switchable CHR bank
+//	0x1000 -> 0x1FFF: 4KB switchable CHR bank
+type Mapper001 struct {
+	prgROMBanks byte
+	chrROMBanks byte
+	prgROM      []byte
+	chrROM      []byte
+	hasCHRRAM   bool
+
+	shiftRegister byte
+	shiftCount    byte
+
+	cpuCycle       uint64
+	lastWriteCycle uint64 // CPU cycle of last write to the shift register
+
+	// 43210
+	// |||||
+	// |||++- Mirroring (0: one-screen, lower bank; 1: one-screen, upper bank; 2: vertical; 3: horizontal)
+	// |++--- PRG ROM bank mode (0, 1: switch 32 KB at $8000, ignoring low bit of bank number;
+	// |                         2: fix first bank at $8000 and switch 16 KB bank at $C000;
+	// |                         3: fix last bank at $C000 and switch 16 KB bank at $8000)
+	// +----- CHR ROM bank mode (0: switch 8 KB at a time; 1: switch two separate 4 KB banks)
+	control  byte
+	chrBank0 byte
+	chrBank1 byte
+	prgBank  byte
+}
+
+const mmc1ControlRegister = 0
+const mmc1CHRBank0Register = 1
+const mmc1CHRBank1Register = 2
+const mmc1PRGBankRegister = 3
+
+func CreateMapper001(header Header, prgROM []byte, chrROM []byte) *Mapper001 {
+	mapper := Mapper001{
+		prgROMBanks: header.ProgramSize(),
+		chrROMBanks: header.CHRSize(),
+		prgROM:      prgROM,
+		chrROM:      chrMemory(header, chrROM),
+		hasCHRRAM:   header.CHRSize() == 0,
+		control:     0x0C, // MMC1 powers up with last bank fixed at 0xC000
+	}
+
+	return &mapper
+}
+
+func (mapper *Mapper001) PrgBanks() byte {
+	return mapper.prgROMBanks
+}
+
+func (mapper *Mapper001) ChrBanks() byte {
+	return mapper.chrROMBanks
+}
+
+func (mapper *Mapper001) Mirroring() byte {
+	switch mapper.control & 0b11 {
+	case 0:
+		return OneScreenMirroring
+	case 1:
+		return OneScreenUpperMirroring
+	case 2:
+		return VerticalMirroring
+	default:
+		return HorizontalMirroring
+	}
+}
+
+func (mapper *Mapper001) ReadPrgROM(address types.Address) byte {
+	if !satisfiableAddress(address) {
+		return 0
+	}
+
+	return mapper.prgROM[mapper.prgROMOffset(address)]
+}
+
+func (mapper *Mapper001) WritePrgROM(address types.Address, value byte) {
+	if !satisfiableAddress(address) {
+		return
+	}
+	consecutive := mapper.cpuCycle == mapper.lastWriteCycle+1
+	mapper.lastWriteCycle = mapper.cpuCycle
+	if consecutive {
+		return
+	}
+
+	if value&0x80 == 0x80 {
+		mapper.shiftRegister = 0
+		mapper.shiftCount = 0
+		mapper.control |= 0x0C
+		return
+	}
+
+	mapper.shiftRegister = (mapper.shiftRegister >> 1) | ((value & 0x01) << 4)
+	mapper.shiftCount++
+	if mapper.shiftCount < 5 {
+		return
+	}
+
+	// Bits 13 and 14 of the address select the target register
+	switch (address >> 13) & 0b11 {
+	case mmc1ControlRegister:
+		mapper.control = mapper.shiftRegister
+	case mmc1CHRBank0Register:
+		mapper.chrBank0 = mapper.shiftRegister
+	case mmc1CHRBank1Register:
+		mapper.chrBank1 = mapper.shiftRegister
+	case mmc1PRGBankRegister:
+		mapper.prgBank = mapper.shiftRegister
+	}
+
+	mapper.shiftRegister = 0
+	mapper.shiftCount = 0
+}
+
+func (mapper *Mapper001) OnCPUCycle(cpuCycle uint64) {
+	mapper.cpuCycle = cpuCycle
+}
+
+func (mapper *Mapper001) ReadChrROM(address types.Address) byte {
+	return mapper.chrROM[mapper.chrROMOffset(address)]
+}
+
+func (mapper *Mapper001) WriteChrROM(address types.Address, value byte) {
+	if !mapper.hasCHRRAM {
+		return
+	}
+
+	mapper.chrROM[mapper.chrROMOffset(address)] = value
+}
+
+func (mapper *Mapper001) PRGRAMEnabled() bool {
+	return mapper.prgBank&0x10 == 0
+}
+
+func (mapper *Mapper001) PRGRAMWritable() bool {
+	return mapper.PRGRAMEnabled()
+}
+
+func (mapper *Mapper001) prgROMOffset(address types.Address) int {
+	bankCount := len(mapper.prgROM) / 0x4000
+	bank := int(mapper.prgBank & 0x0F)
+
+	// SUROM boards use bit 4 of the CHR bank register to select which 256KB half of PRG ROM is visible
+	outerBank := 0
+	if bankCount > 16 {
+		outerBank = int(mapper.chrBank0 & 0x10)
+	}
+
+	switch (mapper.control >> 2) & 0b11 {
+	case 0, 1:
+		bank = (bank & 0x0E) | outerBank
+		if address >= 0xC000 {
+			bank++
+		}
+	case 2:
+		if address < 0xC000 {
+			bank = outerBank
+		} else {
+			bank |= outerBank
+		}
+	case 3:
+		if address < 0xC000 {
+			bank |= outerBank
+		} else {
+			bank = (outerBank | 0x0F) % bankCount
+		}
+	}
+
+	return (bank%bankCount)*0x4000 + int(address&0x3FFF)
+}
+
+func (mapper *Mapper001) chrROMOffset(address types.Address) int {
+	bankCount := len(mapper.chrROM) / 0x1000
+	if bankCount == 0 {
+		return int(address) % len(mapper.chrROM)
+	}
+
+	var bank int
+	if mapper.control&0x10 == 0 {
+		// 8KB mode, low bit of the bank number is ignored
+		bank = int(mapper.chrBank0 & 0x1E)
+		if address >= 0x1000 {
+			bank++
+		}
+	} else if address < 0x1000 {
+		bank = int(mapper.chrBank0)
+	} else {
+		bank = int(mapper.chrBank1)
+	}
+
+	return (bank%bankCount)*0x1000 + int(address&0x0FFF)
+}
+
+// SyncState saves or loads bank registers, and CHR RAM when the board has it
+func (mapper *Mapper001) SyncState(state *savestate.Stream) {
+	state.Sync(&mapper.shiftRegister, &mapper.shiftCount, &mapper.cpuCycle, &mapper.lastWriteCycle)
+	state.Sync(&mapper.control, &mapper.chrBank0, &mapper.chrBank1, &mapper.prgBank)
+	if mapper.hasCHRRAM {
+		state.SyncMemory(mapper.chrROM)
+	}
+}
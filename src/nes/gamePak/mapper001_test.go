@@ -0,0 +1,161 @@
+package gamePak
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func CreateMapper001ForTest(prgBanks byte, chrBanks byte) *Mapper001 {
+	prgROM := make([]byte, int(prgBanks)*0x4000)
+	for bank := 0; bank < int(prgBanks); bank++ {
+		prgROM[bank*0x4000] = byte(bank)
+	}
+
+	chrROM := make([]byte, int(chrBanks)*0x2000)
+	for bank := 0; bank < int(chrBanks)*2; bank++ {
+		chrROM[bank*0x1000] = byte(bank)
+	}
+
+	header := CreateINes1Header(prgBanks, chrBanks, 0x10, 0, 0, 0, 0)
+
+	return CreateMapper(header, prgROM, chrROM).(*Mapper001)
+}
+
+func mmc1SerialWrite(mapper *Mapper001, address types.Address, value byte) {
+	for i := 0; i < 5; i++ {
+		mapper.WritePrgROM(address, value>>i)
+	}
+}
+
+func TestMapper001_powers_up_with_last_bank_fixed_at_0xC000(t *testing.T) {
+	mapper := CreateMapper001ForTest(8, 1)
+
+	assert.Equal(t, byte(0), mapper.ReadPrgROM(0x8000))
+	assert.Equal(t, byte(7), mapper.ReadPrgROM(0xC000))
+}
+
+func TestMapper001_register_is_only_written_after_fifth_write(t *testing.T) {
+	mapper := CreateMapper001ForTest(8, 1)
+
+	for i := 0; i < 4; i++ {
+		mapper.WritePrgROM(0xE000, 0x01)
+		assert.Equal(t, byte(0), mapper.ReadPrgROM(0x8000))
+	}
+	mapper.WritePrgROM(0xE000, 0x00)
+
+	assert.Equal(t, byte(0x0F), mapper.prgBank)
+}
+
+func TestMapper001_writing_bit_7_resets_shift_register(t *testing.T) {
+	mapper := CreateMapper001ForTest(8, 1)
+	mmc1SerialWrite(mapper, 0x8000, 0x00)
+
+	mapper.WritePrgROM(0xE000, 0x01)
+	mapper.WritePrgROM(0xE000, 0x01)
+	mapper.WritePrgROM(0x8000, 0x80)
+	mmc1SerialWrite(mapper, 0xE000, 0x02)
+
+	assert.Equal(t, byte(0x02), mapper.prgBank)
+	assert.Equal(t, byte(0x0C), mapper.control&0x0C, "reset should fix last bank at 0xC000")
+}
+
+func TestMapper001_prg_bank_modes(t *testing.T) {
+	tests := []struct {
+		name     string
+		control  byte
+		prgBank  byte
+		bank8000 byte
+		bankC000 byte
+	}{
+		{"32KB mode ignores low bit", 0x00, 0x03, 2, 3},
+		{"fix first bank at 0x8000", 0x08, 0x03, 0, 3},
+		{"fix last bank at 0xC000", 0x0C, 0x03, 3, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper := CreateMapper001ForTest(8, 1)
+			mmc1SerialWrite(mapper, 0x8000, tt.control)
+			mmc1SerialWrite(mapper, 0xE000, tt.prgBank)
+
+			assert.Equal(t, tt.bank8000, mapper.ReadPrgROM(0x8000))
+			assert.Equal(t, tt.bankC000, mapper.ReadPrgROM(0xC000))
+		})
+	}
+}
+
+func TestMapper001_chr_bank_modes(t *testing.T) {
+	mapper := CreateMapper001ForTest(2, 4)
+
+	// 8KB mode
+	mmc1SerialWrite(mapper, 0x8000, 0x00)
+	mmc1SerialWrite(mapper, 0xA000, 0x03)
+	assert.Equal(t, byte(2), mapper.ReadChrROM(0x0000))
+	assert.Equal(t, byte(3), mapper.ReadChrROM(0x1000))
+
+	// 4KB mode
+	mmc1SerialWrite(mapper, 0x8000, 0x10)
+	mmc1SerialWrite(mapper, 0xA000, 0x05)
+	mmc1SerialWrite(mapper, 0xC000, 0x01)
+	assert.Equal(t, byte(5), mapper.ReadChrROM(0x0000))
+	assert.Equal(t, byte(1), mapper.ReadChrROM(0x1000))
+}
+
+func TestMapper001_mirroring_is_controlled_by_mapper(t *testing.T) {
+	tests := []struct {
+		control   byte
+		mirroring byte
+	}{
+		{0, OneScreenMirroring},
+		{1, OneScreenUpperMirroring},
+		{2, VerticalMirroring},
+		{3, HorizontalMirroring},
+	}
+
+	mapper := CreateMapper001ForTest(2, 1)
+	for _, tt := range tests {
+		mmc1SerialWrite(mapper, 0x8000, tt.control)
+		assert.Equal(t, tt.mirroring, mapper.Mirroring())
+	}
+}
+
+func TestMapper001_prg_ram_can_be_disabled(t *testing.T) {
+	header := CreateINes1Header(2, 1, 0x10, 0, 0, 0, 0)
+	cartridge := CreateGamePak(header, make([]byte, 0x8000), make([]byte, 0x2000))
+	mapper := cartridge.mapper.(*Mapper001)
+
+	cartridge.WritePrgROM(0x6000, 0xAB)
+	assert.Equal(t, byte(0xAB), cartridge.ReadPrgROM(0x6000))
+
+	mmc1SerialWrite(mapper, 0xE000, 0x10)
+	cartridge.WritePrgROM(0x6000, 0xCD)
+	assert.Equal(t, byte(0), cartridge.ReadPrgROM(0x6000))
+
+	mmc1SerialWrite(mapper, 0xE000, 0x00)
+	assert.Equal(t, byte(0xAB), cartridge.ReadPrgROM(0x6000))
+}
+
+func TestMapper001_chr_ram_is_writable(t *testing.T) {
+	mapper := CreateMapper001ForTest(2, 0)
+
+	mapper.WriteChrROM(0x1234, 0x42)
+
+	assert.Equal(t, byte(0x42), mapper.ReadChrROM(0x1234))
+}
+
+func TestMapper001_ignores_writes_on_consecutive_cycles(t *testing.T) {
+	mapper := CreateMapper001ForTest(8, 1)
+	mmc1SerialWrite(mapper, 0xE000, 0x03)
+
+	// INC $E000 on a 0xFF byte: writes 0xFF back, resetting the mapper, then 0x00 on next cycle
+	mapper.OnCPUCycle(100)
+	mapper.WritePrgROM(0xE000, 0xFF)
+	mapper.OnCPUCycle(101)
+	mapper.WritePrgROM(0xE000, 0x00)
+	assert.Equal(t, byte(0), mapper.shiftCount, "second write should be ignored")
+
+	mapper.OnCPUCycle(103)
+	mmc1SerialWrite(mapper, 0xE000, 0x05)
+	assert.Equal(t, byte(0x05), mapper.prgBank)
+}
@@ -0,0 +1,88 @@
+package gamePak
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/savestate"
+	"github.com/raulferras/nes-golang/src/nes/types"
+)
+
+// Mapper002 UxROM
+//
+//	CPU Address Bus
+//	0x8000 -> 0xBFFF: 16KB switchable PRG ROM bank
+//	0xC000 -> 0xFFFF: 16KB PRG ROM bank, fixed to the last bank
+//
+// Writing to 0x8000 -> 0xFFFF selects the bank. Boards have bus conflicts.
+// CHR is usually 8KB of RAM.
+type Mapper002 struct {
+	prgROMBanks byte
+	chrROMBanks byte
+	prgROM      []byte
+	chrROM      []byte
+	hasCHRRAM   bool
+	mirroring   byte
+
+	prgBank byte
+}
+
+func CreateMapper002(header Header, prgROM []byte, chrROM []byte) *Mapper002 {
+	return &Mapper002{
+		prgROMBanks: header.ProgramSize(),
+		chrROMBanks: header.CHRSize(),
+		prgROM:      prgROM,
+		chrROM:      chrMemory(header, chrROM),
+		hasCHRRAM:   header.CHRSize() == 0,
+		mirroring:   header.Mirroring(),
+	}
+}
+
+func (mapper *Mapper002) PrgBanks() byte {
+	return mapper.prgROMBanks
+}
+
+func (mapper *Mapper002) ChrBanks() byte {
+	return mapper.chrROMBanks
+}
+
+func (mapper *Mapper002) Mirroring() byte {
+	return mapper.mirroring
+}
+
+func (mapper *Mapper002) ReadPrgROM(address types.Address) byte {
+	if !satisfiableAddress(address) {
+		return 0
+	}
+
+	bankCount := len(mapper.prgROM) / 0x4000
+	bank := bankCount - 1
+	if address < 0xC000 {
+		bank = int(mapper.prgBank) % bankCount
+	}
+
+	return mapper.prgROM[bank*0x4000+int(address&0x3FFF)]
+}
+
+func (mapper *Mapper002) WritePrgROM(address types.Address, value byte) {
+	if !satisfiableAddress(address) {
+		return
+	}
+
+	mapper.prgBank = busConflict(mapper, address, value)
+}
+
+func (mapper *Mapper002) ReadChrROM(address types.Address) byte {
+	return mapper.chrROM[address&0x1FFF]
+}
+
+func (mapper *Mapper002) WriteChrROM(address types.Address, value byte) {
+	if mapper.hasCHRRAM {
+		mapper.chrROM[address&0x1FFF] = value
+	}
+}
+
+// SyncState saves or loads bank registers, and CHR RAM when the board has it
+func (mapper *Mapper002) SyncState(state *savestate.Stream) {
+	state.Sync(&mapper.prgBank)
+	if mapper.hasCHRRAM {
+		state.SyncMemory(mapper.chrROM)
+	}
+}
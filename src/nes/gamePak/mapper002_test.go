@@ -0,0 +1,61 @@
+package gamePak
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// bankedROM returns a ROM where the first byte of each bank holds the bank number
+func bankedROM(size int, bankSize int) []byte {
+	rom := make([]byte, size)
+	for bank := 0; bank < size/bankSize; bank++ {
+		rom[bank*bankSize] = byte(bank)
+	}
+
+	return rom
+}
+
+// fillROM sets every byte of the ROM to the same value, useful to check bus conflicts
+func fillROM(rom []byte, value byte) []byte {
+	for i := range rom {
+		rom[i] = value
+	}
+
+	return rom
+}
+
+func CreateMapper002ForTest(prgROM []byte) Mapper {
+	header := CreateINes1Header(byte(len(prgROM)/0x4000), 0, 0x20, 0, 0, 0, 0)
+
+	return CreateMapper(header, prgROM, []byte{})
+}
+
+func TestMapper002_switches_bank_at_0x8000_and_fixes_last_bank_at_0xC000(t *testing.T) {
+	mapper := CreateMapper002ForTest(fillROM(make([]byte, 8*0x4000), 0xFF))
+	for bank := 0; bank < 8; bank++ {
+		mapper.(*Mapper002).prgROM[bank*0x4000] = byte(bank)
+	}
+
+	assert.Equal(t, byte(0), mapper.ReadPrgROM(0x8000))
+	assert.Equal(t, byte(7), mapper.ReadPrgROM(0xC000))
+
+	mapper.WritePrgROM(0x8001, 3)
+
+	assert.Equal(t, byte(3), mapper.ReadPrgROM(0x8000))
+	assert.Equal(t, byte(7), mapper.ReadPrgROM(0xC000))
+}
+
+func TestMapper002_has_bus_conflicts(t *testing.T) {
+	mapper := CreateMapper002ForTest(bankedROM(8*0x4000, 0x4000))
+	mapper.WritePrgROM(0x8000, 1) // ROM has 0x00 at 0x8000
+
+	assert.Equal(t, byte(0), mapper.ReadPrgROM(0x8000), "written value should have been ANDed with ROM value")
+}
+
+func TestMapper002_has_chr_ram(t *testing.T) {
+	mapper := CreateMapper002ForTest(bankedROM(2*0x4000, 0x4000))
+
+	mapper.WriteChrROM(0x1FFF, 0x55)
+
+	assert.Equal(t, byte(0x55), mapper.ReadChrROM(0x1FFF))
+}
@@ -0,0 +1,89 @@
+package gamePak
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/savestate"
+	"github.com/raulferras/nes-golang/src/nes/types"
+)
+
+// Mapper003 CNROM
+//
+//	CPU Address Bus
+//	0x8000 -> 0xFFFF: 16KB or 32KB PRG ROM, mapped like NROM
+//
+//	PPU Address Bus
+//	0x0000 -> 0x1FFF: 8KB switchable CHR ROM bank
+//
+// Writing to 0x8000 -> 0xFFFF selects the CHR bank. Boards have bus conflicts.
+type Mapper003 struct {
+	prgROMBanks byte
+	chrROMBanks byte
+	prgROM      []byte
+	chrROM      []byte
+	hasCHRRAM   bool
+	mirroring   byte
+
+	chrBank byte
+}
+
+func CreateMapper003(header Header, prgROM []byte, chrROM []byte) *Mapper003 {
+	return &Mapper003{
+		prgROMBanks: header.ProgramSize(),
+		chrROMBanks: header.CHRSize(),
+		prgROM:      prgROM,
+		chrROM:      chrMemory(header, chrROM),
+		hasCHRRAM:   header.CHRSize() == 0,
+		mirroring:   header.Mirroring(),
+	}
+}
+
+func (mapper *Mapper003) PrgBanks() byte {
+	return mapper.prgROMBanks
+}
+
+func (mapper *Mapper003) ChrBanks() byte {
+	return mapper.chrROMBanks
+}
+
+func (mapper *Mapper003) Mirroring() byte {
+	return mapper.mirroring
+}
+
+func (mapper *Mapper003) ReadPrgROM(address types.Address) byte {
+	if !satisfiableAddress(address) {
+		return 0
+	}
+
+	return mapper.prgROM[int(address&0x7FFF)%len(mapper.prgROM)]
+}
+
+func (mapper *Mapper003) WritePrgROM(address types.Address, value byte) {
+	if !satisfiableAddress(address) {
+		return
+	}
+
+	mapper.chrBank = busConflict(mapper, address, value)
+}
+
+func (mapper *Mapper003) ReadChrROM(address types.Address) byte {
+	return mapper.chrROM[mapper.chrROMOffset(address)]
+}
+
+func (mapper *Mapper003) WriteChrROM(address types.Address, value byte) {
+	if mapper.hasCHRRAM {
+		mapper.chrROM[mapper.chrROMOffset(address)] = value
+	}
+}
+
+func (mapper *Mapper003) chrROMOffset(address types.Address) int {
+	bankCount := len(mapper.chrROM) / 0x2000
+
+	return (int(mapper.chrBank)%bankCount)*0x2000 + int(address&0x1FFF)
+}
+
+// SyncState saves or loads bank registers, and CHR RAM when the board has it
+func (mapper *Mapper003) SyncState(state *savestate.Stream) {
+	state.Sync(&mapper.chrBank)
+	if mapper.hasCHRRAM {
+		state.SyncMemory(mapper.chrROM)
+	}
+}
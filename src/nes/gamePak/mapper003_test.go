@@ -0,0 +1,43 @@
+package gamePak
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func CreateMapper003ForTest(prgBanks byte, chrBanks byte) Mapper {
+	header := CreateINes1Header(prgBanks, chrBanks, 0x30, 0, 0, 0, 0)
+
+	return CreateMapper(
+		header,
+		fillROM(make([]byte, int(prgBanks)*0x4000), 0xFF),
+		bankedROM(int(chrBanks)*0x2000, 0x2000),
+	)
+}
+
+func TestMapper003_maps_prg_rom_like_nrom(t *testing.T) {
+	mapper := CreateMapper003ForTest(1, 4)
+	mapper.(*Mapper003).prgROM[0x3FFF] = 0x3F
+
+	assert.Equal(t, byte(0x3F), mapper.ReadPrgROM(0xBFFF))
+	assert.Equal(t, byte(0x3F), mapper.ReadPrgROM(0xFFFF), "mirroring failed")
+}
+
+func TestMapper003_switches_chr_bank(t *testing.T) {
+	mapper := CreateMapper003ForTest(2, 4)
+
+	mapper.WritePrgROM(0x8000, 2)
+	assert.Equal(t, byte(2), mapper.ReadChrROM(0x0000))
+
+	mapper.WritePrgROM(0xFFFF, 3)
+	assert.Equal(t, byte(3), mapper.ReadChrROM(0x0000))
+}
+
+func TestMapper003_has_bus_conflicts(t *testing.T) {
+	mapper := CreateMapper003ForTest(2, 4)
+	mapper.(*Mapper003).prgROM[0x0010] = 0x01
+
+	mapper.WritePrgROM(0x8010, 3)
+
+	assert.Equal(t, byte(1), mapper.ReadChrROM(0x0000))
+}
@@ -0,0 +1,234 @@
+package gamePak
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/savestate"
+	"github.com/raulferras/nes-golang/src/nes/types"
+)
+
+// Mapper004 MMC3
+//
+//	CPU Address Bus
+//	0x6000 -> 0x7FFF: PRG RAM, provided by the GamePak. The mapper can disable or write protect it
+//	0x8000 -> 0x9FFF: 8KB switchable PRG ROM bank (R6) or fixed to second-last bank
+//	0xA000 -> 0xBFFF: 8KB switchable PRG ROM bank (R7)
+//	0xC000 -> 0xDFFF: 8KB PRG ROM bank fixed to second-last bank or switchable (R6)
+//	0xE000 -> 0xFFFF: 8KB PRG ROM bank fixed to last bank
+//
+//	PPU Address Bus (CHR A12 inversion swaps both halves)
+//	0x0000 -> 0x07FF: 2KB switchable CHR bank (R0)
+//	0x0800 -> 0x0FFF: 2KB switchable CHR bank (R1)
+//	0x1000 -> 0x1FFF: four 1KB switchable CHR banks (R2 -> R5)
+//
+// It also has a scanline counter clocked by rising edges of PPU address line A12,
+// which raises an IRQ when it reaches zero.
+type Mapper004 struct {
+	prgROMBanks byte
+	chrROMBanks byte
+	prgROM      []byte
+	chrROM      []byte
+	hasCHRRAM   bool
+
+	bankSelect    byte
+	banks         [8]byte
+	mirroring     byte
+	fourScreen    bool
+	prgRAMEnabled bool
+	prgRAMWrites  bool
+
+	irqLatch   byte
+	irqCounter byte
+	irqReload  bool
+	irqEnabled bool
+	irqPending bool
+
+	a12High     bool
+	a12LowSince uint64 // PPU cycle when A12 last went low
+}
+
+// A12 has to stay low for 10 PPU cycles, about 3 CPU cycles, before a rising edge clocks the counter.
+// This filters out the edges caused by the short toggles between pattern and nametable fetches.
+const mmc3A12LowPPUCycles = 10
+
+func CreateMapper004(header Header, prgROM []byte, chrROM []byte) *Mapper004 {
+	mapper := Mapper004{
+		prgROMBanks:   header.ProgramSize(),
+		chrROMBanks:   header.CHRSize(),
+		prgROM:        prgROM,
+		chrROM:        chrMemory(header, chrROM),
+		hasCHRRAM:     header.CHRSize() == 0,
+		mirroring:     header.Mirroring(),
+		fourScreen:    header.Mirroring() == FourScreenMirroring,
+		prgRAMEnabled: true,
+		prgRAMWrites:  true,
+	}
+
+	return &mapper
+}
+
+func (mapper *Mapper004) PrgBanks() byte {
+	return mapper.prgROMBanks
+}
+
+func (mapper *Mapper004) ChrBanks() byte {
+	return mapper.chrROMBanks
+}
+
+func (mapper *Mapper004) Mirroring() byte {
+	return mapper.mirroring
+}
+
+func (mapper *Mapper004) ReadPrgROM(address types.Address) byte {
+	if !satisfiableAddress(address) {
+		return 0
+	}
+
+	return mapper.prgROM[mapper.prgROMOffset(address)]
+}
+
+func (mapper *Mapper004) WritePrgROM(address types.Address, value byte) {
+	if !satisfiableAddress(address) {
+		return
+	}
+
+	even := address&0x01 == 0
+	switch {
+	case address <= 0x9FFF && even:
+		mapper.bankSelect = value
+	case address <= 0x9FFF:
+		mapper.banks[mapper.bankSelect&0x07] = value
+	case address <= 0xBFFF && even:
+		if mapper.fourScreen {
+			break
+		}
+		if value&0x01 == 0 {
+			mapper.mirroring = VerticalMirroring
+		} else {
+			mapper.mirroring = HorizontalMirroring
+		}
+	case address <= 0xBFFF:
+		mapper.prgRAMEnabled = value&0x80 == 0x80
+		mapper.prgRAMWrites = value&0x40 == 0
+	case address <= 0xDFFF && even:
+		mapper.irqLatch = value
+	case address <= 0xDFFF:
+		mapper.irqCounter = 0
+		mapper.irqReload = true
+	case even:
+		mapper.irqEnabled = false
+		mapper.irqPending = false
+	default:
+		mapper.irqEnabled = true
+	}
+}
+
+func (mapper *Mapper004) ReadChrROM(address types.Address) byte {
+	return mapper.chrROM[mapper.chrROMOffset(address)]
+}
+
+func (mapper *Mapper004) WriteChrROM(address types.Address, value byte) {
+	if !mapper.hasCHRRAM {
+		return
+	}
+
+	mapper.chrROM[mapper.chrROMOffset(address)] = value
+}
+
+func (mapper *Mapper004) PRGRAMEnabled() bool {
+	return mapper.prgRAMEnabled
+}
+
+func (mapper *Mapper004) PRGRAMWritable() bool {
+	return mapper.prgRAMEnabled && mapper.prgRAMWrites
+}
+
+// OnPPUAddress watches PPU A12 to clock the scanline counter.
+func (mapper *Mapper004) OnPPUAddress(address types.Address, ppuCycle uint64) {
+	a12High := address&0x1000 == 0x1000
+	if a12High && !mapper.a12High {
+		if ppuCycle-mapper.a12LowSince >= mmc3A12LowPPUCycles {
+			mapper.clockScanlineCounter()
+		}
+	} else if !a12High && mapper.a12High {
+		mapper.a12LowSince = ppuCycle
+	}
+
+	mapper.a12High = a12High
+}
+
+func (mapper *Mapper004) IRQ() bool {
+	return mapper.irqPending
+}
+
+func (mapper *Mapper004) clockScanlineCounter() {
+	if mapper.irqCounter == 0 || mapper.irqReload {
+		mapper.irqCounter = mapper.irqLatch
+		mapper.irqReload = false
+	} else {
+		mapper.irqCounter--
+	}
+
+	if mapper.irqCounter == 0 && mapper.irqEnabled {
+		mapper.irqPending = true
+	}
+}
+
+func (mapper *Mapper004) prgROMOffset(address types.Address) int {
+	bankCount := len(mapper.prgROM) / 0x2000
+	secondLast := bankCount - 2
+	prgMode := mapper.bankSelect & 0x40
+
+	var bank int
+	switch address & 0xE000 {
+	case 0x8000:
+		if prgMode == 0 {
+			bank = int(mapper.banks[6] & 0x3F)
+		} else {
+			bank = secondLast
+		}
+	case 0xA000:
+		bank = int(mapper.banks[7] & 0x3F)
+	case 0xC000:
+		if prgMode == 0 {
+			bank = secondLast
+		} else {
+			bank = int(mapper.banks[6] & 0x3F)
+		}
+	default:
+		bank = bankCount - 1
+	}
+
+	return (bank%bankCount)*0x2000 + int(address&0x1FFF)
+}
+
+func (mapper *Mapper004) chrROMOffset(address types.Address) int {
+	bankCount := len(mapper.chrROM) / 0x0400
+
+	// CHR A12 inversion swaps 2KB and 1KB halves
+	if mapper.bankSelect&0x80 == 0x80 {
+		address ^= 0x1000
+	}
+
+	var bank int
+	switch {
+	case address < 0x0800:
+		bank = int(mapper.banks[0]&0xFE) + int(address>>10&0x01)
+	case address < 0x1000:
+		bank = int(mapper.banks[1]&0xFE) + int(address>>10&0x01)
+	default:
+		bank = int(mapper.banks[2+(address-0x1000)>>10])
+	}
+
+	return (bank%bankCount)*0x0400 + int(address&0x03FF)
+}
+
+// SyncState saves or loads bank registers and IRQ counter, and CHR RAM when the board has it
+func (mapper *Mapper004) SyncState(state *savestate.Stream) {
+	state.Sync(
+		&mapper.bankSelect, &mapper.banks, &mapper.mirroring, &mapper.prgRAMEnabled, &mapper.prgRAMWrites,
+		&mapper.irqLatch, &mapper.irqCounter, &mapper.irqReload, &mapper.irqEnabled, &mapper.irqPending,
+		&mapper.a12High, &mapper.a12LowSince,
+	)
+	if mapper.hasCHRRAM {
+		state.SyncMemory(mapper.chrROM)
+	}
+}
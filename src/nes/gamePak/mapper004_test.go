@@ -0,0 +1,167 @@
+package gamePak
+
+import (
+	"bytes"
+	"github.com/raulferras/nes-golang/src/nes/savestate"
+	"github.com/raulferras/nes-golang/src/nes/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func CreateMapper004ForTest(prgBanks byte, chrBanks byte) *Mapper004 {
+	prgROM := make([]byte, int(prgBanks)*0x4000)
+	for bank := 0; bank < int(prgBanks)*2; bank++ {
+		prgROM[bank*0x2000] = byte(bank)
+	}
+
+	chrROM := make([]byte, int(chrBanks)*0x2000)
+	for bank := 0; bank < int(chrBanks)*8; bank++ {
+		chrROM[bank*0x0400] = byte(bank)
+	}
+
+	header := CreateINes1Header(prgBanks, chrBanks, 0x40, 0, 0, 0, 0)
+
+	return CreateMapper(header, prgROM, chrROM).(*Mapper004)
+}
+
+// Rises A12 after keeping it low long enough for the mapper to count the edge
+func clockA12(mapper *Mapper004, ppuCycle *uint64) {
+	mapper.OnPPUAddress(0x0000, *ppuCycle)
+	*ppuCycle += 20
+	mapper.OnPPUAddress(0x1000, *ppuCycle)
+	*ppuCycle += 20
+}
+
+func TestMapper004_prg_banks(t *testing.T) {
+	mapper := CreateMapper004ForTest(8, 1) // 16 banks of 8KB
+	mapper.WritePrgROM(0x8000, 6)
+	mapper.WritePrgROM(0x8001, 3)
+	mapper.WritePrgROM(0x8000, 7)
+	mapper.WritePrgROM(0x8001, 5)
+
+	assert.Equal(t, byte(3), mapper.ReadPrgROM(0x8000))
+	assert.Equal(t, byte(5), mapper.ReadPrgROM(0xA000))
+	assert.Equal(t, byte(14), mapper.ReadPrgROM(0xC000))
+	assert.Equal(t, byte(15), mapper.ReadPrgROM(0xE000))
+
+	// PRG mode 1 swaps 0x8000 and 0xC000
+	mapper.WritePrgROM(0x8000, 0x40)
+	assert.Equal(t, byte(14), mapper.ReadPrgROM(0x8000))
+	assert.Equal(t, byte(3), mapper.ReadPrgROM(0xC000))
+}
+
+func TestMapper004_chr_banks(t *testing.T) {
+	mapper := CreateMapper004ForTest(2, 4) // 32 banks of 1KB
+	for register, bank := range []byte{4, 10, 20, 21, 22, 23} {
+		mapper.WritePrgROM(0x8000, byte(register))
+		mapper.WritePrgROM(0x8001, bank)
+	}
+
+	assert.Equal(t, byte(4), mapper.ReadChrROM(0x0000))
+	assert.Equal(t, byte(5), mapper.ReadChrROM(0x0400))
+	assert.Equal(t, byte(10), mapper.ReadChrROM(0x0800))
+	assert.Equal(t, byte(20), mapper.ReadChrROM(0x1000))
+	assert.Equal(t, byte(23), mapper.ReadChrROM(0x1C00))
+
+	// CHR A12 inversion
+	mapper.WritePrgROM(0x8000, 0x80)
+	assert.Equal(t, byte(20), mapper.ReadChrROM(0x0000))
+	assert.Equal(t, byte(4), mapper.ReadChrROM(0x1000))
+	assert.Equal(t, byte(11), mapper.ReadChrROM(0x1C00))
+}
+
+func TestMapper004_mirroring(t *testing.T) {
+	mapper := CreateMapper004ForTest(2, 1)
+
+	mapper.WritePrgROM(0xA000, 0)
+	assert.Equal(t, VerticalMirroring, mapper.Mirroring())
+
+	mapper.WritePrgROM(0xA000, 1)
+	assert.Equal(t, HorizontalMirroring, mapper.Mirroring())
+}
+
+func TestMapper004_prg_ram_protect(t *testing.T) {
+	header := CreateINes1Header(2, 1, 0x40, 0, 0, 0, 0)
+	cartridge := CreateGamePak(header, make([]byte, 0x8000), make([]byte, 0x2000))
+	cartridge.WritePrgROM(0x6000, 0x11)
+
+	cartridge.WritePrgROM(0xA001, 0xC0) // enabled, write protected
+	cartridge.WritePrgROM(0x6000, 0x22)
+	assert.Equal(t, byte(0x11), cartridge.ReadPrgROM(0x6000))
+
+	cartridge.WritePrgROM(0xA001, 0x00) // disabled
+	assert.Equal(t, byte(0), cartridge.ReadPrgROM(0x6000))
+}
+
+func TestMapper004_irq_is_raised_when_counter_reaches_zero(t *testing.T) {
+	mapper := CreateMapper004ForTest(2, 1)
+	ppuCycle := uint64(100)
+	mapper.WritePrgROM(0xC000, 3) // latch
+	mapper.WritePrgROM(0xC001, 0) // reload
+	mapper.WritePrgROM(0xE001, 0) // enable
+
+	clockA12(mapper, &ppuCycle) // reload to 3
+	clockA12(mapper, &ppuCycle) // 2
+	clockA12(mapper, &ppuCycle) // 1
+	assert.False(t, mapper.IRQ())
+
+	clockA12(mapper, &ppuCycle) // 0
+	assert.True(t, mapper.IRQ())
+
+	mapper.WritePrgROM(0xE000, 0)
+	assert.False(t, mapper.IRQ(), "writing to 0xE000 should acknowledge the irq")
+}
+
+func TestMapper004_short_a12_pulses_are_filtered(t *testing.T) {
+	mapper := CreateMapper004ForTest(2, 1)
+	mapper.WritePrgROM(0xC000, 1)
+	mapper.WritePrgROM(0xC001, 0)
+	mapper.WritePrgROM(0xE001, 0)
+
+	ppuCycle := uint64(100)
+	for i := 0; i < 10; i++ {
+		mapper.OnPPUAddress(types.Address(0x0000), ppuCycle)
+		ppuCycle += 2
+		mapper.OnPPUAddress(types.Address(0x1000), ppuCycle)
+		ppuCycle += 2
+	}
+
+	assert.Equal(t, byte(1), mapper.irqCounter, "only first rising edge should be counted")
+	assert.False(t, mapper.IRQ())
+}
+
+func TestMapper004_disabled_irq_is_not_raised(t *testing.T) {
+	mapper := CreateMapper004ForTest(2, 1)
+	ppuCycle := uint64(100)
+	mapper.WritePrgROM(0xC000, 0)
+	mapper.WritePrgROM(0xC001, 0)
+
+	clockA12(mapper, &ppuCycle)
+
+	assert.False(t, mapper.IRQ())
+}
+
+func TestMapper004_state_keeps_banks_and_irq_counter(t *testing.T) {
+	mapper := CreateMapper004ForTest(8, 1)
+	ppuCycle := uint64(100)
+	mapper.WritePrgROM(0x8000, 6)
+	mapper.WritePrgROM(0x8001, 3)
+	mapper.WritePrgROM(0xC000, 2)
+	mapper.WritePrgROM(0xC001, 0)
+	mapper.WritePrgROM(0xE001, 0)
+	clockA12(mapper, &ppuCycle) // reload to 2
+
+	state := bytes.Buffer{}
+	mapper.SyncState(savestate.NewSaver(&state))
+
+	loaded := CreateMapper004ForTest(8, 1)
+	loader := savestate.NewLoader(&state)
+	loaded.SyncState(loader)
+
+	assert.NoError(t, loader.Err())
+	assert.Equal(t, byte(3), loaded.ReadPrgROM(0x8000))
+	clockA12(loaded, &ppuCycle) // 1
+	assert.False(t, loaded.IRQ())
+	clockA12(loaded, &ppuCycle) // 0
+	assert.True(t, loaded.IRQ())
+}
@@ -0,0 +1,98 @@
+package gamePak
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/savestate"
+	"github.com/raulferras/nes-golang/src/nes/types"
+)
+
+// Mapper007 AxROM
+//
+//	CPU Address Bus
+//	0x8000 -> 0xFFFF: 32KB switchable PRG ROM bank
+//
+// Writing to 0x8000 -> 0xFFFF:
+//
+//	7  bit  0
+//	---- ----
+//	xxxM xPPP
+//	   |  |||
+//	   |  +++- Select 32 KB PRG ROM bank for CPU $8000-$FFFF
+//	   +------ Select 1 KB VRAM page for all 4 nametables (one-screen mirroring)
+//
+// ANROM boards have bus conflicts. CHR is 8KB of RAM.
+type Mapper007 struct {
+	prgROMBanks byte
+	chrROMBanks byte
+	prgROM      []byte
+	chrROM      []byte
+	hasCHRRAM   bool
+
+	prgBank   byte
+	mirroring byte
+}
+
+func CreateMapper007(header Header, prgROM []byte, chrROM []byte) *Mapper007 {
+	return &Mapper007{
+		prgROMBanks: header.ProgramSize(),
+		chrROMBanks: header.CHRSize(),
+		prgROM:      prgROM,
+		chrROM:      chrMemory(header, chrROM),
+		hasCHRRAM:   header.CHRSize() == 0,
+		mirroring:   OneScreenMirroring,
+	}
+}
+
+func (mapper *Mapper007) PrgBanks() byte {
+	return mapper.prgROMBanks
+}
+
+func (mapper *Mapper007) ChrBanks() byte {
+	return mapper.chrROMBanks
+}
+
+func (mapper *Mapper007) Mirroring() byte {
+	return mapper.mirroring
+}
+
+func (mapper *Mapper007) ReadPrgROM(address types.Address) byte {
+	if !satisfiableAddress(address) {
+		return 0
+	}
+
+	bankCount := len(mapper.prgROM) / 0x8000
+	bank := int(mapper.prgBank) % bankCount
+
+	return mapper.prgROM[bank*0x8000+int(address&0x7FFF)]
+}
+
+func (mapper *Mapper007) WritePrgROM(address types.Address, value byte) {
+	if !satisfiableAddress(address) {
+		return
+	}
+
+	value = busConflict(mapper, address, value)
+	mapper.prgBank = value & 0x07
+	if value&0x10 == 0x10 {
+		mapper.mirroring = OneScreenUpperMirroring
+	} else {
+		mapper.mirroring = OneScreenMirroring
+	}
+}
+
+func (mapper *Mapper007) ReadChrROM(address types.Address) byte {
+	return mapper.chrROM[address&0x1FFF]
+}
+
+func (mapper *Mapper007) WriteChrROM(address types.Address, value byte) {
+	if mapper.hasCHRRAM {
+		mapper.chrROM[address&0x1FFF] = value
+	}
+}
+
+// SyncState saves or loads bank registers, and CHR RAM when the board has it
+func (mapper *Mapper007) SyncState(state *savestate.Stream) {
+	state.Sync(&mapper.prgBank, &mapper.mirroring)
+	if mapper.hasCHRRAM {
+		state.SyncMemory(mapper.chrROM)
+	}
+}
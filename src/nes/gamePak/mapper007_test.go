@@ -0,0 +1,45 @@
+package gamePak
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func CreateMapper007ForTest() Mapper {
+	prgROM := bankedROM(8*0x8000, 0x8000)
+	for bank := 0; bank < 8; bank++ {
+		prgROM[bank*0x8000+0x7FFF] = 0xFF
+	}
+	header := CreateINes1Header(16, 0, 0x70, 0, 0, 0, 0)
+
+	return CreateMapper(header, prgROM, []byte{})
+}
+
+func TestMapper007_switches_32KB_prg_bank(t *testing.T) {
+	mapper := CreateMapper007ForTest()
+	assert.Equal(t, byte(0), mapper.ReadPrgROM(0x8000))
+
+	mapper.WritePrgROM(0xFFFF, 5)
+
+	assert.Equal(t, byte(5), mapper.ReadPrgROM(0x8000))
+}
+
+func TestMapper007_selects_one_screen_nametable_at_runtime(t *testing.T) {
+	mapper := CreateMapper007ForTest()
+	assert.Equal(t, OneScreenMirroring, mapper.Mirroring())
+
+	mapper.WritePrgROM(0xFFFF, 0x10)
+	assert.Equal(t, OneScreenUpperMirroring, mapper.Mirroring())
+
+	mapper.WritePrgROM(0xFFFF, 0x00)
+	assert.Equal(t, OneScreenMirroring, mapper.Mirroring())
+}
+
+func TestMapper007_has_bus_conflicts(t *testing.T) {
+	mapper := CreateMapper007ForTest()
+
+	mapper.WritePrgROM(0x8000, 0x13) // ROM has 0x00 at 0x8000
+
+	assert.Equal(t, byte(0), mapper.ReadPrgROM(0x8000))
+	assert.Equal(t, OneScreenMirroring, mapper.Mirroring())
+}
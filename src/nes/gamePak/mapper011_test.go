@@ -0,0 +1,34 @@
+package gamePak
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func CreateMapper011ForTest() Mapper {
+	prgROM := bankedROM(4*0x8000, 0x8000)
+	for bank := 0; bank < 4; bank++ {
+		prgROM[bank*0x8000+0x7FFF] = 0xFF
+	}
+	header := CreateINes1Header(8, 16, 0xB0, 0, 0, 0, 0)
+
+	return CreateMapper(header, prgROM, bankedROM(16*0x2000, 0x2000))
+}
+
+func TestMapper011_switches_prg_and_chr_banks(t *testing.T) {
+	mapper := CreateMapper011ForTest()
+
+	mapper.WritePrgROM(0xFFFF, 0xA2)
+
+	assert.Equal(t, byte(2), mapper.ReadPrgROM(0x8000))
+	assert.Equal(t, byte(10), mapper.ReadChrROM(0x0000))
+}
+
+func TestMapper011_has_bus_conflicts(t *testing.T) {
+	mapper := CreateMapper011ForTest()
+
+	mapper.WritePrgROM(0x8000, 0xA2) // ROM has 0x00 at 0x8000
+
+	assert.Equal(t, byte(0), mapper.ReadPrgROM(0x8000))
+	assert.Equal(t, byte(0), mapper.ReadChrROM(0x0000))
+}
@@ -0,0 +1,122 @@
+package gamePak
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/savestate"
+	"github.com/raulferras/nes-golang/src/nes/types"
+)
+
+// Mapper034 BNROM and NINA-001
+// Both boards share the mapper number. NINA-001 is told apart by having more than 8KB of CHR ROM.
+//
+// BNROM
+//
+//	0x8000 -> 0xFFFF: 32KB switchable PRG ROM bank, selected writing to 0x8000 -> 0xFFFF.
+//	Boards have bus conflicts. CHR is 8KB of RAM.
+//
+// NINA-001
+//
+//	0x6000 -> 0x7FFF: PRG RAM, provided by the GamePak. Registers below also write into it
+//	0x7FFD: Select 32KB PRG ROM bank for CPU $8000-$FFFF
+//	0x7FFE: Select 4KB CHR ROM bank for PPU $0000-$0FFF
+//	0x7FFF: Select 4KB CHR ROM bank for PPU $1000-$1FFF
+type Mapper034 struct {
+	prgROMBanks byte
+	chrROMBanks byte
+	prgROM      []byte
+	chrROM      []byte
+	hasCHRRAM   bool
+	mirroring   byte
+	nina001     bool
+
+	prgBank  byte
+	chrBank0 byte
+	chrBank1 byte
+}
+
+func CreateMapper034(header Header, prgROM []byte, chrROM []byte) *Mapper034 {
+	return &Mapper034{
+		prgROMBanks: header.ProgramSize(),
+		chrROMBanks: header.CHRSize(),
+		prgROM:      prgROM,
+		chrROM:      chrMemory(header, chrROM),
+		hasCHRRAM:   header.CHRSize() == 0,
+		mirroring:   header.Mirroring(),
+		nina001:     header.CHRSize() > 1,
+		chrBank1:    1,
+	}
+}
+
+func (mapper *Mapper034) PrgBanks() byte {
+	return mapper.prgROMBanks
+}
+
+func (mapper *Mapper034) ChrBanks() byte {
+	return mapper.chrROMBanks
+}
+
+func (mapper *Mapper034) Mirroring() byte {
+	return mapper.mirroring
+}
+
+func (mapper *Mapper034) ReadPrgROM(address types.Address) byte {
+	if !satisfiableAddress(address) {
+		return 0
+	}
+
+	bankCount := len(mapper.prgROM) / 0x8000
+	bank := int(mapper.prgBank) % bankCount
+
+	return mapper.prgROM[bank*0x8000+int(address&0x7FFF)]
+}
+
+func (mapper *Mapper034) WritePrgROM(address types.Address, value byte) {
+	if mapper.nina001 {
+		switch address {
+		case 0x7FFD:
+			mapper.prgBank = value & 0x01
+		case 0x7FFE:
+			mapper.chrBank0 = value & 0x0F
+		case 0x7FFF:
+			mapper.chrBank1 = value & 0x0F
+		}
+		return
+	}
+
+	if !satisfiableAddress(address) {
+		return
+	}
+
+	mapper.prgBank = busConflict(mapper, address, value)
+}
+
+func (mapper *Mapper034) ReadChrROM(address types.Address) byte {
+	return mapper.chrROM[mapper.chrROMOffset(address)]
+}
+
+func (mapper *Mapper034) WriteChrROM(address types.Address, value byte) {
+	if mapper.hasCHRRAM {
+		mapper.chrROM[mapper.chrROMOffset(address)] = value
+	}
+}
+
+func (mapper *Mapper034) chrROMOffset(address types.Address) int {
+	if !mapper.nina001 {
+		return int(address & 0x1FFF)
+	}
+
+	bankCount := len(mapper.chrROM) / 0x1000
+	bank := mapper.chrBank0
+	if address >= 0x1000 {
+		bank = mapper.chrBank1
+	}
+
+	return (int(bank)%bankCount)*0x1000 + int(address&0x0FFF)
+}
+
+// SyncState saves or loads bank registers, and CHR RAM when the board has it
+func (mapper *Mapper034) SyncState(state *savestate.Stream) {
+	state.Sync(&mapper.prgBank, &mapper.chrBank0, &mapper.chrBank1)
+	if mapper.hasCHRRAM {
+		state.SyncMemory(mapper.chrROM)
+	}
+}
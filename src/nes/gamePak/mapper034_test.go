@@ -0,0 +1,53 @@
+package gamePak
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMapper034_BNROM_switches_32KB_prg_bank(t *testing.T) {
+	prgROM := fillROM(make([]byte, 4*0x8000), 0xFF)
+	for bank := 0; bank < 4; bank++ {
+		prgROM[bank*0x8000] = byte(bank)
+	}
+	header := CreateINes1Header(8, 0, 0x20, 0x20, 0, 0, 0)
+	mapper := CreateMapper(header, prgROM, []byte{})
+
+	mapper.WritePrgROM(0x9000, 3)
+
+	assert.Equal(t, byte(3), mapper.ReadPrgROM(0x8000))
+}
+
+func TestMapper034_BNROM_has_bus_conflicts(t *testing.T) {
+	header := CreateINes1Header(8, 0, 0x20, 0x20, 0, 0, 0)
+	mapper := CreateMapper(header, bankedROM(4*0x8000, 0x8000), []byte{})
+
+	mapper.WritePrgROM(0x8000, 3) // ROM has 0x00 at 0x8000
+
+	assert.Equal(t, byte(0), mapper.ReadPrgROM(0x8000))
+}
+
+func TestMapper034_NINA001_switches_banks_through_registers_in_prg_ram_range(t *testing.T) {
+	header := CreateINes1Header(4, 2, 0x20, 0x20, 0, 0, 0)
+	mapper := CreateMapper(header, bankedROM(2*0x8000, 0x8000), bankedROM(2*0x2000, 0x1000))
+
+	mapper.WritePrgROM(0x7FFD, 1)
+	mapper.WritePrgROM(0x7FFE, 3)
+	mapper.WritePrgROM(0x7FFF, 2)
+
+	assert.Equal(t, byte(1), mapper.ReadPrgROM(0x8000))
+	assert.Equal(t, byte(3), mapper.ReadChrROM(0x0000))
+	assert.Equal(t, byte(2), mapper.ReadChrROM(0x1000))
+}
+
+func TestMapper034_NINA001_registers_are_also_written_into_prg_ram(t *testing.T) {
+	header := CreateINes1Header(4, 2, 0x20, 0x20, 0, 0, 0)
+	cartridge := CreateGamePak(header, bankedROM(2*0x8000, 0x8000), bankedROM(2*0x2000, 0x1000))
+
+	cartridge.WritePrgROM(0x6000, 0x42)
+	cartridge.WritePrgROM(0x7FFD, 0x01)
+
+	assert.Equal(t, byte(0x42), cartridge.ReadPrgROM(0x6000))
+	assert.Equal(t, byte(0x01), cartridge.ReadPrgROM(0x7FFD))
+	assert.Equal(t, byte(1), cartridge.ReadPrgROM(0x8000))
+}
@@ -0,0 +1,104 @@
+package gamePak
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/savestate"
+	"github.com/raulferras/nes-golang/src/nes/types"
+)
+
+// Mapper066 GxROM
+//
+//	CPU Address Bus
+//	0x8000 -> 0xFFFF: 32KB switchable PRG ROM bank
+//
+//	PPU Address Bus
+//	0x0000 -> 0x1FFF: 8KB switchable CHR ROM bank
+//
+// Writing to 0x8000 -> 0xFFFF:
+//
+//	7  bit  0
+//	---- ----
+//	xxPP xxCC
+//	  ||   ||
+//	  ||   ++- Select 8 KB CHR ROM bank for PPU $0000-$1FFF
+//	  ++------ Select 32 KB PRG ROM bank for CPU $8000-$FFFF
+//
+// Boards have bus conflicts.
+type Mapper066 struct {
+	prgROMBanks byte
+	chrROMBanks byte
+	prgROM      []byte
+	chrROM      []byte
+	hasCHRRAM   bool
+	mirroring   byte
+
+	prgBank byte
+	chrBank byte
+}
+
+func CreateMapper066(header Header, prgROM []byte, chrROM []byte) *Mapper066 {
+	return &Mapper066{
+		prgROMBanks: header.ProgramSize(),
+		chrROMBanks: header.CHRSize(),
+		prgROM:      prgROM,
+		chrROM:      chrMemory(header, chrROM),
+		hasCHRRAM:   header.CHRSize() == 0,
+		mirroring:   header.Mirroring(),
+	}
+}
+
+func (mapper *Mapper066) PrgBanks() byte {
+	return mapper.prgROMBanks
+}
+
+func (mapper *Mapper066) ChrBanks() byte {
+	return mapper.chrROMBanks
+}
+
+func (mapper *Mapper066) Mirroring() byte {
+	return mapper.mirroring
+}
+
+func (mapper *Mapper066) ReadPrgROM(address types.Address) byte {
+	if !satisfiableAddress(address) {
+		return 0
+	}
+
+	bankCount := len(mapper.prgROM) / 0x8000
+	bank := int(mapper.prgBank) % bankCount
+
+	return mapper.prgROM[bank*0x8000+int(address&0x7FFF)]
+}
+
+func (mapper *Mapper066) WritePrgROM(address types.Address, value byte) {
+	if !satisfiableAddress(address) {
+		return
+	}
+
+	value = busConflict(mapper, address, value)
+	mapper.prgBank = (value >> 4) & 0x03
+	mapper.chrBank = value & 0x03
+}
+
+func (mapper *Mapper066) ReadChrROM(address types.Address) byte {
+	return mapper.chrROM[mapper.chrROMOffset(address)]
+}
+
+func (mapper *Mapper066) WriteChrROM(address types.Address, value byte) {
+	if mapper.hasCHRRAM {
+		mapper.chrROM[mapper.chrROMOffset(address)] = value
+	}
+}
+
+func (mapper *Mapper066) chrROMOffset(address types.Address) int {
+	bankCount := len(mapper.chrROM) / 0x2000
+
+	return (int(mapper.chrBank)%bankCount)*0x2000 + int(address&0x1FFF)
+}
+
+// SyncState saves or loads bank registers, and CHR RAM when the board has it
+func (mapper *Mapper066) SyncState(state *savestate.Stream) {
+	state.Sync(&mapper.prgBank, &mapper.chrBank)
+	if mapper.hasCHRRAM {
+		state.SyncMemory(mapper.chrROM)
+	}
+}
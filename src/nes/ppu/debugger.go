@@ -2,6 +2,8 @@ package ppu
 
 import (
 	"image"
+	"image/color"
+	"math/bits"
 )
 
 func (ppu *P2c02) PatternTable(patternTable byte, palette byte) image.RGBA {
@@ -44,3 +46,43 @@ func (ppu *P2c02) PatternTable(patternTable byte, palette byte) image.RGBA {
 	//saveTile(300, chr)
 	return *chr
 }
+
+// Sprite renders the sprite found at the given OAM slot (0-63), using its palette and flipping.
+// Image is 8x8 or 8x16 pixels depending on PPUCTRL sprite size. Transparent pixels have alpha 0.
+func (ppu *P2c02) Sprite(index byte) image.RGBA {
+	object := objectAttributeEntry{
+		y:          ppu.oamData[index*4],
+		tileId:     ppu.oamData[index*4+1],
+		attributes: ppu.oamData[index*4+2],
+		x:          ppu.oamData[index*4+3],
+	}
+	height := ppu.spriteHeight()
+	sprite := image.NewRGBA(image.Rect(0, 0, TILE_WIDTH, int(height)))
+
+	for y := byte(0); y < height; y++ {
+		row := y
+		if object.isFlippedVertically() {
+			row = height - 1 - y
+		}
+		address := ppu.spritePatternAddress(object.tileId, row)
+		lower := ppu.Peek(address)
+		upper := ppu.Peek(address + 8)
+		if object.isFlippedHorizontally() {
+			lower = bits.Reverse8(lower)
+			upper = bits.Reverse8(upper)
+		}
+
+		for x := 0; x < TILE_WIDTH; x++ {
+			value := (upper>>7)<<1 | lower>>7
+			if value != 0 {
+				sprite.Set(x, int(y), ppu.GetRGBColor(object.palette(), value))
+			} else {
+				sprite.Set(x, int(y), color.RGBA{})
+			}
+			upper <<= 1
+			lower <<= 1
+		}
+	}
+
+	return *sprite
+}
@@ -8,62 +8,102 @@ import (
 
 // Read made by CPU
 func (ppu *P2c02) ReadRegister(register types.Address) byte {
-	value := byte(0x00)
-
 	switch register {
-	case PPUCTRL:
-		panic("trying to read PPUCTRL")
-
-	case PPUMASK:
-		//panic("trying to read PPMASK")
-		value = 0
-		break
-
 	case PPUSTATUS:
-		// Source: javid9x reading from status only get top 3 bits. The rest tends to be filled with noise, or more likely what was last in data buffer.
-		value = ppu.PpuStatus.Value()
+		// Only top 3 bits are driven, the rest come from the I/O latch
+		value := ppu.PpuStatus.Value()&0xE0 | ppu.readIOLatch()&0x1F
+		ppu.setIOLatch(value, 0xE0)
 
 		// Reading from status register alters it
 		ppu.PpuStatus.VerticalBlankStarted = false // Reading from status, clears VBlank flag.
-		//ppu.registers.status &= 0x7F
+		if ppu.currentScanline == ppu.timing.VBlankScanline && ppu.renderCycle == 1 {
+			// VBlank flag is set on next PPU cycle. Reading now reads it clear, and it never gets set this frame.
+			ppu.vblankSuppressed = true
+		}
 		ppu.tRam.resetLatch()
-		break
 
-	case OAMADDR:
-		break
+		return value
 
 	case OAMDATA:
-		value = ppu.oamData[ppu.oamAddr]
-		break
-
-	case PPUSCROLL:
-		break
+		value := ppu.oamData[ppu.oamAddr]
+		if ppu.oamAddr&0x03 == 2 {
+			// Attribute bits 2-4 are not implemented, they read as 0
+			value &= 0xE3
+		}
+		ppu.setIOLatch(value, 0xFF)
 
-	case PPUADDR:
-		break
+		return value
 
 	case PPUDATA:
-		// TODO test delay and not delay from palette
-		value = ppu.readBuffer
-		ppu.readBuffer = ppu.Read(ppu.vRam.address())
+		return ppu.readPPUData()
+	}
 
-		// If reading from Palette, there is no delay
-		if isPaletteAddress(ppu.vRam.address()) {
-			value = ppu.readBuffer
-		}
+	// Write only registers: PPUCTRL, PPUMASK, OAMADDR, PPUSCROLL and PPUADDR
+	return ppu.readIOLatch()
+}
 
-		ppu.vRam.increment(ppu.PpuControl.IncrementMode)
-		break
+func (ppu *P2c02) readPPUData() byte {
+	address := ppu.vRam.address()
 
-	case OAMDMA:
-		break
+	var value byte
+	if isPaletteAddress(address) {
+		// Reading from Palette, there is no delay. Only the 6 low bits are driven.
+		// The buffer is filled with the nametable byte "below" the palette.
+		value = ppu.Read(address)&ppu.PpuMask.colorMask() | ppu.readIOLatch()&0xC0
+		ppu.readBuffer = ppu.Read(address - 0x1000)
+		ppu.setIOLatch(value, 0x3F)
+	} else {
+		value = ppu.readBuffer
+		ppu.readBuffer = ppu.Read(address)
+		ppu.setIOLatch(value, 0xFF)
 	}
 
+	ppu.incrementVRamAfterAccess()
+
 	return value
 }
 
+// incrementVRamAfterAccess moves v after a PPUDATA read or write.
+// During rendering, v is being used to fetch tiles, and the access glitches
+// into a coarse X and a Y increment at the same time.
+func (ppu *P2c02) incrementVRamAfterAccess() {
+	if ppu.PpuMask.renderingEnabled() && ppu.scanlineIsVisibleOrIsPreRender() {
+		ppu.incrementX()
+		ppu.incrementY()
+		return
+	}
+
+	ppu.vRam.increment(ppu.PpuControl.IncrementMode)
+}
+
+// The I/O latch holds the last value written to or read from any PPU register.
+// Reading write only registers returns it, and so do undriven bits of readable ones.
+// Each bit decays to 0 when it is not refreshed for about 600ms.
+const ioLatchDecayCycles = 3221590
+
+func (ppu *P2c02) setIOLatch(value byte, driven byte) {
+	ppu.ioLatch = ppu.ioLatch&^driven | value&driven
+	for bit := uint(0); bit < 8; bit++ {
+		if driven&(1<<bit) != 0 {
+			ppu.ioLatchRefreshed[bit] = ppu.clock
+		}
+	}
+}
+
+func (ppu *P2c02) readIOLatch() byte {
+	for bit := uint(0); bit < 8; bit++ {
+		if ppu.clock-ppu.ioLatchRefreshed[bit] > ioLatchDecayCycles {
+			ppu.ioLatch &^= 1 << bit
+		}
+	}
+
+	return ppu.ioLatch
+}
+
 // Write made by CPU
 func (ppu *P2c02) WriteRegister(register types.Address, value byte) {
+	ppu.setIOLatch(value, 0xFF)
+
 	if !ppu.warmup {
 		//log.Printf("Ignoring write register: %40X: %0X\n", register, value)
 		return
@@ -74,7 +114,7 @@ func (ppu *P2c02) WriteRegister(register types.Address, value byte) {
 		ppu.ppuCtrlWrite(value)
 		ppu.tRam.setNameTableX(ppu.PpuControl.NameTableX)
 		ppu.tRam.setNameTableY(ppu.PpuControl.NameTableY)
-		// todo trigger nmi if in vblank and generateNMI transitions from 0 to 1
+		// Enabling NMI while in VBlank asserts the NMI line, see NMIAsserted
 		break
 
 	case PPUMASK:
@@ -82,8 +122,8 @@ func (ppu *P2c02) WriteRegister(register types.Address, value byte) {
 		break
 
 	case PPUSTATUS:
-		// READONLY!
-		panic("tried to write @PPUSTATUS")
+		// Read only, writing only fills the I/O latch
+		break
 
 	case OAMADDR:
 		ppu.oamAddr = value
@@ -110,12 +150,14 @@ func (ppu *P2c02) WriteRegister(register types.Address, value byte) {
 		ppu.tRam.push(value)
 		if ppu.tRam.latch == 0 {
 			ppu.vRam = ppu.tRam
+			// v is driven into the address bus, mappers watching A12 can notice it.
+			ppu.cartridge.OnPPUAddress(ppu.vRam.address(), ppu.clock)
 		}
 		break
 	case PPUDATA:
 		address := ppu.vRam.address()
 		ppu.Write(address, value)
-		ppu.vRam.increment(ppu.PpuControl.IncrementMode)
+		ppu.incrementVRamAfterAccess()
 		break
 	case OAMDMA:
 		//fmt.Println("OAMDMA!")
@@ -153,14 +195,16 @@ func (ppu *P2c02) Read(address types.Address) byte {
 func (ppu *P2c02) read(address types.Address, readOnly bool) byte {
 	result := byte(0x00)
 
+	if !readOnly && !isPaletteAddress(address) {
+		ppu.cartridge.OnPPUAddress(address, ppu.clock)
+	}
+
 	// CHR ROM address
 	if isCHRAddress(address) {
 		result = ppu.cartridge.ReadCHRROM(address)
 	} else if isNameTableAddress(address) {
 		// Nametable 0, 1, 2, 3
-		mirroring := ppu.cartridge.Header().Mirroring()
-		nameTableAddress := getNameTableAddress(mirroring, address)
-		result = ppu.nameTables[nameTableAddress]
+		result = ppu.readNameTable(address)
 	} else if isPaletteAddress(address) {
 		result = ppu.readPalette(address)
 	}
@@ -169,14 +213,12 @@ func (ppu *P2c02) read(address types.Address, readOnly bool) byte {
 }
 
 func (ppu *P2c02) Write(address types.Address, value byte) {
-	if isNameTableAddress(address) {
-		nameTableAddress := getNameTableAddress(ppu.cartridge.Header().Mirroring(), address)
-		if ppu.nameTables[nameTableAddress] != value {
-			ppu.nameTableChanged = true
-		}
-
-		ppu.nameTables[nameTableAddress] = value
+	if !isPaletteAddress(address) {
+		ppu.cartridge.OnPPUAddress(address, ppu.clock)
+	}
 
+	if isNameTableAddress(address) {
+		ppu.writeNameTable(address, value)
 	} else if address == 0x4010 {
 		// OAM DMA: Transfers 256 bytes of data from CPU page $XX00-$XXFF to internal PPU OAM
 		// DMA will begin at current OAM write address.
@@ -243,6 +285,41 @@ func (ppu *P2c02) writePalette(address types.Address, colorIndex byte) {
 	ppu.paletteTable[address] = colorIndex
 }
 
+// readNameTable reads from cartridge memory when it replaces console VRAM (CIRAM) for address,
+// from CIRAM otherwise.
+func (ppu *P2c02) readNameTable(address types.Address) byte {
+	if value, ok := ppu.cartridge.ReadNameTable(address); ok {
+		return value
+	}
+
+	return ppu.nameTables[ppu.ciramAddress(address)]
+}
+
+func (ppu *P2c02) writeNameTable(address types.Address, value byte) {
+	if ppu.cartridge.WriteNameTable(address, value) {
+		ppu.nameTableChanged = true
+		return
+	}
+
+	ciramAddress := ppu.ciramAddress(address)
+	if ppu.nameTables[ciramAddress] != value {
+		ppu.nameTableChanged = true
+	}
+
+	ppu.nameTables[ciramAddress] = value
+}
+
+// ciramAddress maps a nametable address into console VRAM, following the mirroring currently selected by the cartridge
+func (ppu *P2c02) ciramAddress(address types.Address) types.Address {
+	mirroring := ppu.cartridge.Mirroring()
+	if mirroring == gamePak.MapperMirroring {
+		page := ppu.cartridge.CIRAMPage(byte(address>>10) & 0x03)
+		return types.Address(page)*NAMETABLE_SIZE | address&0x3FF
+	}
+
+	return getNameTableAddress(mirroring, address)
+}
+
 func getNameTableAddress(mirrorMode byte, address types.Address) types.Address {
 	realAddress := address
 	// $2000-$23FF 	$0400 	Nametable 0
@@ -312,6 +389,8 @@ func getNameTableAddress(mirrorMode byte, address types.Address) types.Address {
 		// Know the row, we subtract x -1 to the row and multiply by 0x400
 	} else if mirrorMode == gamePak.OneScreenMirroring {
 		realAddress = (address) & 0x3FF
+	} else if mirrorMode == gamePak.OneScreenUpperMirroring {
+		realAddress = 0x400 | (address & 0x3FF)
 	}
 
 	return realAddress % 2048
@@ -43,7 +43,7 @@ func (register *Mask) write(value byte) {
 		register.EmphasizeGreen = 1
 	}
 	if (value>>7)&0x01 == 1 {
-		register.EmphasizeGreen = 1
+		register.EmphasizeBlue = 1
 	}
 }
 
@@ -72,3 +72,18 @@ func (register *Mask) showSpritesEnabled() bool {
 func (register *Mask) renderingEnabled() bool {
 	return register.showBackgroundEnabled() || register.showSpritesEnabled()
 }
+
+// colorMask is applied to palette indexes sent to the screen.
+// Greyscale keeps the luminance bits only, so every color falls in the gray column.
+func (register *Mask) colorMask() byte {
+	if register.GreyScale == 1 {
+		return 0x30
+	}
+
+	return 0x3F
+}
+
+// emphasis returns the emphasis bits: red, green and blue from lowest to highest
+func (register *Mask) emphasis() byte {
+	return register.EmphasizeRed | register.EmphasizeGreen<<1 | register.EmphasizeBlue<<2
+}
@@ -94,7 +94,22 @@ func TestPPU_read_nameTables_with_mirroring(t *testing.T) {
 		{
 			name:       "One screen mirroring",
 			mirrorMode: gamePak.OneScreenMirroring,
-			assertions: []mirrorAssertion{},
+			assertions: []mirrorAssertion{
+				{0x2000, 0x000},
+				{0x2400, 0x000},
+				{0x2BFF, 0x3FF},
+				{0x2FFF, 0x3FF},
+			},
+		},
+		{
+			name:       "One screen upper bank mirroring",
+			mirrorMode: gamePak.OneScreenUpperMirroring,
+			assertions: []mirrorAssertion{
+				{0x2000, 0x400},
+				{0x2400, 0x400},
+				{0x2BFF, 0x7FF},
+				{0x2FFF, 0x7FF},
+			},
 		},
 	}
 
@@ -159,7 +174,7 @@ func TestPPUMemory_write_nametables(t *testing.T) {
 				mirrorMode = 1
 			}
 			header := gamePak.CreateINes1Header(10, 10, mirrorMode, 0, 0, 0, 0)
-			pak := gamePak.CreateGamePak(header, []byte{0}, []byte{0})
+			pak := gamePak.CreateGamePak(header, make([]byte, 0x4000), []byte{0})
 			ppu := CreatePPU(&pak, false, "")
 
 			ppu.Write(tt.mirrorA.address, 0xFF)
@@ -174,3 +189,21 @@ func TestPPUMemory_write_nametables(t *testing.T) {
 		})
 	}
 }
+
+func TestPPUMemory_four_screen_nametables_are_not_mirrored(t *testing.T) {
+	header := gamePak.CreateINes1Header(1, 1, 0b1000, 0, 0, 0, 0)
+	pak := gamePak.CreateGamePak(header, make([]byte, 0x4000), make([]byte, 0x2000))
+	ppu := CreatePPU(&pak, false, "")
+
+	ppu.Write(0x2000, 0x01)
+	ppu.Write(0x2400, 0x02)
+	ppu.Write(0x2800, 0x03)
+	ppu.Write(0x2C00, 0x04)
+
+	assert.Equal(t, byte(0x01), ppu.Read(0x2000))
+	assert.Equal(t, byte(0x02), ppu.Read(0x2400))
+	assert.Equal(t, byte(0x03), ppu.Read(0x2800))
+	assert.Equal(t, byte(0x04), ppu.Read(0x2C00))
+	assert.Equal(t, byte(0x04), ppu.Read(0x3C00), "0x3000 -> 0x3EFF should mirror nametables")
+	assert.Equal(t, [2 * NAMETABLE_SIZE]byte{}, ppu.nameTables, "console VRAM should not be used")
+}
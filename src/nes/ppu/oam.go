@@ -23,6 +23,10 @@ func (oae *objectAttributeEntry) isFlippedHorizontally() bool {
 	return oae.attributes&0x40 == 0x40
 }
 
+func (oae *objectAttributeEntry) isBehindBackground() bool {
+	return oae.attributes&0x20 == 0x20
+}
+
 func (oae *objectAttributeEntry) palette() byte {
 	// Sprite palette always goes from index 4 up to 7.
 	// but to save bytes, only three bits are used.
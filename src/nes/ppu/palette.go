@@ -1,5 +1,7 @@
 package ppu
 
+import "image/color"
+
 var SystemPalette = [NES_PALETTE_COLORS][3]byte{
 	{84, 84, 84},
 	{0, 30, 116},
@@ -72,3 +74,58 @@ var SystemPalette = [NES_PALETTE_COLORS][3]byte{
 	{0, 0, 0},
 	{0, 0, 0},
 }
+
+// Palette holds the RGB color of each of the 64 NES colors, for each of the 8 PPUMASK emphasis combinations.
+// Index is emphasis << 6 | color, where emphasis bits are red, green and blue from lowest to highest, like in PPUMASK.
+type Palette [NES_PALETTE_COLORS * 8]color.RGBA
+
+// NewPalette builds a palette from 64 colors, deriving emphasized colors from them
+func NewPalette(colors [NES_PALETTE_COLORS][3]byte) Palette {
+	var palette Palette
+	for emphasis := byte(0); emphasis < 8; emphasis++ {
+		for index, rgb := range colors {
+			palette[int(emphasis)<<6|index] = emphasize(
+				color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 255},
+				emphasis,
+			)
+		}
+	}
+
+	return palette
+}
+
+// Color returns the RGB color of a NES color with the given PPUMASK emphasis bits
+func (palette *Palette) Color(colorIndex byte, emphasis byte) color.RGBA {
+	return palette[int(emphasis&0x07)<<6|int(colorIndex&0x3F)]
+}
+
+// Emphasis darkens the channels not being emphasized. Emphasizing all three darkens the whole picture.
+// Attenuation factor commonly used by emulators. More info: https://www.nesdev.org/wiki/Colour_emphasis
+const emphasisAttenuation = 0.816328
+
+func emphasize(rgb color.RGBA, emphasis byte) color.RGBA {
+	if emphasis == 0 {
+		return rgb
+	}
+	if emphasis == 0x07 {
+		rgb.R = attenuate(rgb.R)
+		rgb.G = attenuate(rgb.G)
+		rgb.B = attenuate(rgb.B)
+		return rgb
+	}
+	if emphasis&0x01 == 0 {
+		rgb.R = attenuate(rgb.R)
+	}
+	if emphasis&0x02 == 0 {
+		rgb.G = attenuate(rgb.G)
+	}
+	if emphasis&0x04 == 0 {
+		rgb.B = attenuate(rgb.B)
+	}
+
+	return rgb
+}
+
+func attenuate(channel byte) byte {
+	return byte(float64(channel) * emphasisAttenuation)
+}
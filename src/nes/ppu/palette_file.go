@@ -0,0 +1,50 @@
+package ppu
+
+import (
+	"fmt"
+	"image/color"
+	"io/ioutil"
+)
+
+// .pal files are raw RGB triplets, either for the 64 colors, or for the 64 colors on each of the 8 emphasis combinations
+const paletteFileSize = NES_PALETTE_COLORS * 3
+const emphasisPaletteFileSize = NES_PALETTE_COLORS * 8 * 3
+
+// PaletteSizeError is returned when a .pal file is neither 192 nor 1536 bytes long
+type PaletteSizeError struct {
+	Size int
+}
+
+func (err PaletteSizeError) Error() string {
+	return fmt.Sprintf("palette should be %d or %d bytes, found %d", paletteFileSize, emphasisPaletteFileSize, err.Size)
+}
+
+func LoadPaletteFile(path string) (Palette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Palette{}, err
+	}
+
+	return ParsePalette(data)
+}
+
+// ParsePalette reads a palette in .pal format.
+// 192 byte palettes have emphasized colors derived from the 64 base colors.
+func ParsePalette(data []byte) (Palette, error) {
+	switch len(data) {
+	case paletteFileSize:
+		var colors [NES_PALETTE_COLORS][3]byte
+		for i := range colors {
+			copy(colors[i][:], data[i*3:i*3+3])
+		}
+		return NewPalette(colors), nil
+	case emphasisPaletteFileSize:
+		var palette Palette
+		for i := range palette {
+			palette[i] = color.RGBA{R: data[i*3], G: data[i*3+1], B: data[i*3+2], A: 255}
+		}
+		return palette, nil
+	}
+
+	return Palette{}, PaletteSizeError{Size: len(data)}
+}
@@ -0,0 +1,114 @@
+package ppu
+
+import (
+	"image/color"
+	"math"
+)
+
+// NTSCParameters tune how the NTSC signal generated by the PPU is decoded into RGB
+type NTSCParameters struct {
+	Hue        float64 // Degrees
+	Saturation float64
+	Contrast   float64
+	Brightness float64
+	Gamma      float64
+}
+
+var DefaultNTSCParameters = NTSCParameters{
+	Hue:        0,
+	Saturation: 1.2,
+	Contrast:   1,
+	Brightness: 0,
+	Gamma:      2.2,
+}
+
+// Signal voltages of each luminance level, as low and high values of the square wave.
+// More info: https://www.nesdev.org/wiki/NTSC_video
+var ntscLowLevels = [4]float64{0.350, 0.518, 0.962, 1.550}
+var ntscHighLevels = [4]float64{1.094, 1.506, 1.962, 1.962}
+
+const ntscBlack = 0.518
+const ntscWhite = 1.962
+
+// Emphasized channels are attenuated while the signal is in their phase
+const ntscEmphasisAttenuation = 0.746
+
+// Color 1 phase is 120 degrees away from color burst
+const ntscHueOffset = 120
+
+// GenerateNTSCPalette builds a palette decoding the signal the PPU outputs for each color and emphasis.
+// The signal is a square wave with 12 phases. Averaging it along a full period gives the
+// luminance (Y) and chrominance (I, Q) components, which are then converted to RGB.
+func GenerateNTSCPalette(params NTSCParameters) Palette {
+	var palette Palette
+	for index := range palette {
+		palette[index] = decodeNTSCColor(index, params)
+	}
+
+	return palette
+}
+
+func decodeNTSCColor(index int, params NTSCParameters) color.RGBA {
+	y, i, q := 0.0, 0.0, 0.0
+	for phase := 0; phase < 12; phase++ {
+		signal := (ntscSignal(index, phase) - ntscBlack) / (ntscWhite - ntscBlack)
+		angle := math.Pi*float64(phase)/6 + (params.Hue+ntscHueOffset)*math.Pi/180
+		y += signal
+		i += signal * math.Cos(angle)
+		q += signal * math.Sin(angle)
+	}
+	y = y/12*params.Contrast + params.Brightness
+	i = i / 12 * params.Contrast * params.Saturation
+	q = q / 12 * params.Contrast * params.Saturation
+
+	return color.RGBA{
+		R: gammaCorrect(y+0.946882*i+0.623557*q, params.Gamma),
+		G: gammaCorrect(y-0.274788*i-0.635691*q, params.Gamma),
+		B: gammaCorrect(y-1.108545*i+1.709007*q, params.Gamma),
+		A: 255,
+	}
+}
+
+// ntscSignal returns the voltage generated for a palette index at one of the 12 phases of the color subcarrier
+func ntscSignal(index int, phase int) float64 {
+	hue := index & 0x0F
+	level := (index >> 4) & 0x03
+	emphasis := index >> 6
+
+	// Colors $xE and $xF are black
+	if hue > 13 {
+		level = 1
+	}
+	low := ntscLowLevels[level]
+	high := ntscHighLevels[level]
+	if hue == 0 {
+		low = high
+	}
+	if hue > 12 {
+		high = low
+	}
+
+	inPhase := func(hue int) bool {
+		return (hue+phase)%12 < 6
+	}
+
+	signal := low
+	if inPhase(hue) {
+		signal = high
+	}
+
+	// Red, green and blue emphasis attenuate the signal at phases of colors 0, 4 and 8
+	if (emphasis&0x01 == 0x01 && inPhase(0)) ||
+		(emphasis&0x02 == 0x02 && inPhase(4)) ||
+		(emphasis&0x04 == 0x04 && inPhase(8)) {
+		signal *= ntscEmphasisAttenuation
+	}
+
+	return signal
+}
+
+func gammaCorrect(value float64, gamma float64) byte {
+	value = math.Max(0, math.Min(1, value))
+
+	return byte(math.Round(255 * math.Pow(value, 2.2/gamma)))
+}
@@ -0,0 +1,131 @@
+package ppu
+
+import (
+	"github.com/stretchr/testify/assert"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePalette_with_64_colors_derives_emphasis(t *testing.T) {
+	data := make([]byte, 192)
+	data[0x16*3] = 200
+	data[0x16*3+1] = 100
+	data[0x16*3+2] = 50
+
+	palette, err := ParsePalette(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGBA{R: 200, G: 100, B: 50, A: 255}, palette.Color(0x16, 0))
+	assert.Equal(t, color.RGBA{R: 200, G: 81, B: 40, A: 255}, palette.Color(0x16, 0b001))
+	assert.Equal(t, color.RGBA{R: 163, G: 81, B: 50, A: 255}, palette.Color(0x16, 0b100))
+	assert.Equal(t, color.RGBA{R: 163, G: 81, B: 40, A: 255}, palette.Color(0x16, 0b111), "all emphasis bits darken every channel")
+}
+
+func TestParsePalette_with_512_colors(t *testing.T) {
+	data := make([]byte, 1536)
+	for i := range data {
+		data[i] = byte(i / 3)
+	}
+
+	palette, err := ParsePalette(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGBA{R: 0x16, G: 0x16, B: 0x16, A: 255}, palette.Color(0x16, 0))
+	assert.Equal(t, color.RGBA{R: 0x56, G: 0x56, B: 0x56, A: 255}, palette.Color(0x16, 1))
+	assert.Equal(t, color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 255}, palette.Color(0x3F, 7))
+}
+
+func TestParsePalette_rejects_unknown_sizes(t *testing.T) {
+	_, err := ParsePalette(make([]byte, 100))
+
+	assert.Equal(t, PaletteSizeError{Size: 100}, err)
+	assert.EqualError(t, err, "palette should be 192 or 1536 bytes, found 100")
+}
+
+func TestLoadPaletteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "palette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.pal")
+	data := make([]byte, 192)
+	data[0] = 10
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	palette, err := LoadPaletteFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGBA{R: 10, A: 255}, palette.Color(0, 0))
+
+	_, err = LoadPaletteFile(filepath.Join(dir, "missing.pal"))
+	assert.Error(t, err)
+}
+
+func TestGenerateNTSCPalette(t *testing.T) {
+	palette := GenerateNTSCPalette(DefaultNTSCParameters)
+
+	assert.Equal(t, color.RGBA{A: 255}, palette.Color(0x0F, 0), "$0F is black")
+	assert.Equal(t, color.RGBA{A: 255}, palette.Color(0x1D, 0), "$1D is black")
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, palette.Color(0x30, 0), "$30 is white")
+
+	grays := []byte{0x00, 0x10, 0x20}
+	for i := 1; i < len(grays); i++ {
+		darker := palette.Color(grays[i-1], 0)
+		lighter := palette.Color(grays[i], 0)
+		assert.Equal(t, darker.R, darker.G, "gray has no chroma")
+		assert.Equal(t, darker.R, darker.B, "gray has no chroma")
+		assert.Less(t, darker.R, lighter.R)
+	}
+
+	red := palette.Color(0x16, 0)
+	assert.Greater(t, red.R, red.G)
+	assert.Greater(t, red.R, red.B)
+	blue := palette.Color(0x12, 0)
+	assert.Greater(t, blue.B, blue.R)
+	assert.Greater(t, blue.B, blue.G)
+	green := palette.Color(0x1A, 0)
+	assert.Greater(t, green.G, green.R)
+	assert.Greater(t, green.G, green.B)
+}
+
+func TestGenerateNTSCPalette_emphasis_darkens_other_channels(t *testing.T) {
+	palette := GenerateNTSCPalette(DefaultNTSCParameters)
+
+	gray := palette.Color(0x10, 0)
+	redEmphasis := palette.Color(0x10, 0b001)
+	assert.Greater(t, redEmphasis.R, redEmphasis.G)
+	assert.Greater(t, redEmphasis.R, redEmphasis.B)
+	assert.Less(t, redEmphasis.G, gray.G)
+
+	allEmphasis := palette.Color(0x10, 0b111)
+	assert.Less(t, allEmphasis.R, gray.R)
+}
+
+func TestGenerateNTSCPalette_hue_rotates_colors(t *testing.T) {
+	params := DefaultNTSCParameters
+	params.Hue = 30 // One NES hue step
+
+	rotated := GenerateNTSCPalette(params)
+	palette := GenerateNTSCPalette(DefaultNTSCParameters)
+
+	assert.Equal(t, palette.Color(0x15, 0), rotated.Color(0x16, 0))
+}
+
+func TestGenerateNTSCPalette_zero_saturation_is_greyscale(t *testing.T) {
+	params := DefaultNTSCParameters
+	params.Saturation = 0
+
+	palette := GenerateNTSCPalette(params)
+
+	for index := byte(0); index < NES_PALETTE_COLORS; index++ {
+		rgb := palette.Color(index, 0)
+		assert.Equal(t, rgb.R, rgb.G, "color %02X", index)
+		assert.Equal(t, rgb.R, rgb.B, "color %02X", index)
+	}
+}
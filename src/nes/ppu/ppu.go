@@ -24,6 +24,9 @@ type P2c02 struct {
 	readBuffer byte
 	oamAddr    byte
 
+	ioLatch          byte      // Last value seen on the PPU data bus, see setIOLatch
+	ioLatchRefreshed [8]uint64 // PPU clock when each I/O latch bit was last refreshed
+
 	cartridge    *gamePak.GamePak
 	nameTables   [2 * NAMETABLE_SIZE]byte
 	paletteTable [PALETTE_SIZE]byte
@@ -42,26 +45,32 @@ type P2c02 struct {
 	bgShifterAttributeHigh uint16
 
 	// Sprite rendering
+	secondaryOAM         [32]byte // Sprites found for next scanline
+	spriteEvaluation     spriteEvaluation
 	oamDataScanline      [8]objectAttributeEntry
 	spriteScanlineCount  byte
+	spriteZeroInScanline bool // sprite 0 is in oamDataScanline[0]
 	spShifterPatternLow  [8]byte
 	spShifterPatternHigh [8]byte
 
 	cycle  uint32 // Current lifetime PPU Cycle. After warmup, ignored.
+	clock  uint64 // Lifetime PPU cycles. Lets the cartridge time events on the PPU bus.
 	warmup bool   // Indicates ppu is already warmed up (cycles went above 30000)
 
+	timing          Timing   // Frame layout of the console region
 	renderCycle     uint16   // Current cycle inside a Scanline. From 0 to PPU_CYCLES_BY_SCANLINE
 	currentScanline Scanline // Current vertical Scanline being rendered
 	evenFrame       bool     // Is current Frame even?
 	frame           uint16
 	frameComplete   bool
 
-	nmi              bool // NMI Interrupt thrown
+	vblankSuppressed bool // PPUSTATUS was read just before VBlank flag was going to be set
 	nameTableChanged bool
 
 	// Render related
 	renderByPixel   bool
 	screen          *image.RGBA
+	palette         Palette
 	framePatternIDs [1024]byte // Screen representation with pattern ids and its position in screen. For debugging purposes.
 	logger          *logger2c02
 	debug           bool
@@ -87,6 +96,8 @@ func CreatePPU(cartridge *gamePak.GamePak, debug bool, logPath string) *P2c02 {
 		renderByPixel: true,
 		evenFrame:     true,
 		screen:        image.NewRGBA(image.Rect(0, 0, types.SCREEN_WIDTH, types.SCREEN_HEIGHT)),
+		palette:       GenerateNTSCPalette(DefaultNTSCParameters),
+		timing:        NTSCTiming,
 
 		logger: nil,
 		debug:  debug,
@@ -133,19 +144,18 @@ func (ppu *P2c02) Tick() {
 	}
 
 	// VBlank logic
-	if ppu.currentScanline == VBLANK_START_SCANLINE {
+	if ppu.currentScanline == ppu.timing.VBlankScanline {
 		if ppu.renderCycle == 1 {
-			// TODO refactor to a method to set Vblank
-			// TODO enabling VBlank only on ==1 and not on >=1 makes it difficult to start emulation inside a VBlank cycle. If changed, nmi triggering should be worked though.
-			ppu.PpuStatus.VerticalBlankStarted = true
-
-			if ppu.PpuControl.GenerateNMIAtVBlank {
-				ppu.nmi = true
+			// Reading PPUSTATUS one cycle before prevents the flag, and its NMI, for the whole frame
+			if !ppu.vblankSuppressed {
+				ppu.PpuStatus.VerticalBlankStarted = true
 			}
+			ppu.vblankSuppressed = false
 		}
-	} else if ppu.currentScanline == VBLANK_END_SCNALINE && ppu.renderCycle == 1 {
+	} else if ppu.currentScanline == ppu.timing.preRenderScanline() && ppu.renderCycle == 1 {
 		ppu.PpuStatus.VerticalBlankStarted = false
 		ppu.PpuStatus.Sprite0Hit = 0
+		ppu.PpuStatus.SpriteOverflow = 0
 	}
 
 	// ------------------------------
@@ -163,7 +173,7 @@ func (ppu *P2c02) Tick() {
 
 	// 341 PPU clock cycles have passed
 	if ppu.renderCycle == PPU_CYCLES_BY_SCANLINE-1 {
-		if ppu.currentScanline == 261 {
+		if ppu.currentScanline == ppu.timing.preRenderScanline() {
 			ppu.evenFrame = !ppu.evenFrame
 			ppu.currentScanline = 0
 			ppu.frame++
@@ -180,6 +190,7 @@ func (ppu *P2c02) Tick() {
 		ppu.renderCycle++
 	}
 
+	ppu.clock++
 	if ppu.cycle >= PPU_CYCLES_TO_WARMUP {
 		ppu.warmup = true
 	} else {
@@ -188,7 +199,7 @@ func (ppu *P2c02) Tick() {
 }
 
 func (ppu *P2c02) shouldSkipFirstCycleOnOddFrame() bool {
-	return ppu.PpuMask.ShowBackground == 1 && ppu.scanlineIsVisibleOrIsPreRender() && ppu.evenFrame == false && ppu.currentScanline == 0 && ppu.renderCycle == 0
+	return ppu.timing.SkipOddFrameCycle && ppu.PpuMask.renderingEnabled() && ppu.evenFrame == false && ppu.currentScanline == 0 && ppu.renderCycle == 0
 }
 
 func (ppu *P2c02) incrementX() {
@@ -238,15 +249,11 @@ func (ppu *P2c02) transferY() {
 	}
 }
 
-func (ppu *P2c02) Nmi() bool {
-	occurred := ppu.nmi
-	ppu.nmi = false
-
-	return occurred
-}
-
-func (ppu *P2c02) ResetNmi() {
-	ppu.nmi = false
+// NMIAsserted tells if the PPU is pulling the NMI line: VBlank flag is set and NMI generation is enabled.
+// CPU triggers NMIs on the transition from not asserted to asserted, so enabling NMI
+// generation in the middle of a VBlank triggers one, and reading PPUSTATUS stops it.
+func (ppu *P2c02) NMIAsserted() bool {
+	return ppu.PpuStatus.VerticalBlankStarted && ppu.PpuControl.GenerateNMIAtVBlank
 }
 
 /*
@@ -262,12 +269,36 @@ func (ppu *P2c02) ResetNmi() {
 */
 func (ppu *P2c02) GetRGBColor(palette byte, colorIndex byte) color.RGBA {
 	paletteColor := ppu.GetPaletteColor(palette, colorIndex)
-	return color.RGBA{
-		R: SystemPalette[paletteColor][0],
-		G: SystemPalette[paletteColor][1],
-		B: SystemPalette[paletteColor][2],
-		A: 255,
+	return ppu.palette.Color(paletteColor, 0)
+}
+
+// outputColor gets the color sent to the screen, with PPUMASK greyscale and color emphasis applied
+func (ppu *P2c02) outputColor(palette byte, colorIndex byte) color.RGBA {
+	paletteColor := ppu.GetPaletteColor(palette, colorIndex) & ppu.PpuMask.colorMask()
+	emphasis := ppu.PpuMask.emphasis()
+	if ppu.timing.SwapRedGreenEmphasis {
+		emphasis = emphasis&0b100 | emphasis&0b01<<1 | emphasis&0b10>>1
 	}
+
+	return ppu.palette.Color(paletteColor, emphasis)
+}
+
+// SetTiming changes the frame layout to the one of a console region
+func (ppu *P2c02) SetTiming(timing Timing) {
+	ppu.timing = timing
+}
+
+func (ppu *P2c02) Timing() Timing {
+	return ppu.timing
+}
+
+// SetPalette changes the RGB colors used to render NES colors
+func (ppu *P2c02) SetPalette(palette Palette) {
+	ppu.palette = palette
+}
+
+func (ppu *P2c02) Palette() Palette {
+	return ppu.palette
 }
 
 func (ppu *P2c02) GetPaletteColor(palette byte, colorIndex byte) byte {
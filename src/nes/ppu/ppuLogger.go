@@ -111,7 +111,7 @@ func (logger *logger2c02) log(ppu *P2c02) {
 		cycle:                ppu.cycle,
 		renderCycle:          ppu.renderCycle,
 		currentScanline:      ppu.currentScanline,
-		nmi:                  ppu.nmi,
+		nmi:                  ppu.NMIAsserted(),
 	}
 
 	logger.snapshots = append(logger.snapshots, state)
@@ -22,6 +22,8 @@ func aPPU() *P2c02 {
 	)
 	ppu := CreatePPU(cartridge, false, "")
 	ppu.warmup = true
+	// Expected colors in tests are taken from SystemPalette
+	ppu.SetPalette(NewPalette(SystemPalette))
 
 	return ppu
 }
@@ -36,6 +38,16 @@ func TestPpu2c02_odd_frames_are_1_cycle_shorter_when_rendering_is_enabled(t *tes
 	assert.Equal(t, uint16(1), ppu.renderCycle, "Did not skip cycle 0")
 }
 
+func TestPpu2c02_odd_frames_are_1_cycle_shorter_when_only_sprites_are_enabled(t *testing.T) {
+	ppu := aPPU()
+	ppu.PpuMask.ShowSprites = 1
+	for i := 0; i < (341 * (261 + 1)); i++ {
+		ppu.Tick()
+	}
+
+	assert.Equal(t, uint16(1), ppu.renderCycle, "Did not skip cycle 0")
+}
+
 func TestPpu2c02_odd_frames_are_last_341_cycles_when_rendering_is_disabled(t *testing.T) {
 	ppu := aPPU()
 	ppu.PpuMask.ShowBackground = 0
@@ -138,13 +150,172 @@ func Test_should_trigger_NMI_on_vBlank(t *testing.T) {
 
 			ppu.Tick()
 
-			assert.Equal(t, tt.allowNMI, ppu.nmi, "Unexpected NMI behaviour")
+			assert.Equal(t, tt.allowNMI, ppu.NMIAsserted(), "Unexpected NMI behaviour")
 		})
 	}
 }
 
+func Test_NMI_is_asserted_when_enabled_during_vBlank(t *testing.T) {
+	ppu := aPPU()
+	ppu.PpuStatus.VerticalBlankStarted = true
+	assert.False(t, ppu.NMIAsserted())
+
+	ppu.WriteRegister(PPUCTRL, 0x80)
+
+	assert.True(t, ppu.NMIAsserted())
+}
+
+func Test_NMI_is_not_asserted_after_reading_PPUSTATUS(t *testing.T) {
+	ppu := aPPU()
+	ppu.PpuControl.GenerateNMIAtVBlank = true
+	ppu.PpuStatus.VerticalBlankStarted = true
+
+	ppu.ReadRegister(PPUSTATUS)
+
+	assert.False(t, ppu.NMIAsserted())
+}
+
 // Testing Render lifecycle
 //func Test_should_load_next_tileId(t *testing.T) {
 //	ppu := aPPU()
 //
 //}
+
+func TestPPU_sprite_fetches_clock_MMC3_scanline_counter(t *testing.T) {
+	header := gamePak.CreateINes1Header(2, 1, 0x40, 0, 0, 0, 0)
+	cartridge := gamePak.CreateGamePak(header, make([]byte, 0x8000), make([]byte, 0x2000))
+	cartridge.WritePrgROM(0xC000, 5) // IRQ latch
+	cartridge.WritePrgROM(0xC001, 0) // IRQ reload
+	cartridge.WritePrgROM(0xE001, 0) // IRQ enable
+
+	ppu := CreatePPU(&cartridge, false, "")
+	ppu.warmup = true
+	ppu.PpuMask.ShowBackground = 1
+	ppu.PpuMask.ShowSprites = 1
+	ppu.PpuControl.BackgroundPatternTableAddress = 0
+	ppu.PpuControl.SpritePatternTableAddress = 1
+
+	for !(ppu.currentScanline == 5 && ppu.renderCycle == 256) {
+		ppu.Tick()
+		assert.False(t, cartridge.IRQ(), "IRQ raised too early at scanline %d", ppu.currentScanline)
+	}
+
+	// First sprite pattern fetch happens on cycle 261
+	for ppu.renderCycle < 261 {
+		ppu.Tick()
+		assert.False(t, cartridge.IRQ(), "IRQ raised before sprite patterns are fetched, at cycle %d", ppu.renderCycle)
+	}
+	ppu.Tick()
+	assert.True(t, cartridge.IRQ(), "IRQ should be raised once sprite patterns are fetched on scanline 5")
+}
+
+func TestPpu2c02_frame_length_depends_on_region(t *testing.T) {
+	cases := []struct {
+		name   string
+		timing Timing
+		cycles int
+	}{
+		{"NTSC, odd frame skips a cycle", NTSCTiming, 341*262*2 - 1},
+		{"PAL", PALTiming, 341 * 312 * 2},
+		{"Dendy", DendyTiming, 341 * 312 * 2},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ppu := aPPU()
+			ppu.SetTiming(tt.timing)
+			ppu.PpuMask.ShowBackground = 1
+
+			for i := 0; i < tt.cycles; i++ {
+				ppu.Tick()
+			}
+
+			assert.Equal(t, uint16(2), ppu.FrameNumber())
+			assert.Equal(t, Scanline(0), ppu.currentScanline)
+			assert.Equal(t, uint16(0), ppu.renderCycle)
+		})
+	}
+}
+
+func TestPpu2c02_Dendy_VBlank_starts_on_scanline_291(t *testing.T) {
+	ppu := aPPU()
+	ppu.SetTiming(DendyTiming)
+	ppu.currentScanline = 241
+	ppu.renderCycle = 1
+	ppu.Tick()
+	assert.False(t, ppu.PpuStatus.VerticalBlankStarted)
+
+	ppu.currentScanline = 291
+	ppu.renderCycle = 1
+	ppu.Tick()
+	assert.True(t, ppu.PpuStatus.VerticalBlankStarted)
+}
+
+func TestPpu2c02_PAL_swaps_red_and_green_emphasis(t *testing.T) {
+	ppu := aPPU()
+	ppu.SetTiming(PALTiming)
+	ppu.paletteTable[0] = 0x16
+	ppu.PpuMask.EmphasizeRed = 1
+
+	assert.Equal(t, ppu.palette.Color(0x16, 0b010), ppu.outputColor(0, 0))
+}
+
+// TestPPU_MMC3_counter_is_clocked_once_per_rendered_scanline counts A12 clocks along whole frames.
+// Latch 0 raises an IRQ on every clock, which is acknowledged right away to count the next one.
+// With background patterns at 0x1000, A12 toggles on every tile fetch, and only the filter keeps it to one clock.
+func TestPPU_MMC3_counter_is_clocked_once_per_rendered_scanline(t *testing.T) {
+	tests := []struct {
+		name            string
+		rendering       bool
+		backgroundTable byte
+		spriteTable     byte
+		clocksPerFrame  int
+		clockCycle      uint16 // render cycle right after the clocking fetch
+	}{
+		{"sprites at 0x1000", true, 0, 1, 241, 262},
+		{"background at 0x1000", true, 1, 0, 241, 326},
+		{"rendering disabled", false, 0, 1, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := gamePak.CreateINes1Header(2, 1, 0x40, 0, 0, 0, 0)
+			cartridge := gamePak.CreateGamePak(header, make([]byte, 0x8000), make([]byte, 0x2000))
+			cartridge.WritePrgROM(0xC000, 0) // IRQ latch
+			cartridge.WritePrgROM(0xC001, 0) // IRQ reload
+			cartridge.WritePrgROM(0xE001, 0) // IRQ enable
+
+			ppu := CreatePPU(&cartridge, false, "")
+			ppu.warmup = true
+			if tt.rendering {
+				ppu.PpuMask.ShowBackground = 1
+				ppu.PpuMask.ShowSprites = 1
+			}
+			ppu.PpuControl.BackgroundPatternTableAddress = tt.backgroundTable
+			ppu.PpuControl.SpritePatternTableAddress = tt.spriteTable
+
+			// First frame settles the counter, second one is counted
+			for !ppu.FrameComplete() {
+				ppu.Tick()
+			}
+			cartridge.WritePrgROM(0xE000, 0)
+			cartridge.WritePrgROM(0xE001, 0)
+			clocks := 0
+			clockCycles := map[uint16]int{}
+			for !ppu.FrameComplete() {
+				ppu.Tick()
+				if cartridge.IRQ() {
+					clocks++
+					clockCycles[ppu.renderCycle]++
+					cartridge.WritePrgROM(0xE000, 0)
+					cartridge.WritePrgROM(0xE001, 0)
+				}
+			}
+
+			assert.Equal(t, tt.clocksPerFrame, clocks)
+			if tt.clocksPerFrame > 0 {
+				assert.Equal(t, map[uint16]int{tt.clockCycle: tt.clocksPerFrame}, clockCycles, "counter should be clocked on the same cycle of each scanline")
+			}
+		})
+	}
+}
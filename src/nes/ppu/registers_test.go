@@ -1,6 +1,7 @@
 package ppu
 
 import (
+	"fmt"
 	"github.com/raulferras/nes-golang/src/nes/gamePak"
 	"github.com/raulferras/nes-golang/src/nes/types"
 	"github.com/stretchr/testify/assert"
@@ -50,7 +51,7 @@ func TestPPU_PPUMASK_write(t *testing.T) {
 		write    byte
 		expected byte
 	}{
-		{"writes on blank", 0x00, 0xFF, 0x7F},
+		{"writes on blank", 0x00, 0xFF, 0xFF},
 		{"writes reset bits", 0xFF, 0x00, 0x00},
 	}
 
@@ -86,7 +87,17 @@ func TestPPU_PPUSTATUS_reading_status_clears_bit7_and_the_address_latch(t *testi
 
 // Reading PPUSTATUS within two cycles of the start of vertical blank will return 0 in bit 7 but clear the latch anyway, causing NMI to not occur that deprecatedFrame.
 func TestPPUSTATUS_should_clear_latch_when_reading_within_two_cycles_of_sthe_start_of_vblank(t *testing.T) {
-	t.Skipf("waiting to implement VBlanks")
+	ppu := aPPU()
+	ppu.PpuControl.GenerateNMIAtVBlank = true
+	ppu.currentScanline = 241
+	ppu.renderCycle = 1
+
+	status := ppu.ReadRegister(PPUSTATUS)
+	ppu.Tick()
+
+	assert.Equal(t, byte(0), status&0x80, "VBlank flag should read clear")
+	assert.False(t, ppu.PpuStatus.VerticalBlankStarted, "VBlank flag should not be set this frame")
+	assert.False(t, ppu.NMIAsserted(), "NMI should not occur this frame")
 }
 
 func TestPPUOAM_address_write(t *testing.T) {
@@ -162,6 +173,7 @@ func TestPPU_PPUADDR_write_twice_to_set_address(t *testing.T) {
 func TestPPU_PPUData_read(t *testing.T) {
 	const PALETTE_VALUE = byte(0x20)
 	const EXPECTED_VALUE = byte(0x15)
+	const NAMETABLE_BELOW_PALETTE_VALUE = byte(0x33)
 
 	cases := []struct {
 		name          string
@@ -173,11 +185,12 @@ func TestPPU_PPUData_read(t *testing.T) {
 		{"buffered read, increment mode going across", 0x2600, 0, 0, EXPECTED_VALUE},
 		{"buffered read, increment mode going down", 0x2600, 1, 0, EXPECTED_VALUE},
 		{"reading from palette addresses does not buffer", 0x3F00, 0, PALETTE_VALUE, 0},
-		{"reading from palette addresses does not buffer", 0x3FFF, 0, PALETTE_VALUE, PALETTE_VALUE},
+		{"reading from palette buffers nametable byte below it", 0x3FFF, 0, PALETTE_VALUE, NAMETABLE_BELOW_PALETTE_VALUE},
 	}
 
 	ppu := aPPU()
 	ppu.Write(0x2600, EXPECTED_VALUE)
+	ppu.Write(0x2FFF, NAMETABLE_BELOW_PALETTE_VALUE)
 	ppu.Write(0x3F00, PALETTE_VALUE)
 	ppu.Write(0x3FFF, PALETTE_VALUE)
 
@@ -256,3 +269,113 @@ func TestPPU_PPUData_write(t *testing.T) {
 func TestOAMDMA(t *testing.T) {
 
 }
+
+func TestPPU_write_only_registers_read_the_io_latch(t *testing.T) {
+	registers := []types.Address{PPUCTRL, PPUMASK, OAMADDR, PPUSCROLL, PPUADDR}
+
+	for _, register := range registers {
+		t.Run(fmt.Sprintf("%04X", register), func(t *testing.T) {
+			ppu := aPPU()
+			ppu.WriteRegister(PPUSTATUS, 0xA5)
+
+			assert.Equal(t, byte(0xA5), ppu.ReadRegister(register))
+		})
+	}
+}
+
+func TestPPU_PPUSTATUS_low_bits_come_from_io_latch(t *testing.T) {
+	ppu := aPPU()
+	ppu.PpuStatus.VerticalBlankStarted = true
+	ppu.WriteRegister(PPUMASK, 0x1F)
+
+	assert.Equal(t, byte(0x9F), ppu.ReadRegister(PPUSTATUS))
+	ppu.PpuStatus.VerticalBlankStarted = false
+	assert.Equal(t, byte(0x1F), ppu.ReadRegister(PPUSTATUS), "previous read drove bit 7 into the latch, but status bits win")
+	assert.Equal(t, byte(0x1F), ppu.ReadRegister(PPUCTRL), "reading PPUSTATUS refreshes the top 3 bits of the latch")
+}
+
+func TestPPU_io_latch_decays(t *testing.T) {
+	ppu := aPPU()
+	ppu.WriteRegister(PPUCTRL, 0xFF)
+
+	ppu.clock += ioLatchDecayCycles / 2
+	ppu.PpuStatus.Sprite0Hit = 1
+	ppu.ReadRegister(PPUSTATUS) // Refreshes top 3 bits with 0b010
+	assert.Equal(t, byte(0x5F), ppu.ReadRegister(PPUCTRL))
+
+	ppu.clock += ioLatchDecayCycles/2 + 1
+	assert.Equal(t, byte(0x40), ppu.ReadRegister(PPUCTRL), "bits not refreshed should decay")
+
+	ppu.clock += ioLatchDecayCycles + 1
+	assert.Equal(t, byte(0x00), ppu.ReadRegister(PPUCTRL))
+}
+
+func TestPPU_PPUDATA_palette_read_top_bits_come_from_io_latch(t *testing.T) {
+	ppu := aPPU()
+	ppu.Write(0x3F01, 0x2A)
+	ppu.WriteRegister(PPUMASK, 0xC0)
+	ppu.vRam.setValue(0x3F01)
+
+	assert.Equal(t, byte(0xEA), ppu.ReadRegister(PPUDATA))
+}
+
+func TestPPU_PPUDATA_palette_read_applies_greyscale(t *testing.T) {
+	ppu := aPPU()
+	ppu.Write(0x3F01, 0x2A)
+	ppu.WriteRegister(PPUMASK, 0x01)
+	ppu.vRam.setValue(0x3F01)
+
+	assert.Equal(t, byte(0x20), ppu.ReadRegister(PPUDATA))
+}
+
+func TestPPU_OAMDATA_read_unimplemented_attribute_bits_as_0(t *testing.T) {
+	ppu := aPPU()
+	ppu.WriteRegister(OAMADDR, 0)
+	for i := 0; i < 4; i++ {
+		ppu.WriteRegister(OAMDATA, 0xFF)
+	}
+
+	values := make([]byte, 4)
+	for i := range values {
+		ppu.WriteRegister(OAMADDR, byte(i))
+		values[i] = ppu.ReadRegister(OAMDATA)
+	}
+
+	assert.Equal(t, []byte{0xFF, 0xFF, 0xE3, 0xFF}, values)
+}
+
+func TestPPU_PPUDATA_access_during_rendering_increments_coarse_x_and_y(t *testing.T) {
+	cases := []struct {
+		name   string
+		access func(ppu *P2c02)
+	}{
+		{"read", func(ppu *P2c02) { ppu.ReadRegister(PPUDATA) }},
+		{"write", func(ppu *P2c02) { ppu.WriteRegister(PPUDATA, 0) }},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ppu := aPPU()
+			ppu.PpuMask.write(0b00011000)
+			ppu.currentScanline = 100
+			ppu.vRam.setValue(5<<5 | 10) // Coarse Y 5, coarse X 10, fine Y 0
+
+			tt.access(ppu)
+
+			assert.Equal(t, uint8(11), ppu.vRam.CoarseX())
+			assert.Equal(t, uint8(5), ppu.vRam.CoarseY())
+			assert.Equal(t, uint8(1), ppu.vRam.FineY())
+		})
+	}
+}
+
+func TestPPU_PPUDATA_access_outside_rendering_increments_address(t *testing.T) {
+	ppu := aPPU()
+	ppu.PpuMask.write(0b00011000)
+	ppu.currentScanline = 245
+	ppu.vRam.setValue(0x2000)
+
+	ppu.ReadRegister(PPUDATA)
+
+	assert.Equal(t, types.Address(0x2001), ppu.vRam.address())
+}
@@ -18,14 +18,14 @@ func (ppu *P2c02) Render() {
 
 func (ppu *P2c02) scanlineIsVisibleOrIsPreRender() bool {
 	scanlineVisible := ppu.currentScanline < 240
-	preRenderScanline := ppu.currentScanline == 261
+	preRenderScanline := ppu.currentScanline == ppu.timing.preRenderScanline()
 
 	return scanlineVisible || preRenderScanline
 }
 
 func (ppu *P2c02) renderLogic() {
 	//renderingEnabled := ppu.PpuMask.ShowBackground || ppu.PpuMask.ShowSprites
-	preRenderScanline := ppu.currentScanline == 261
+	preRenderScanline := ppu.currentScanline == ppu.timing.preRenderScanline()
 	scanlineVisible := ppu.currentScanline < 240
 
 	// We are in a cycle which falls inside the visible horizontal region
@@ -118,27 +118,22 @@ func (ppu *P2c02) renderLogic() {
 		}
 
 		// Sprites stuff -------------------
-		if ppu.renderCycle == 257 && scanlineVisible {
-			for i := 0; i < 8; i++ {
-				ppu.oamDataScanline[i] = objectAttributeEntry{0, 0, 0, 0}
-				ppu.spShifterPatternLow[i] = 0x00
-				ppu.spShifterPatternHigh[i] = 0x00
+		if ppu.PpuMask.renderingEnabled() {
+			if scanlineVisible {
+				ppu.evaluateSprites()
 			}
-		}
 
-		if ppu.renderCycle == 257 {
-			// Pre-fetching sprites for next Scanline
-			// This is not fully accurate, sprite loading occurs along different cycles.
-			ppu.loadNextScanLineSprites()
-		}
+			if ppu.renderCycle == 257 {
+				ppu.loadScanlineSprites(scanlineVisible)
+			}
 
-		if ppu.renderCycle == 340 {
-			// Get sprite pattern information
-			// Doing this at cycle 340 is a simplification of what the real NES actually does.
-			// More info: https://www.nesdev.org/wiki/PPU_sprite_evaluation
-			ppu.loadSpriteShifters()
-			ppu.checkSprite0Hit()
+			if ppu.renderCycle >= 257 && ppu.renderCycle <= 320 {
+				// OAMADDR is reset along sprite fetches
+				ppu.oamAddr = 0
+				ppu.fetchSprites()
+			}
 		}
+
 		// ---------------------------------
 	}
 
@@ -146,79 +141,111 @@ func (ppu *P2c02) renderLogic() {
 		// idle PPU does nothing here
 	}
 
-	ppu.finalPixelComposition()
+	if scanlineVisible && cycleIsVisible {
+		ppu.finalPixelComposition()
+		ppu.updateSpriteShifters()
+	}
 }
 
 func (ppu *P2c02) finalPixelComposition() {
-	var bgPixel byte = 0x00
-	var bgPalette byte = 0x00
-	if ppu.PpuMask.showBackgroundEnabled() {
-		bitSelector := uint16(0x8000) >> ppu.fineX
-		pixel0 := byte(0)
-		pixel1 := byte(0)
-		if ppu.bgShifterTileLow&bitSelector > 0 {
-			pixel0 = 1
-		} else {
-			pixel0 = 0
-		}
-		if ppu.bgShifterTileHigh&bitSelector > 0 {
-			pixel1 = 1
-		} else {
-			pixel1 = 0
-		}
-		bgPixel = pixel1<<1 | pixel0
-
-		palette0 := byte(0)
-		palette1 := byte(0)
-		if ppu.bgShifterAttributeLow&bitSelector > 0 {
-			palette0 = 1
-		} else {
-			palette0 = 0
-		}
-		if ppu.bgShifterAttributeHigh&bitSelector > 0 {
-			palette1 = 1
-		} else {
-			palette1 = 0
-		}
-		bgPalette = palette1<<1 | palette0
-	}
-
-	var fgPixel byte
-	var fgPalette byte
-	//var fgPriority byte //todo implement priority
-	if ppu.PpuMask.showSpritesEnabled() {
-		for i := byte(0); i < ppu.spriteScanlineCount; i++ {
-			if ppu.oamDataScanline[i].x > 0 {
-				continue
-			}
-
-			fgPixelLow := (ppu.spShifterPatternLow[i] & 0x80) >> 7
-			fgPixelHigh := (ppu.spShifterPatternHigh[i] & 0x80) >> 7
-			fgPixel = fgPixelHigh<<1 | fgPixelLow
-
-			fgPalette = ppu.oamDataScanline[i].palette()
-			if fgPixel != 0 {
-				break
-			}
-		}
-	}
-
+	x := ppu.renderCycle - 1
+	bgPixel, bgPalette := ppu.backgroundPixel(x)
+	fgPixel, fgPalette, fgBehindBackground, sprite0Pixel := ppu.spritePixel(x)
+
+	ppu.checkSprite0Hit(x, bgPixel, sprite0Pixel)
+
+	// Priority multiplexer
+	//	BG  Sprite  Priority  Output
+	//	0   0       X         Universal background color
+	//	0   1-3     X         Sprite
+	//	1-3 0       X         Background
+	//	1-3 1-3     0         Sprite
+	//	1-3 1-3     1         Background
 	var finalPixel byte
 	var finalPalette byte
-	if fgPixel != 0 {
+	if fgPixel != 0 && (bgPixel == 0 || !fgBehindBackground) {
 		finalPixel = fgPixel
 		finalPalette = fgPalette
-	} else {
+	} else if bgPixel != 0 {
 		finalPixel = bgPixel
 		finalPalette = bgPalette
 	}
 
 	if ppu.renderByPixel {
 		ppu.screen.Set(
-			int(ppu.renderCycle-1),
+			int(x),
 			int(ppu.currentScanline),
-			ppu.GetRGBColor(finalPalette, finalPixel))
+			ppu.outputColor(finalPalette, finalPixel))
+	}
+}
+
+// backgroundPixel returns the background pixel color index (0: transparent) and its palette at screen coordinate x
+func (ppu *P2c02) backgroundPixel(x uint16) (pixel byte, palette byte) {
+	if !ppu.PpuMask.showBackgroundEnabled() {
+		return
 	}
+	if x < 8 && ppu.PpuMask.ShowBackgroundLeftMost == 0 {
+		return
+	}
+
+	bitSelector := uint16(0x8000) >> ppu.fineX
+	pixel0 := byte(0)
+	pixel1 := byte(0)
+	if ppu.bgShifterTileLow&bitSelector > 0 {
+		pixel0 = 1
+	}
+	if ppu.bgShifterTileHigh&bitSelector > 0 {
+		pixel1 = 1
+	}
+	pixel = pixel1<<1 | pixel0
+
+	palette0 := byte(0)
+	palette1 := byte(0)
+	if ppu.bgShifterAttributeLow&bitSelector > 0 {
+		palette0 = 1
+	}
+	if ppu.bgShifterAttributeHigh&bitSelector > 0 {
+		palette1 = 1
+	}
+	palette = palette1<<1 | palette0
+
+	return
+}
+
+// spritePixel returns the first opaque sprite pixel at screen coordinate x.
+// Sprites are stored by OAM index, so lower indexes win over higher ones,
+// even when the winner is behind the background.
+// sprite0Pixel is the pixel of sprite 0 when it is the one found at x.
+func (ppu *P2c02) spritePixel(x uint16) (pixel byte, palette byte, behindBackground bool, sprite0Pixel byte) {
+	if !ppu.PpuMask.showSpritesEnabled() {
+		return
+	}
+	if x < 8 && ppu.PpuMask.ShowSpritesLeftMost == 0 {
+		return
+	}
+
+	for i := byte(0); i < ppu.spriteScanlineCount; i++ {
+		object := &ppu.oamDataScanline[i]
+		if object.x > 0 {
+			continue
+		}
+
+		pixelLow := (ppu.spShifterPatternLow[i] & 0x80) >> 7
+		pixelHigh := (ppu.spShifterPatternHigh[i] & 0x80) >> 7
+		pixel = pixelHigh<<1 | pixelLow
+		if pixel == 0 {
+			continue
+		}
+
+		palette = object.palette()
+		behindBackground = object.isBehindBackground()
+		if i == 0 && ppu.spriteZeroInScanline {
+			sprite0Pixel = pixel
+		}
+		return
+	}
+
+	return
 }
 
 // updateShifters
@@ -233,14 +260,22 @@ func (ppu *P2c02) updateShifters() {
 		ppu.bgShifterAttributeHigh <<= 1
 	}
 
-	if ppu.PpuMask.showSpritesEnabled() && ppu.renderCycle >= 1 && ppu.renderCycle < 258 {
-		for i := byte(0); i < ppu.spriteScanlineCount; i++ {
-			if ppu.oamDataScanline[i].x > 0 {
-				ppu.oamDataScanline[i].x--
-			} else {
-				ppu.spShifterPatternLow[i] <<= 1
-				ppu.spShifterPatternHigh[i] <<= 1
-			}
+}
+
+// updateSpriteShifters
+// Sprites wait until their X counter reaches 0, then shift one pixel per cycle.
+// It runs after the current pixel has been composed, so a sprite at X is drawn starting at X.
+func (ppu *P2c02) updateSpriteShifters() {
+	if !ppu.PpuMask.showSpritesEnabled() {
+		return
+	}
+
+	for i := byte(0); i < ppu.spriteScanlineCount; i++ {
+		if ppu.oamDataScanline[i].x > 0 {
+			ppu.oamDataScanline[i].x--
+		} else {
+			ppu.spShifterPatternLow[i] <<= 1
+			ppu.spShifterPatternHigh[i] <<= 1
 		}
 	}
 }
@@ -374,8 +409,8 @@ func (ppu *P2c02) findTile(tileID byte, patternTable byte, tileColumn uint8, til
 	}
 
 	for y := 0; y <= 7; y++ {
-		lower := ppu.Read(offsetAddress + types.Address(y))
-		upper := ppu.Read(offsetAddress + types.Address(y+8))
+		lower := ppu.Peek(offsetAddress + types.Address(y))
+		upper := ppu.Peek(offsetAddress + types.Address(y+8))
 
 		for x := 0; x <= 7; x++ {
 			value := (1&upper)<<1 | (1 & lower)
@@ -3,6 +3,7 @@ package ppu
 import (
 	"fmt"
 	"github.com/raulferras/nes-golang/src/nes/gamePak"
+	"github.com/raulferras/nes-golang/src/nes/types"
 	"github.com/raulferras/nes-golang/src/utils"
 	"github.com/stretchr/testify/assert"
 	"image"
@@ -88,6 +89,7 @@ func Test_getsTile(t *testing.T) {
 
 	cartridge := gamePak.NewDummyGamePak(chrROM)
 	ppu := CreatePPU(cartridge, false, "")
+	ppu.SetPalette(NewPalette(SystemPalette))
 	ppu.nameTables[0] = 0
 	ppu.paletteTable[0] = 0x0F
 	ppu.paletteTable[1] = 0x30
@@ -287,3 +289,139 @@ func TestPpu2c02_GetNesColorFromPaletteRam_should_gets_background_color(t *testi
 	nesColor = ppu.GetPaletteColor(3, 0)
 	assert.Equal(t, backgroundColor, nesColor)
 }
+
+func TestPpu2c02_finalPixelComposition(t *testing.T) {
+	type sprite struct {
+		pixel      byte
+		attributes byte
+	}
+	cases := []struct {
+		name            string
+		x               uint16
+		mask            byte
+		bgPixel         byte
+		sprites         []sprite
+		expectedPalette byte
+		expectedPixel   byte
+	}{
+		{"both transparent shows universal background color", 20, 0b00011110, 0, []sprite{{0, 0}}, 0, 0},
+		{"transparent sprite shows background", 20, 0b00011110, 1, []sprite{{0, 0}}, 0, 1},
+		{"sprite over transparent background", 20, 0b00011110, 0, []sprite{{2, 0x00}}, 4, 2},
+		{"sprite behind transparent background", 20, 0b00011110, 0, []sprite{{2, 0x20}}, 4, 2},
+		{"sprite in front of opaque background", 20, 0b00011110, 1, []sprite{{2, 0x00}}, 4, 2},
+		{"sprite behind opaque background", 20, 0b00011110, 1, []sprite{{2, 0x20}}, 0, 1},
+		{"lower OAM index wins", 20, 0b00011110, 0, []sprite{{2, 0x01}, {3, 0x02}}, 5, 2},
+		{"transparent sprite lets next sprite through", 20, 0b00011110, 0, []sprite{{0, 0x01}, {3, 0x02}}, 6, 3},
+		{"sprite behind background hides sprites in front of it", 20, 0b00011110, 1, []sprite{{2, 0x20}, {3, 0x00}}, 0, 1},
+		{"sprites hidden in leftmost 8 pixels", 7, 0b00011010, 1, []sprite{{2, 0x00}}, 0, 1},
+		{"background hidden in leftmost 8 pixels", 7, 0b00010110, 1, []sprite{{0, 0x00}}, 0, 0},
+		{"sprites visible after leftmost 8 pixels", 8, 0b00011010, 1, []sprite{{2, 0x00}}, 4, 2},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ppu := aPPU()
+			for i := byte(0); i < 0x20; i++ {
+				ppu.Write(PaletteLowAddress+types.Address(i), i)
+			}
+			ppu.PpuMask.write(tt.mask)
+			ppu.currentScanline = 10
+			ppu.renderCycle = tt.x + 1
+			ppu.bgShifterTileLow = uint16(tt.bgPixel&0x01) << 15
+			ppu.bgShifterTileHigh = uint16(tt.bgPixel>>1) << 15
+			for i, sprite := range tt.sprites {
+				ppu.oamDataScanline[i] = objectAttributeEntry{attributes: sprite.attributes}
+				ppu.spShifterPatternLow[i] = (sprite.pixel & 0x01) << 7
+				ppu.spShifterPatternHigh[i] = (sprite.pixel >> 1) << 7
+			}
+			ppu.spriteScanlineCount = byte(len(tt.sprites))
+
+			ppu.finalPixelComposition()
+
+			assert.Equal(t, ppu.GetRGBColor(tt.expectedPalette, tt.expectedPixel), ppu.screen.RGBAAt(int(tt.x), 10))
+		})
+	}
+}
+
+func TestPpu2c02_finalPixelComposition_sprite0Hit(t *testing.T) {
+	cases := []struct {
+		name         string
+		x            uint16
+		mask         byte
+		bgPixel      byte
+		spritePixel  byte
+		spriteZero   bool
+		attributes   byte
+		expectedFlag byte
+	}{
+		{"opaque sprite 0 over opaque background", 20, 0b00011110, 1, 1, true, 0, 1},
+		{"sprite 0 behind background still hits", 20, 0b00011110, 1, 1, true, 0x20, 1},
+		{"transparent background", 20, 0b00011110, 0, 1, true, 0, 0},
+		{"transparent sprite 0", 20, 0b00011110, 1, 0, true, 0, 0},
+		{"sprite is not sprite 0", 20, 0b00011110, 1, 1, false, 0, 0},
+		{"x=255", 255, 0b00011110, 1, 1, true, 0, 0},
+		{"sprites clipped at leftmost 8 pixels", 0, 0b00011100, 1, 1, true, 0, 0},
+		{"background clipped at leftmost 8 pixels", 7, 0b00011010, 1, 1, true, 0, 0},
+		{"no clipping at leftmost 8 pixels", 0, 0b00011110, 1, 1, true, 0, 1},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ppu := aPPU()
+			ppu.PpuMask.write(tt.mask)
+			ppu.currentScanline = 10
+			ppu.renderCycle = tt.x + 1
+			ppu.bgShifterTileLow = uint16(tt.bgPixel) << 15
+			ppu.oamDataScanline[0] = objectAttributeEntry{attributes: tt.attributes}
+			ppu.spShifterPatternLow[0] = tt.spritePixel << 7
+			ppu.spriteScanlineCount = 1
+			ppu.spriteZeroInScanline = tt.spriteZero
+
+			ppu.finalPixelComposition()
+
+			assert.Equal(t, tt.expectedFlag, ppu.PpuStatus.Sprite0Hit)
+		})
+	}
+}
+
+func TestPpu2c02_updateSpriteShifters_waits_for_sprite_x_before_shifting(t *testing.T) {
+	ppu := aPPU()
+	ppu.PpuMask.ShowSprites = 1
+	ppu.spriteScanlineCount = 1
+	ppu.oamDataScanline[0] = objectAttributeEntry{x: 1}
+	ppu.spShifterPatternLow[0] = 0xC0
+
+	ppu.updateSpriteShifters()
+	assert.Equal(t, byte(0), ppu.oamDataScanline[0].x)
+	assert.Equal(t, byte(0xC0), ppu.spShifterPatternLow[0])
+
+	ppu.updateSpriteShifters()
+	assert.Equal(t, byte(0x80), ppu.spShifterPatternLow[0])
+}
+
+func TestPpu2c02_outputColor_applies_PPUMASK(t *testing.T) {
+	paletteColor := byte(0x16) // {152, 34, 32}
+	cases := []struct {
+		name     string
+		mask     byte
+		expected color.RGBA
+	}{
+		{"no effects", 0b00011110, color.RGBA{R: 152, G: 34, B: 32, A: 255}},
+		{"greyscale", 0b00011111, color.RGBA{R: 152, G: 150, B: 152, A: 255}},
+		{"emphasize red", 0b00111110, color.RGBA{R: 152, G: 27, B: 26, A: 255}},
+		{"emphasize green", 0b01011110, color.RGBA{R: 124, G: 34, B: 26, A: 255}},
+		{"emphasize blue", 0b10011110, color.RGBA{R: 124, G: 27, B: 32, A: 255}},
+		{"emphasize all", 0b11111110, color.RGBA{R: 124, G: 27, B: 26, A: 255}},
+		{"greyscale and emphasis", 0b00100001, color.RGBA{R: 152, G: 122, B: 124, A: 255}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ppu := aPPU()
+			ppu.Write(PaletteLowAddress+1, paletteColor)
+			ppu.PpuMask.write(tt.mask)
+
+			assert.Equal(t, tt.expected, ppu.outputColor(0, 1))
+		})
+	}
+}
@@ -5,78 +5,208 @@ import (
 	"math/bits"
 )
 
-func (ppu *P2c02) loadNextScanLineSprites() {
-	ppu.spriteScanlineCount = 0
-	var spriteHeight int16
-	if ppu.PpuControl.SpriteSize == 0 {
-		spriteHeight = 8
-	} else {
-		spriteHeight = 16
-	}
-
-	for oamIndex := 0; oamIndex < OAMDATA_SIZE && ppu.spriteScanlineCount < 8; oamIndex += 4 {
-		spriteY := int16(ppu.oamData[oamIndex])
-		diff := int16(ppu.currentScanline) - spriteY
-		if diff >= 0 && diff < spriteHeight {
-			ppu.oamDataScanline[ppu.spriteScanlineCount] = objectAttributeEntry{
-				y:          byte(spriteY),
-				tileId:     ppu.oamData[oamIndex+1],
-				attributes: ppu.oamData[oamIndex+2],
-				x:          ppu.oamData[oamIndex+3],
-			}
-			ppu.spriteScanlineCount++
-		}
-	}
+// spriteEvaluation holds the state of the sprite evaluation running along a visible scanline.
+// More info: https://www.nesdev.org/wiki/PPU_sprite_evaluation
+type spriteEvaluation struct {
+	n              byte // Sprite being evaluated in primary OAM, 0 to 63
+	m              byte // Byte being read from the sprite, 0 to 3
+	secondaryIndex byte // Next free byte in secondary OAM. Secondary OAM is full when it reaches 32
+	latch          byte // Byte read from primary OAM on odd cycles, written on even cycles
+	spriteZero     bool // Sprite 0 was copied into secondary OAM
+	done           bool // All 64 sprites were evaluated, or overflow was found
 }
 
-// Get sprite pattern information
-// Doing this at cycle 340 is a simplification of what the real NES actually does.
-// More info: https://www.nesdev.org/wiki/PPU_sprite_evaluation
-func (ppu *P2c02) loadSpriteShifters() {
-	var spritePatternAddressLow types.Address
-	var spritePatternAddressHigh types.Address
-	var spritePatternLow byte
-	var spritePatternHigh byte
-
-	for i := byte(0); i < ppu.spriteScanlineCount; i++ {
-		object := ppu.oamDataScanline[i]
-
-		if ppu.PpuControl.SpriteSize == PPU_CONTROL_SPRITE_SIZE_8 {
-			if !object.isFlippedVertically() {
-				spritePatternAddressLow = types.Address(ppu.PpuControl.SpritePatternTableAddress) << 12
-				spritePatternAddressLow |= types.Address(object.tileId) << 4                         // Multiply ID per 16 (16 bytes per tile)
-				spritePatternAddressLow |= types.Address((ppu.currentScanline) - Scanline(object.y)) // Which tile line we want
-			} else {
-				spritePatternAddressLow = types.Address(ppu.PpuControl.SpritePatternTableAddress) << 12
-				spritePatternAddressLow |= types.Address(object.tileId) << 4                             // Multiply ID per 16 (16 bytes per tile)
-				spritePatternAddressLow |= types.Address(7 - (ppu.currentScanline - Scanline(object.y))) // Which tile line we want
-			}
-		} else {
-			// TODO implement 8x16 sprites
+// evaluateSprites runs one cycle of the evaluation that finds the sprites for next scanline.
+//
+//	Cycles 1-64: secondary OAM is cleared to $FF, one byte every 2 cycles.
+//	Cycles 65-256: odd cycles read from primary OAM, even cycles write to secondary OAM.
+func (ppu *P2c02) evaluateSprites() {
+	cycle := ppu.renderCycle
+	if cycle == 0 || cycle > 256 {
+		return
+	}
+
+	if cycle <= 64 {
+		if cycle%2 == 0 {
+			ppu.secondaryOAM[cycle/2-1] = 0xFF
 		}
+		return
+	}
+
+	evaluation := &ppu.spriteEvaluation
+	if cycle == 65 {
+		*evaluation = spriteEvaluation{}
+	}
+
+	if cycle%2 == 1 {
+		evaluation.latch = ppu.oamData[evaluation.n*4+evaluation.m]
+		return
+	}
 
-		spritePatternAddressHigh = spritePatternAddressLow + 8
-		spritePatternLow = ppu.Read(spritePatternAddressLow)
-		spritePatternHigh = ppu.Read(spritePatternAddressHigh)
+	if evaluation.done {
+		// Attempts (and fails) to copy into a full secondary OAM. Nothing observable happens.
+		return
+	}
 
-		if object.isFlippedHorizontally() {
-			spritePatternLow = bits.Reverse8(spritePatternLow)
-			spritePatternHigh = bits.Reverse8(spritePatternHigh)
+	if evaluation.secondaryIndex < 32 {
+		// Y is always copied, but the slot is only kept when the sprite is in range
+		ppu.secondaryOAM[evaluation.secondaryIndex] = evaluation.latch
+		if evaluation.m == 0 && !ppu.spriteInRange(evaluation.latch) {
+			ppu.nextEvaluatedSprite()
+			return
 		}
 
-		ppu.spShifterPatternLow[i] = spritePatternLow
-		ppu.spShifterPatternHigh[i] = spritePatternHigh
+		if evaluation.n == 0 {
+			evaluation.spriteZero = true
+		}
+		evaluation.secondaryIndex++
+		evaluation.m++
+		if evaluation.m == 4 {
+			evaluation.m = 0
+			ppu.nextEvaluatedSprite()
+		}
+		return
 	}
-}
 
-func (ppu *P2c02) checkSprite0Hit() {
-	if !ppu.PpuMask.showSpritesEnabled() {
+	// Secondary OAM is full: look for a 9th sprite to set the overflow flag.
+	// Hardware bug: m is incremented together with n, so bytes other than Y are taken as Y
+	// and overflow detection is unreliable.
+	if ppu.spriteInRange(evaluation.latch) {
+		ppu.PpuStatus.SpriteOverflow = 1
+		evaluation.done = true
 		return
 	}
+	evaluation.m = (evaluation.m + 1) & 0x03
+	ppu.nextEvaluatedSprite()
+}
+
+func (ppu *P2c02) nextEvaluatedSprite() {
+	ppu.spriteEvaluation.n++
+	if ppu.spriteEvaluation.n == 64 {
+		ppu.spriteEvaluation.n = 0
+		ppu.spriteEvaluation.done = true
+	}
+}
+
+// spriteInRange tells if a sprite with the given Y has to be drawn on next scanline
+func (ppu *P2c02) spriteInRange(y byte) bool {
+	diff := int16(ppu.currentScanline) - int16(y)
+
+	return diff >= 0 && diff < int16(ppu.spriteHeight())
+}
+
+// loadScanlineSprites moves the sprites found by the evaluation into the sprite units.
+// Pre-render scanline does not evaluate sprites, so no sprite is drawn on first scanline.
+func (ppu *P2c02) loadScanlineSprites(evaluated bool) {
+	ppu.spriteScanlineCount = 0
+	ppu.spriteZeroInScanline = false
+	if evaluated {
+		ppu.spriteScanlineCount = ppu.spriteEvaluation.secondaryIndex / 4
+		ppu.spriteZeroInScanline = ppu.spriteEvaluation.spriteZero
+	}
 
-	if Scanline(ppu.oamDataScanline[0].y) == ppu.currentScanline {
-		if uint16(ppu.oamDataScanline[0].x) <= ppu.renderCycle {
-			ppu.PpuStatus.Sprite0Hit = 1
+	for i := 0; i < 8; i++ {
+		ppu.oamDataScanline[i] = objectAttributeEntry{
+			y:          ppu.secondaryOAM[i*4],
+			tileId:     ppu.secondaryOAM[i*4+1],
+			attributes: ppu.secondaryOAM[i*4+2],
+			x:          ppu.secondaryOAM[i*4+3],
 		}
+		ppu.spShifterPatternLow[i] = 0x00
+		ppu.spShifterPatternHigh[i] = 0x00
 	}
 }
+
+// fetchSprites runs one cycle of the sprite fetches for next scanline, along cycles 257-320.
+// Each sprite takes 8 cycles: two garbage nametable fetches, then low and high pattern bytes.
+// Unused sprite slots still fetch tile $FF. Mappers watching the PPU bus rely on these fetches.
+func (ppu *P2c02) fetchSprites() {
+	i := byte((ppu.renderCycle - 257) / 8)
+
+	switch ppu.renderCycle % 8 {
+	case 1, 3:
+		ppu.Read(0x2000 | ppu.vRam.nameTableAddress())
+	case 5:
+		ppu.spShifterPatternLow[i] = ppu.fetchSpritePattern(i, 0)
+	case 7:
+		ppu.spShifterPatternHigh[i] = ppu.fetchSpritePattern(i, 8)
+	}
+}
+
+// fetchSpritePattern reads one plane (offset 0 or 8) of the sprite line to be drawn on next scanline
+func (ppu *P2c02) fetchSpritePattern(i byte, plane types.Address) byte {
+	if i >= ppu.spriteScanlineCount {
+		ppu.Read(ppu.spritePatternAddress(0xFF, 0) + plane)
+		return 0
+	}
+
+	object := ppu.oamDataScanline[i]
+
+	row := byte(ppu.currentScanline - Scanline(object.y)) // Which sprite line we want
+	if object.isFlippedVertically() {
+		// In 8x16 mode, flipping also swaps top and bottom tiles
+		row = ppu.spriteHeight() - 1 - row
+	}
+	pattern := ppu.Read(ppu.spritePatternAddress(object.tileId, row) + plane)
+
+	if object.isFlippedHorizontally() {
+		pattern = bits.Reverse8(pattern)
+	}
+
+	return pattern
+}
+
+func (ppu *P2c02) spriteHeight() byte {
+	if ppu.PpuControl.SpriteSize == PPU_CONTROL_SPRITE_SIZE_16 {
+		return 16
+	}
+
+	return 8
+}
+
+// spritePatternAddress returns the address of the low plane of the given sprite line.
+//
+// 8x8 sprites take the pattern table from PPUCTRL.
+// 8x16 sprites ignore it, and use a pair of tiles instead:
+//
+//	76543210
+//	||||||||
+//	|||||||+- Pattern table ($0000 or $1000)
+//	+++++++-- Tile number of the top half. Bottom half is the next tile.
+func (ppu *P2c02) spritePatternAddress(tileId byte, row byte) types.Address {
+	if ppu.PpuControl.SpriteSize == PPU_CONTROL_SPRITE_SIZE_8 {
+		address := types.Address(ppu.PpuControl.SpritePatternTableAddress) << 12
+		address |= types.Address(tileId) << 4 // Multiply ID per 16 (16 bytes per tile)
+		address |= types.Address(row & 0x07)
+
+		return address
+	}
+
+	tile := tileId & 0xFE
+	if row >= 8 {
+		tile++
+	}
+
+	address := types.Address(tileId&0x01) << 12
+	address |= types.Address(tile) << 4
+	address |= types.Address(row & 0x07)
+
+	return address
+}
+
+// checkSprite0Hit sets the sprite 0 hit flag when an opaque pixel of sprite 0
+// overlaps an opaque background pixel at screen coordinate x.
+// Pixels hidden by left-8 clipping never reach here as opaque, and x=255 never hits.
+func (ppu *P2c02) checkSprite0Hit(x uint16, bgPixel byte, sprite0Pixel byte) {
+	if !ppu.PpuMask.showBackgroundEnabled() || !ppu.PpuMask.showSpritesEnabled() {
+		return
+	}
+	if bgPixel == 0 || sprite0Pixel == 0 {
+		return
+	}
+	if x == 255 {
+		return
+	}
+
+	ppu.PpuStatus.Sprite0Hit = 1
+}
@@ -2,6 +2,7 @@ package ppu
 
 import (
 	"github.com/raulferras/nes-golang/src/nes/gamePak"
+	"github.com/raulferras/nes-golang/src/nes/types"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -31,6 +32,23 @@ func createCHRRom() []byte {
 	return chrROM
 }
 
+// evaluateSprites runs sprite evaluation along a whole scanline
+func evaluateSprites(ppu *P2c02) {
+	for cycle := uint16(1); cycle <= 256; cycle++ {
+		ppu.renderCycle = cycle
+		ppu.evaluateSprites()
+	}
+	ppu.loadScanlineSprites(true)
+}
+
+// fetchSpritePatterns runs sprite pattern fetches along cycles 257-320
+func fetchSpritePatterns(ppu *P2c02) {
+	for cycle := uint16(257); cycle <= 320; cycle++ {
+		ppu.renderCycle = cycle
+		ppu.fetchSprites()
+	}
+}
+
 func Test_loadSpritesShifters_should_render_sprite_line_no_flipping(t *testing.T) {
 	chrRom := createCHRRom()
 	cartridge := gamePak.NewDummyGamePak(chrRom)
@@ -41,7 +59,7 @@ func Test_loadSpritesShifters_should_render_sprite_line_no_flipping(t *testing.T
 	ppu.oamDataScanline[0] = objectAttributeEntry{y: 0, tileId: 0, attributes: 0x00, x: 0}
 	ppu.spriteScanlineCount = 1
 
-	ppu.loadSpriteShifters()
+	fetchSpritePatterns(ppu)
 
 	assert.Equal(t, byte(0b00000111), ppu.spShifterPatternLow[0])
 	assert.Equal(t, byte(0b00000111), ppu.spShifterPatternHigh[0])
@@ -57,9 +75,10 @@ func Test_loadSpritesShifters_should_render_sprite_line_vertical_flipping(t *tes
 	ppu.oamDataScanline[0] = objectAttributeEntry{y: 0, tileId: 0, attributes: 0x80, x: 0}
 	ppu.spriteScanlineCount = 1
 
-	ppu.loadSpriteShifters()
+	fetchSpritePatterns(ppu)
 
-	assert.Equal(t, byte(0b00000001), ppu.spShifterPatternLow[0])
+	// Line 1 of the sprite, flipped into line 6
+	assert.Equal(t, byte(0b01110000), ppu.spShifterPatternLow[0])
 	assert.Equal(t, byte(0b11111111), ppu.spShifterPatternHigh[0])
 }
 
@@ -73,7 +92,7 @@ func Test_loadSpritesShifters_should_render_sprite_line_horizontal_flipping(t *t
 	ppu.oamDataScanline[0] = objectAttributeEntry{y: 0, tileId: 0, attributes: 0x40, x: 0}
 	ppu.spriteScanlineCount = 1
 
-	ppu.loadSpriteShifters()
+	fetchSpritePatterns(ppu)
 
 	assert.Equal(t, byte(0b11100000), ppu.spShifterPatternLow[0])
 	assert.Equal(t, byte(0b11100000), ppu.spShifterPatternHigh[0])
@@ -89,9 +108,10 @@ func Test_loadSpritesShifters_should_render_sprite_line_vertical_and_horizontal_
 	ppu.oamDataScanline[0] = objectAttributeEntry{y: 0, tileId: 0, attributes: 0x80 | 0x40, x: 0}
 	ppu.spriteScanlineCount = 1
 
-	ppu.loadSpriteShifters()
+	fetchSpritePatterns(ppu)
 
-	assert.Equal(t, byte(0b10000000), ppu.spShifterPatternLow[0])
+	// Line 1 of the sprite, flipped into line 6
+	assert.Equal(t, byte(0b00001110), ppu.spShifterPatternLow[0])
 	assert.Equal(t, byte(0b11111111), ppu.spShifterPatternHigh[0])
 }
 
@@ -99,10 +119,20 @@ func Test_checkSprite0Hit_should_not_enable_flag_if_sprite_rendering_is_disabled
 	chrRom := createCHRRom()
 	cartridge := gamePak.NewDummyGamePak(chrRom)
 	ppu := CreatePPU(cartridge, false, "")
-	ppu.currentScanline = 10
-	ppu.oamDataScanline[0] = objectAttributeEntry{y: 10, tileId: 0, attributes: 0x80 | 0x40, x: 20}
+	ppu.PpuMask.write(0b00001010)
 
-	ppu.checkSprite0Hit()
+	ppu.checkSprite0Hit(20, 1, 1)
+
+	assert.Equal(t, byte(0), ppu.PpuStatus.Sprite0Hit)
+}
+
+func Test_checkSprite0Hit_should_not_enable_flag_if_background_rendering_is_disabled(t *testing.T) {
+	chrRom := createCHRRom()
+	cartridge := gamePak.NewDummyGamePak(chrRom)
+	ppu := CreatePPU(cartridge, false, "")
+	ppu.PpuMask.write(0b00010100)
+
+	ppu.checkSprite0Hit(20, 1, 1)
 
 	assert.Equal(t, byte(0), ppu.PpuStatus.Sprite0Hit)
 }
@@ -111,11 +141,20 @@ func Test_checkSprite0Hit_should_not_enable_flag_if_no_sprite_0_is_being_rendere
 	chrRom := createCHRRom()
 	cartridge := gamePak.NewDummyGamePak(chrRom)
 	ppu := CreatePPU(cartridge, false, "")
-	ppu.currentScanline = 10
-	ppu.renderCycle = 20
-	ppu.oamDataScanline[0] = objectAttributeEntry{y: 11, tileId: 0, attributes: 0x80 | 0x40, x: 20}
+	ppu.PpuMask.write(0b00011110)
+
+	ppu.checkSprite0Hit(20, 1, 0)
+
+	assert.Equal(t, byte(0), ppu.PpuStatus.Sprite0Hit)
+}
+
+func Test_checkSprite0Hit_should_not_enable_flag_if_sprite_0_hits_transparent_background_pixel(t *testing.T) {
+	chrRom := createCHRRom()
+	cartridge := gamePak.NewDummyGamePak(chrRom)
+	ppu := CreatePPU(cartridge, false, "")
+	ppu.PpuMask.write(0b00011110)
 
-	ppu.checkSprite0Hit()
+	ppu.checkSprite0Hit(20, 0, 3)
 
 	assert.Equal(t, byte(0), ppu.PpuStatus.Sprite0Hit)
 }
@@ -124,11 +163,301 @@ func Test_checkSprite0Hit_should_enable_flag_if_sprite_0_hits_opaque_background_
 	chrRom := createCHRRom()
 	cartridge := gamePak.NewDummyGamePak(chrRom)
 	ppu := CreatePPU(cartridge, false, "")
-	ppu.currentScanline = 10
-	ppu.renderCycle = 20
-	ppu.oamDataScanline[0] = objectAttributeEntry{y: 10, tileId: 0, attributes: 0x80 | 0x40, x: 20}
+	ppu.PpuMask.write(0b00011110)
+
+	ppu.checkSprite0Hit(20, 2, 3)
 
-	ppu.checkSprite0Hit()
+	assert.Equal(t, byte(1), ppu.PpuStatus.Sprite0Hit)
+}
 
+func Test_checkSprite0Hit_should_never_hit_at_x_255(t *testing.T) {
+	chrRom := createCHRRom()
+	cartridge := gamePak.NewDummyGamePak(chrRom)
+	ppu := CreatePPU(cartridge, false, "")
+	ppu.PpuMask.write(0b00011110)
+
+	ppu.checkSprite0Hit(255, 1, 1)
 	assert.Equal(t, byte(0), ppu.PpuStatus.Sprite0Hit)
+
+	ppu.checkSprite0Hit(254, 1, 1)
+	assert.Equal(t, byte(1), ppu.PpuStatus.Sprite0Hit)
+}
+
+// create8x16CHRRom fills the low plane of each tile line with its own address,
+// so tests can tell which tile line was fetched. High plane is the low plane inverted.
+func create8x16CHRRom() []byte {
+	chrROM := make([]byte, 0x2000)
+	for tile := 0; tile < 0x200; tile++ {
+		for line := 0; line < 8; line++ {
+			address := tile*16 + line
+			chrROM[address] = byte(tile<<3 | line)
+			chrROM[address+8] = ^chrROM[address]
+		}
+	}
+
+	return chrROM
+}
+
+func Test_loadSpritesShifters_8x16(t *testing.T) {
+	cases := []struct {
+		name         string
+		scanline     Scanline
+		tileId       byte
+		attributes   byte
+		expectedTile int
+		expectedLine int
+	}{
+		{"top half", 3, 0x02, 0x00, 0x02, 3},
+		{"bottom half", 10, 0x02, 0x00, 0x03, 2},
+		{"pattern table taken from tile bit 0", 3, 0x03, 0x00, 0x102, 3},
+		{"bottom half from second pattern table", 15, 0x03, 0x00, 0x103, 7},
+		{"vertical flip swaps halves", 1, 0x02, 0x80, 0x03, 6},
+		{"vertical flip on bottom half", 12, 0x02, 0x80, 0x02, 3},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			cartridge := gamePak.NewDummyGamePak(create8x16CHRRom())
+			ppu := CreatePPU(cartridge, false, "")
+			ppu.ppuCtrlWrite(0b00101000) // 8x16 sprites, 8x8 pattern table set to $1000 to prove it is ignored
+
+			ppu.currentScanline = tt.scanline
+			ppu.oamDataScanline[0] = objectAttributeEntry{y: 0, tileId: tt.tileId, attributes: tt.attributes, x: 0}
+			ppu.spriteScanlineCount = 1
+
+			fetchSpritePatterns(ppu)
+
+			expected := byte(tt.expectedTile<<3 | tt.expectedLine)
+			assert.Equal(t, expected, ppu.spShifterPatternLow[0])
+			assert.Equal(t, ^expected, ppu.spShifterPatternHigh[0])
+		})
+	}
+}
+
+func Test_evaluateSprites_8x16_sprites_are_16_lines_tall(t *testing.T) {
+	cartridge := gamePak.NewDummyGamePak(createCHRRom())
+	ppu := CreatePPU(cartridge, false, "")
+	ppu.oamData[0] = 10
+	ppu.oamData[4] = 20
+
+	ppu.currentScanline = 25
+	evaluateSprites(ppu)
+	assert.Equal(t, byte(1), ppu.spriteScanlineCount, "8x8 mode should only find sprite at y=20")
+
+	ppu.ppuCtrlWrite(0b00100000)
+	evaluateSprites(ppu)
+	assert.Equal(t, byte(2), ppu.spriteScanlineCount, "8x16 mode should find both sprites")
+}
+
+func TestPPU_Sprite_renders_8x16_sprites(t *testing.T) {
+	cartridge := gamePak.NewDummyGamePak(create8x16CHRRom())
+	ppu := CreatePPU(cartridge, false, "")
+	ppu.ppuCtrlWrite(0b00100000)
+	ppu.oamData[4] = 0    // y
+	ppu.oamData[5] = 0x02 // tile
+	ppu.oamData[6] = 0x80 // flipped vertically
+	ppu.oamData[7] = 0    // x
+
+	sprite := ppu.Sprite(1)
+
+	assert.Equal(t, 8, sprite.Bounds().Dx())
+	assert.Equal(t, 16, sprite.Bounds().Dy())
+
+	// Tile 0x03 line 7 (low plane 0x1F) is on top once flipped: first 3 pixels use color 2, remaining ones color 1
+	assert.Equal(t, ppu.GetRGBColor(4, 2), sprite.RGBAAt(0, 0))
+	assert.Equal(t, ppu.GetRGBColor(4, 1), sprite.RGBAAt(7, 0))
+}
+
+// setSprite writes a sprite into primary OAM
+func setSprite(ppu *P2c02, index int, y byte, tileId byte, attributes byte, x byte) {
+	ppu.oamData[index*4] = y
+	ppu.oamData[index*4+1] = tileId
+	ppu.oamData[index*4+2] = attributes
+	ppu.oamData[index*4+3] = x
+}
+
+// anEvaluationPPU creates a PPU on scanline 10 with all sprites out of screen.
+// Evaluation copies every Y it reads into the next free secondary OAM slot, so unused slots read $FF.
+func anEvaluationPPU() *P2c02 {
+	ppu := CreatePPU(gamePak.NewDummyGamePak(createCHRRom()), false, "")
+	ppu.currentScanline = 10
+	for i := range ppu.oamData {
+		ppu.oamData[i] = 0xFF
+	}
+
+	return ppu
+}
+
+func Test_evaluateSprites_clears_secondary_OAM(t *testing.T) {
+	ppu := anEvaluationPPU()
+	for i := range ppu.secondaryOAM {
+		ppu.secondaryOAM[i] = byte(i)
+	}
+
+	evaluateSprites(ppu)
+
+	for i, value := range ppu.secondaryOAM {
+		assert.Equal(t, byte(0xFF), value, "secondary OAM byte %d", i)
+	}
+	assert.Equal(t, byte(0), ppu.spriteScanlineCount)
+}
+
+func Test_evaluateSprites_copies_sprites_in_range_in_OAM_order(t *testing.T) {
+	ppu := anEvaluationPPU()
+	setSprite(ppu, 0, 50, 0x01, 0x00, 10)
+	setSprite(ppu, 3, 5, 0x02, 0x41, 20)
+	setSprite(ppu, 7, 10, 0x03, 0x82, 30)
+	setSprite(ppu, 9, 2, 0x04, 0x00, 40)
+
+	evaluateSprites(ppu)
+
+	assert.Equal(t, byte(2), ppu.spriteScanlineCount)
+	assert.False(t, ppu.spriteZeroInScanline)
+	assert.Equal(t, objectAttributeEntry{y: 5, tileId: 0x02, attributes: 0x41, x: 20}, ppu.oamDataScanline[0])
+	assert.Equal(t, objectAttributeEntry{y: 10, tileId: 0x03, attributes: 0x82, x: 30}, ppu.oamDataScanline[1])
+	assert.Equal(t, objectAttributeEntry{y: 0xFF, tileId: 0xFF, attributes: 0xFF, x: 0xFF}, ppu.oamDataScanline[2])
+	assert.Equal(t, byte(0), ppu.PpuStatus.SpriteOverflow)
+}
+
+func Test_evaluateSprites_flags_sprite_zero(t *testing.T) {
+	ppu := anEvaluationPPU()
+	setSprite(ppu, 0, 8, 0x01, 0x00, 10)
+
+	evaluateSprites(ppu)
+
+	assert.Equal(t, byte(1), ppu.spriteScanlineCount)
+	assert.True(t, ppu.spriteZeroInScanline)
+}
+
+func Test_evaluateSprites_overflow(t *testing.T) {
+	cases := []struct {
+		name             string
+		sprites          [][4]byte
+		expectedOverflow byte
+	}{
+		{
+			"8 sprites do not overflow",
+			[][4]byte{{10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}},
+			0,
+		},
+		{
+			"9th sprite in range overflows",
+			[][4]byte{{10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}},
+			1,
+		},
+		{
+			"diagonal read misses a sprite in range",
+			[][4]byte{{10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {200, 0, 0, 0}, {10, 0x50, 0, 0}},
+			0,
+		},
+		{
+			"diagonal read takes tile id as Y",
+			[][4]byte{{10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {10, 0, 0, 0}, {200, 0, 0, 0}, {200, 10, 0, 0}},
+			1,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ppu := anEvaluationPPU()
+			for i, sprite := range tt.sprites {
+				setSprite(ppu, i, sprite[0], sprite[1], sprite[2], sprite[3])
+			}
+
+			evaluateSprites(ppu)
+
+			assert.Equal(t, byte(8), ppu.spriteScanlineCount)
+			assert.Equal(t, tt.expectedOverflow, ppu.PpuStatus.SpriteOverflow)
+		})
+	}
+}
+
+func TestPPU_sprite_overflow_is_cleared_on_pre_render_scanline(t *testing.T) {
+	ppu := CreatePPU(gamePak.NewDummyGamePak(createCHRRom()), false, "")
+	ppu.warmup = true
+	ppu.PpuStatus.SpriteOverflow = 1
+	ppu.currentScanline = 261
+	ppu.renderCycle = 0
+
+	ppu.Tick()
+	ppu.Tick()
+
+	assert.Equal(t, byte(0), ppu.PpuStatus.SpriteOverflow)
+}
+
+// TestPPU_sprite_0_hit_along_a_frame renders whole frames over a solid background, and finds
+// where sprite 0 hit is first reported, mirroring the cases of blargg's ppu_sprite_hit.
+func TestPPU_sprite_0_hit_along_a_frame(t *testing.T) {
+	const (
+		solidTile      = 1 // every pixel opaque
+		leftColumnTile = 2 // only the leftmost column is opaque
+		bottomRowTile  = 3 // only the bottom row is opaque
+		tallSprite     = 4 // 8x16: transparent tile 4 on top, solid tile 5 below
+	)
+	tests := []struct {
+		name       string
+		mask       byte
+		spriteSize byte
+		y          byte
+		tile       byte
+		attributes byte
+		x          byte
+		hit        bool
+		scanline   Scanline
+		hitX       uint16
+	}{
+		{"sprites are drawn one scanline below their Y", 0b00011110, 0, 20, solidTile, 0, 40, true, 21, 40},
+		{"first opaque pixel hits", 0b00011110, 0, 20, leftColumnTile, 0, 40, true, 21, 40},
+		{"horizontal flip", 0b00011110, 0, 20, leftColumnTile, 0x40, 40, true, 21, 47},
+		{"vertical flip", 0b00011110, 0, 20, bottomRowTile, 0x80, 40, true, 21, 40},
+		{"no vertical flip", 0b00011110, 0, 20, bottomRowTile, 0, 40, true, 28, 40},
+		{"behind background", 0b00011110, 0, 20, solidTile, 0x20, 40, true, 21, 40},
+		{"left clipping", 0b00011000, 0, 20, solidTile, 0, 4, true, 21, 8},
+		{"left clipping hides the whole sprite", 0b00011000, 0, 20, solidTile, 0, 0, false, 0, 0},
+		{"x=255 never hits", 0b00011110, 0, 20, solidTile, 0, 255, false, 0, 0},
+		{"x=254 hits", 0b00011110, 0, 20, solidTile, 0, 254, true, 21, 254},
+		{"last visible scanline", 0b00011110, 0, 238, solidTile, 0, 40, true, 239, 40},
+		{"below the screen", 0b00011110, 0, 239, solidTile, 0, 40, false, 0, 0},
+		{"8x16 bottom tile", 0b00011110, 1, 20, tallSprite, 0, 40, true, 29, 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chrROM := gamePak.NewEmptyCHRROM()
+			for row := 0; row < 8; row++ {
+				chrROM[solidTile*16+row] = 0xFF
+				chrROM[leftColumnTile*16+row] = 0x80
+				chrROM[(tallSprite+1)*16+row] = 0xFF
+			}
+			chrROM[bottomRowTile*16+7] = 0xFF
+
+			ppu := CreatePPU(gamePak.NewDummyGamePak(chrROM), false, "")
+			ppu.warmup = true
+			for address := types.Address(0x2000); address < 0x23C0; address++ {
+				ppu.Write(address, solidTile)
+			}
+			ppu.PpuMask.write(tt.mask)
+			ppu.PpuControl.SpriteSize = tt.spriteSize
+			copy(ppu.oamData[:4], []byte{tt.y, tt.tile, tt.attributes, tt.x})
+
+			// First frame gets v from t on prerender scanline, second one is checked
+			for !ppu.FrameComplete() {
+				ppu.Tick()
+			}
+			hit := false
+			for !ppu.FrameComplete() && !hit {
+				scanline, cycle := ppu.currentScanline, ppu.renderCycle
+				ppu.Tick()
+				if ppu.PpuStatus.Sprite0Hit == 1 {
+					hit = true
+					if tt.hit {
+						assert.Equal(t, tt.scanline, scanline, "scanline")
+						assert.Equal(t, tt.hitX, cycle-1, "x")
+					}
+				}
+			}
+
+			assert.Equal(t, tt.hit, hit, "sprite 0 hit")
+		})
+	}
 }
@@ -0,0 +1,51 @@
+package ppu
+
+import "github.com/raulferras/nes-golang/src/nes/savestate"
+
+// SyncState saves or loads registers, memories and rendering pipeline.
+// The frame being drawn is not part of it, it is complete again after a frame.
+func (ppu *P2c02) SyncState(state *savestate.Stream) {
+	state.Sync(&ppu.PpuControl, &ppu.PpuStatus, &ppu.PpuMask)
+	ppu.vRam.syncState(state)
+	ppu.tRam.syncState(state)
+	state.Sync(
+		&ppu.fineX, &ppu.readBuffer, &ppu.oamAddr, &ppu.ioLatch, &ppu.ioLatchRefreshed,
+		&ppu.nameTables, &ppu.paletteTable, &ppu.oamData,
+	)
+
+	state.Sync(
+		&ppu.bgNextTileId, &ppu.bgNextAttribute, &ppu.bgNextLowTile, &ppu.bgNextHighTile,
+		&ppu.bgShifterTileLow, &ppu.bgShifterTileHigh, &ppu.bgShifterAttributeLow, &ppu.bgShifterAttributeHigh,
+	)
+
+	state.Sync(&ppu.secondaryOAM)
+	ppu.spriteEvaluation.syncState(state)
+	for i := range ppu.oamDataScanline {
+		ppu.oamDataScanline[i].syncState(state)
+	}
+	state.Sync(&ppu.spriteScanlineCount, &ppu.spriteZeroInScanline, &ppu.spShifterPatternLow, &ppu.spShifterPatternHigh)
+
+	state.Sync(
+		&ppu.cycle, &ppu.clock, &ppu.warmup,
+		&ppu.renderCycle, &ppu.currentScanline, &ppu.evenFrame, &ppu.frame, &ppu.frameComplete,
+		&ppu.vblankSuppressed, &ppu.nameTableChanged,
+	)
+}
+
+func (register *LoopyRegister) syncState(state *savestate.Stream) {
+	state.Sync(
+		&register._coarseX, &register._coarseY, &register._nameTableX, &register._nameTableY, &register._fineY,
+		&register.latch,
+	)
+}
+
+func (evaluation *spriteEvaluation) syncState(state *savestate.Stream) {
+	state.Sync(
+		&evaluation.n, &evaluation.m, &evaluation.secondaryIndex, &evaluation.latch,
+		&evaluation.spriteZero, &evaluation.done,
+	)
+}
+
+func (entry *objectAttributeEntry) syncState(state *savestate.Stream) {
+	state.Sync(&entry.y, &entry.tileId, &entry.attributes, &entry.x)
+}
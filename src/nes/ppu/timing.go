@@ -0,0 +1,31 @@
+package ppu
+
+// Timing holds the PPU parameters that change between console regions
+type Timing struct {
+	Scanlines            Scanline // Scanlines per frame, pre-render scanline included
+	VBlankScanline       Scanline // Scanline where VBlank flag is raised, on its second cycle
+	SkipOddFrameCycle    bool     // Odd frames skip a cycle when rendering is enabled
+	SwapRedGreenEmphasis bool     // PPUMASK bits 5 and 6 emphasize green and red instead of red and green
+}
+
+var NTSCTiming = Timing{
+	Scanlines:         PPU_SCANLINES + 1,
+	VBlankScanline:    VBLANK_START_SCANLINE,
+	SkipOddFrameCycle: true,
+}
+
+var PALTiming = Timing{
+	Scanlines:            312,
+	VBlankScanline:       VBLANK_START_SCANLINE,
+	SwapRedGreenEmphasis: true,
+}
+
+// DendyTiming has as many scanlines as PAL, but VBlank starts 50 scanlines later, keeping NTSC VBlank length
+var DendyTiming = Timing{
+	Scanlines:      312,
+	VBlankScanline: 291,
+}
+
+func (timing Timing) preRenderScanline() Scanline {
+	return timing.Scanlines - 1
+}
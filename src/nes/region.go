@@ -0,0 +1,82 @@
+package nes
+
+import (
+	"fmt"
+	"github.com/raulferras/nes-golang/src/nes/apu"
+	"github.com/raulferras/nes-golang/src/nes/gamePak"
+	"github.com/raulferras/nes-golang/src/nes/ppu"
+	"strings"
+)
+
+// Region groups the timings of a console model.
+// CPU and PPU are clocked dividing the same master clock, by CPUDivider and PPUDivider.
+type Region struct {
+	Name       string
+	CPUDivider uint64
+	PPUDivider uint64
+	FrameRate  float64 // Frames per second
+	PPU        ppu.Timing
+	APU        apu.Timing
+}
+
+// NTSC consoles run 3 PPU cycles per CPU cycle, 262 scanlines per frame
+var NTSC = Region{
+	Name:       "NTSC",
+	CPUDivider: 12,
+	PPUDivider: 4,
+	FrameRate:  60.0988,
+	PPU:        ppu.NTSCTiming,
+	APU:        apu.NTSCTiming,
+}
+
+// PAL consoles run 3.2 PPU cycles per CPU cycle, 312 scanlines per frame
+var PAL = Region{
+	Name:       "PAL",
+	CPUDivider: 16,
+	PPUDivider: 5,
+	FrameRate:  50.0070,
+	PPU:        ppu.PALTiming,
+	APU:        apu.PALTiming,
+}
+
+// Dendy famiclones run 3 PPU cycles per CPU cycle, 312 scanlines per frame
+var Dendy = Region{
+	Name:       "Dendy",
+	CPUDivider: 15,
+	PPUDivider: 5,
+	FrameRate:  50.0070,
+	PPU:        ppu.DendyTiming,
+	APU:        apu.DendyTiming,
+}
+
+// UnknownRegionError is returned when a region name is not one of ntsc, pal or dendy
+type UnknownRegionError struct {
+	Name string
+}
+
+func (err UnknownRegionError) Error() string {
+	return fmt.Sprintf("unknown region %q, expected ntsc, pal or dendy", err.Name)
+}
+
+// RegionFromTvSystem picks the region a cartridge header asks for. Multi region games run as NTSC.
+func RegionFromTvSystem(tvSystem byte) Region {
+	switch tvSystem {
+	case gamePak.TvSystemPAL:
+		return PAL
+	case gamePak.TvSystemDendy:
+		return Dendy
+	}
+
+	return NTSC
+}
+
+// RegionByName finds a region by its case insensitive name
+func RegionByName(name string) (Region, error) {
+	for _, region := range []Region{NTSC, PAL, Dendy} {
+		if strings.EqualFold(region.Name, name) {
+			return region, nil
+		}
+	}
+
+	return Region{}, UnknownRegionError{Name: name}
+}
@@ -0,0 +1,51 @@
+package nes
+
+import (
+	"github.com/raulferras/nes-golang/src/nes/gamePak"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRegionFromTvSystem(t *testing.T) {
+	assert.Equal(t, NTSC, RegionFromTvSystem(gamePak.TvSystemNTSC))
+	assert.Equal(t, PAL, RegionFromTvSystem(gamePak.TvSystemPAL))
+	assert.Equal(t, NTSC, RegionFromTvSystem(gamePak.TvSystemMultiRegion))
+	assert.Equal(t, Dendy, RegionFromTvSystem(gamePak.TvSystemDendy))
+}
+
+func TestRegionByName(t *testing.T) {
+	region, err := RegionByName("pal")
+	assert.NoError(t, err)
+	assert.Equal(t, PAL, region)
+
+	_, err = RegionByName("secam")
+	assert.Equal(t, UnknownRegionError{Name: "secam"}, err)
+}
+
+func TestNes_CPU_to_PPU_clock_ratio_depends_on_region(t *testing.T) {
+	cases := []struct {
+		region    Region
+		ppuCycles int
+		cpuCycles int
+	}{
+		{NTSC, 300, 100},
+		{PAL, 320, 100},
+		{Dendy, 300, 100},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.region.Name, func(t *testing.T) {
+			nes := aNopNes()
+			nes.SetRegion(tt.region)
+
+			cpuCycles := 0
+			for i := 0; i < tt.ppuCycles; i++ {
+				if _, cpuExecuted := nes.Tick(); cpuExecuted {
+					cpuCycles++
+				}
+			}
+
+			assert.Equal(t, tt.cpuCycles, cpuCycles)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+// Package rewind keeps a history of console states to play emulation backwards.
+//
+// A snapshot is taken every few frames into a ring buffer of fixed size, evicting the oldest ones.
+// Most snapshots are deltas: the XOR of the state against the last keyframe, a full state taken every few snapshots.
+// Consecutive states differ in a few bytes, so deltas are mostly zeros and compress down to a few hundred bytes.
+package rewind
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"io/ioutil"
+)
+
+// Console is the emulator whose state is captured, usually nes.Nes
+type Console interface {
+	SaveState(writer io.Writer) error
+	LoadState(reader io.Reader) error
+}
+
+type Options struct {
+	Interval         int // Frames between snapshots
+	KeyframeInterval int // Snapshots between keyframes
+	MaxSnapshots     int // Snapshots kept, must be greater than KeyframeInterval
+	MemoryLimit      int // Bytes of compressed snapshots kept
+}
+
+// DefaultOptions keep a minute of history at 60 frames per second, one snapshot per frame
+func DefaultOptions() Options {
+	return Options{
+		Interval:         1,
+		KeyframeInterval: 60,
+		MaxSnapshots:     60 * 60,
+		MemoryLimit:      32 << 20,
+	}
+}
+
+type snapshot struct {
+	keyframe bool
+	data     []byte // Compressed state, or compressed XOR of the state against its keyframe
+}
+
+type Buffer struct {
+	options Options
+
+	snapshots []snapshot // Ring buffer, from oldest to newest starting at first
+	first     int
+	count     int
+	size      int // Bytes held by snapshots
+
+	frames   int    // Frames since last snapshot
+	present  bool   // Newest snapshot holds the current state, no frame ran since it was taken
+	keyframe []byte // State of newest keyframe, new deltas are encoded against it. nil forces next snapshot to be a keyframe
+	deltas   int    // Deltas taken since newest keyframe
+
+	state      bytes.Buffer
+	compressed bytes.Buffer
+	compressor *flate.Writer
+}
+
+func NewBuffer(options Options) *Buffer {
+	if options.Interval < 1 {
+		options.Interval = 1
+	}
+	if options.KeyframeInterval < 1 {
+		options.KeyframeInterval = 1
+	}
+	// Evicting a keyframe evicts its deltas, there must be room for another keyframe
+	if options.MaxSnapshots <= options.KeyframeInterval {
+		options.MaxSnapshots = options.KeyframeInterval + 1
+	}
+
+	compressor, _ := flate.NewWriter(nil, flate.BestSpeed)
+
+	return &Buffer{
+		options:    options,
+		snapshots:  make([]snapshot, options.MaxSnapshots),
+		compressor: compressor,
+	}
+}
+
+// Snapshots returns how many snapshots are held
+func (buffer *Buffer) Snapshots() int {
+	return buffer.count
+}
+
+// Size returns the bytes held by snapshots
+func (buffer *Buffer) Size() int {
+	return buffer.size
+}
+
+// Capture must be called after each emulated frame. It takes a snapshot every Interval frames.
+func (buffer *Buffer) Capture(console Console) error {
+	buffer.frames++
+	buffer.present = false
+	if buffer.frames < buffer.options.Interval {
+		return nil
+	}
+	buffer.frames = 0
+
+	buffer.state.Reset()
+	if err := console.SaveState(&buffer.state); err != nil {
+		return err
+	}
+	state := buffer.state.Bytes()
+
+	isKeyframe := buffer.keyframe == nil ||
+		len(buffer.keyframe) != len(state) ||
+		buffer.deltas+1 >= buffer.options.KeyframeInterval
+	if isKeyframe {
+		buffer.keyframe = append(buffer.keyframe[:0], state...)
+		buffer.deltas = 0
+	} else {
+		xor(state, buffer.keyframe)
+		buffer.deltas++
+	}
+
+	data, err := buffer.compress(state)
+	if err != nil {
+		return err
+	}
+	buffer.push(snapshot{keyframe: isKeyframe, data: data})
+	buffer.present = true
+
+	return nil
+}
+
+// Rewind loads the newest snapshot and drops it, so each call goes further back in time.
+// A snapshot taken on the last frame is skipped, it holds the state the console is already in.
+// The oldest snapshot is kept, holding rewind stays there. It returns false when there is no snapshot.
+func (buffer *Buffer) Rewind(console Console) (bool, error) {
+	if buffer.count == 0 {
+		return false, nil
+	}
+	if buffer.present && buffer.count > 1 {
+		buffer.dropNewest()
+	}
+	buffer.present = false
+
+	newest := buffer.count - 1
+	keyframeIndex := newest
+	for !buffer.at(keyframeIndex).keyframe {
+		keyframeIndex--
+	}
+
+	keyframe, err := decompress(buffer.at(keyframeIndex).data)
+	if err != nil {
+		return false, err
+	}
+	state := keyframe
+	if keyframeIndex != newest {
+		state, err = decompress(buffer.at(newest).data)
+		if err != nil {
+			return false, err
+		}
+		xor(state, keyframe)
+	}
+
+	if err := console.LoadState(bytes.NewReader(state)); err != nil {
+		return false, err
+	}
+
+	if buffer.count > 1 {
+		buffer.dropNewest()
+	}
+	// Next snapshots go on from the loaded one
+	buffer.frames = 0
+	if buffer.count-1 >= keyframeIndex {
+		buffer.keyframe = keyframe
+		buffer.deltas = buffer.count - 1 - keyframeIndex
+	} else {
+		buffer.keyframe = nil
+	}
+
+	return true, nil
+}
+
+func (buffer *Buffer) compress(state []byte) ([]byte, error) {
+	buffer.compressed.Reset()
+	buffer.compressor.Reset(&buffer.compressed)
+	if _, err := buffer.compressor.Write(state); err != nil {
+		return nil, err
+	}
+	if err := buffer.compressor.Close(); err != nil {
+		return nil, err
+	}
+
+	return append([]byte(nil), buffer.compressed.Bytes()...), nil
+}
+
+func decompress(data []byte) ([]byte, error) {
+	reader := flate.NewReader(bytes.NewReader(data))
+	defer reader.Close()
+
+	return ioutil.ReadAll(reader)
+}
+
+// xor applies a delta in place. Applying it twice gives the original back.
+func xor(state []byte, keyframe []byte) {
+	for i := range state {
+		state[i] ^= keyframe[i]
+	}
+}
+
+// at returns the snapshot at an index, 0 being the oldest
+func (buffer *Buffer) at(index int) *snapshot {
+	return &buffer.snapshots[(buffer.first+index)%len(buffer.snapshots)]
+}
+
+// push adds a snapshot, evicting the oldest ones when out of room.
+// The keyframe of the newest group is never evicted, as the snapshot being pushed may depend on it.
+func (buffer *Buffer) push(newSnapshot snapshot) {
+	for buffer.count == len(buffer.snapshots) || buffer.size+len(newSnapshot.data) > buffer.options.MemoryLimit {
+		if !buffer.evictOldestGroup(newSnapshot.keyframe) {
+			break
+		}
+	}
+
+	*buffer.at(buffer.count) = newSnapshot
+	buffer.count++
+	buffer.size += len(newSnapshot.data)
+}
+
+// evictOldestGroup drops the oldest keyframe along with its deltas. Unless pushingKeyframe is set,
+// the newest group is kept. It returns false when nothing could be dropped.
+func (buffer *Buffer) evictOldestGroup(pushingKeyframe bool) bool {
+	groupLength := 1
+	for groupLength < buffer.count && !buffer.at(groupLength).keyframe {
+		groupLength++
+	}
+	if buffer.count == 0 || (groupLength == buffer.count && !pushingKeyframe) {
+		return false
+	}
+
+	for i := 0; i < groupLength; i++ {
+		evicted := buffer.at(0)
+		buffer.size -= len(evicted.data)
+		*evicted = snapshot{}
+		buffer.first = (buffer.first + 1) % len(buffer.snapshots)
+		buffer.count--
+	}
+
+	return true
+}
+
+func (buffer *Buffer) dropNewest() {
+	newest := buffer.at(buffer.count - 1)
+	buffer.size -= len(newest.data)
+	*newest = snapshot{}
+	buffer.count--
+}
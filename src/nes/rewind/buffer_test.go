@@ -0,0 +1,133 @@
+package rewind
+
+import (
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+// fakeConsole holds a frame counter along some memory, as a console state does
+type fakeConsole struct {
+	frame  uint32
+	memory [1024]byte
+}
+
+func (console *fakeConsole) SaveState(writer io.Writer) error {
+	binary.Write(writer, binary.LittleEndian, console.frame)
+	_, err := writer.Write(console.memory[:])
+	return err
+}
+
+func (console *fakeConsole) LoadState(reader io.Reader) error {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	console.frame = binary.LittleEndian.Uint32(data)
+	copy(console.memory[:], data[4:])
+	return nil
+}
+
+func (console *fakeConsole) runFrame() {
+	console.frame++
+	console.memory[console.frame%1024]++
+}
+
+func runAndCapture(t *testing.T, buffer *Buffer, console *fakeConsole, frames int) {
+	for i := 0; i < frames; i++ {
+		console.runFrame()
+		assert.NoError(t, buffer.Capture(console))
+	}
+}
+
+func rewoundFrame(t *testing.T, buffer *Buffer, console *fakeConsole) uint32 {
+	rewound, err := buffer.Rewind(console)
+	assert.NoError(t, err)
+	assert.True(t, rewound)
+
+	return console.frame
+}
+
+func TestBuffer_rewinds_snapshots_from_newest_to_oldest(t *testing.T) {
+	buffer := NewBuffer(Options{Interval: 2, KeyframeInterval: 4, MaxSnapshots: 100, MemoryLimit: 1 << 20})
+	console := &fakeConsole{}
+	runAndCapture(t, buffer, console, 20)
+
+	assert.Equal(t, 10, buffer.Snapshots())
+	// Snapshot of frame 20 is the current state
+	for frame := uint32(18); frame >= 2; frame -= 2 {
+		assert.Equal(t, frame, rewoundFrame(t, buffer, console))
+	}
+	assert.Equal(t, uint32(2), rewoundFrame(t, buffer, console), "rewind should stay at oldest snapshot")
+}
+
+func TestBuffer_skips_current_state_only_right_after_it_was_captured(t *testing.T) {
+	buffer := NewBuffer(Options{Interval: 2, KeyframeInterval: 4, MaxSnapshots: 100, MemoryLimit: 1 << 20})
+	console := &fakeConsole{}
+	runAndCapture(t, buffer, console, 21)
+
+	assert.Equal(t, uint32(20), rewoundFrame(t, buffer, console))
+}
+
+func TestBuffer_captures_go_on_from_rewound_snapshot(t *testing.T) {
+	buffer := NewBuffer(Options{Interval: 1, KeyframeInterval: 4, MaxSnapshots: 100, MemoryLimit: 1 << 20})
+	console := &fakeConsole{}
+	runAndCapture(t, buffer, console, 19)
+	expected := *console
+	runAndCapture(t, buffer, console, 1)
+
+	for console.frame != 9 {
+		rewoundFrame(t, buffer, console)
+	}
+	runAndCapture(t, buffer, console, 11)
+	*console = fakeConsole{}
+
+	assert.Equal(t, uint32(19), rewoundFrame(t, buffer, console))
+	assert.Equal(t, expected, *console)
+	assert.Equal(t, uint32(18), rewoundFrame(t, buffer, console))
+}
+
+func TestBuffer_without_snapshots_does_not_rewind(t *testing.T) {
+	buffer := NewBuffer(DefaultOptions())
+
+	rewound, err := buffer.Rewind(&fakeConsole{})
+
+	assert.NoError(t, err)
+	assert.False(t, rewound)
+}
+
+func TestBuffer_evicts_oldest_keyframe_with_its_deltas(t *testing.T) {
+	buffer := NewBuffer(Options{Interval: 1, KeyframeInterval: 5, MaxSnapshots: 12, MemoryLimit: 1 << 20})
+	console := &fakeConsole{}
+	runAndCapture(t, buffer, console, 13)
+
+	assert.Equal(t, 8, buffer.Snapshots(), "frames 1 to 5 should have been evicted together")
+	for i := 0; i < 20; i++ {
+		buffer.Rewind(console)
+	}
+	assert.Equal(t, uint32(6), console.frame)
+}
+
+func TestBuffer_keeps_memory_under_limit(t *testing.T) {
+	buffer := NewBuffer(Options{Interval: 1, KeyframeInterval: 10, MaxSnapshots: 1000, MemoryLimit: 2000})
+	console := &fakeConsole{}
+	runAndCapture(t, buffer, console, 500)
+
+	assert.True(t, buffer.Size() <= 2000)
+	assert.True(t, buffer.Snapshots() >= 10)
+}
+
+func TestBuffer_deltas_are_smaller_than_keyframes(t *testing.T) {
+	buffer := NewBuffer(Options{Interval: 1, KeyframeInterval: 10, MaxSnapshots: 100, MemoryLimit: 1 << 20})
+	console := &fakeConsole{}
+	for i := range console.memory {
+		console.memory[i] = byte(i * 7)
+	}
+	runAndCapture(t, buffer, console, 2)
+
+	assert.True(t, buffer.at(0).keyframe)
+	assert.False(t, buffer.at(1).keyframe)
+	assert.True(t, len(buffer.at(1).data) < len(buffer.at(0).data)/4)
+}
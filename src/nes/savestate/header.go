@@ -0,0 +1,76 @@
+package savestate
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Version of the state layout. It must be increased whenever a SyncState method changes,
+// states of other versions are refused instead of being loaded into the wrong fields.
+const Version uint16 = 2
+
+var magic = [4]byte{'N', 'E', 'S', 'S'}
+
+// Header goes first in a state file
+//
+//	Magic:    4 bytes, "NESS"
+//	Version:  2 bytes
+//	ROM hash: 20 bytes, SHA-1 of PRG and CHR ROM, see GamePak.ROMHash
+type Header struct {
+	Magic   [4]byte
+	Version uint16
+	ROMHash [sha1.Size]byte
+}
+
+var ErrBadMagic = errors.New("not a save state, bad magic number")
+
+type VersionError struct {
+	Version uint16
+}
+
+func (err VersionError) Error() string {
+	return fmt.Sprintf("save state version %d is not supported, expected version %d", err.Version, Version)
+}
+
+type ROMMismatchError struct {
+	Expected [sha1.Size]byte
+	Actual   [sha1.Size]byte
+}
+
+func (err ROMMismatchError) Error() string {
+	return fmt.Sprintf("save state belongs to rom %x, loaded rom is %x", err.Actual, err.Expected)
+}
+
+// NewSaverWithHeader writes the header of a state for the rom, and returns the stream to save the state into
+func NewSaverWithHeader(writer io.Writer, romHash [sha1.Size]byte) *Stream {
+	stream := NewSaver(writer)
+	header := Header{Magic: magic, Version: Version, ROMHash: romHash}
+	stream.Sync(&header)
+
+	return stream
+}
+
+// NewLoaderWithHeader reads the header of a state, and returns the stream to load the state from.
+// It fails when the state was not saved by this version, or belongs to another rom.
+func NewLoaderWithHeader(reader io.Reader, romHash [sha1.Size]byte) (*Stream, error) {
+	stream := NewLoader(reader)
+	header := Header{}
+	stream.Sync(&header)
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+
+	if header.Magic != magic {
+		return nil, ErrBadMagic
+	}
+	if header.Version != Version {
+		return nil, VersionError{Version: header.Version}
+	}
+	if header.ROMHash != romHash {
+		return nil, ROMMismatchError{Expected: romHash, Actual: header.ROMHash}
+	}
+
+	return stream, nil
+}
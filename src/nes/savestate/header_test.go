@@ -0,0 +1,47 @@
+package savestate
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewLoaderWithHeader(t *testing.T) {
+	romHash := sha1.Sum([]byte("rom"))
+	otherHash := sha1.Sum([]byte("other rom"))
+
+	tests := []struct {
+		name          string
+		header        Header
+		expectedError error
+	}{
+		{"accepts state of the rom", Header{magic, Version, romHash}, nil},
+		{"refuses bad magic", Header{[4]byte{'N', 'E', 'S', 0x1A}, Version, romHash}, ErrBadMagic},
+		{"refuses other versions", Header{magic, Version + 1, romHash}, VersionError{Version + 1}},
+		{"refuses states of other roms", Header{magic, Version, otherHash}, ROMMismatchError{Expected: romHash, Actual: otherHash}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := bytes.Buffer{}
+			NewSaver(&buffer).Sync(&tt.header)
+
+			stream, err := NewLoaderWithHeader(&buffer, romHash)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedError == nil, stream != nil)
+		})
+	}
+}
+
+func TestNewSaverWithHeader_writes_header_first(t *testing.T) {
+	romHash := sha1.Sum([]byte("rom"))
+	buffer := bytes.Buffer{}
+	NewSaverWithHeader(&buffer, romHash)
+
+	_, err := NewLoaderWithHeader(&buffer, romHash)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, buffer.Len())
+}
@@ -0,0 +1,75 @@
+// Package savestate serializes the state of the console components into a binary stream.
+//
+// Each component describes its state once, in a SyncState method listing its fields in order.
+// The same method saves or loads them, depending on the direction of the stream, so both can not drift apart.
+// Values are stored little endian, without padding.
+package savestate
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Stream saves state into a writer, or loads it from a reader.
+// The first error is kept and further syncs are ignored, so components do not need to check errors on each field.
+type Stream struct {
+	writer io.Writer
+	reader io.Reader
+	err    error
+}
+
+// LengthError is returned when a variable sized memory, like PRG RAM, does not match the size found in the state
+type LengthError struct {
+	Expected int
+	Actual   int
+}
+
+func (err LengthError) Error() string {
+	return fmt.Sprintf("state holds %d bytes of memory, %d were expected", err.Actual, err.Expected)
+}
+
+func NewSaver(writer io.Writer) *Stream {
+	return &Stream{writer: writer}
+}
+
+func NewLoader(reader io.Reader) *Stream {
+	return &Stream{reader: reader}
+}
+
+// Loading tells if the stream loads state, instead of saving it
+func (stream *Stream) Loading() bool {
+	return stream.reader != nil
+}
+
+// Sync saves or loads values. Each one must be a pointer to fixed size data: numbers, bools,
+// arrays of them, or structs with exported fixed size fields only. Byte slices are synced in place.
+func (stream *Stream) Sync(values ...interface{}) {
+	for _, value := range values {
+		if stream.err != nil {
+			return
+		}
+		if stream.Loading() {
+			stream.err = binary.Read(stream.reader, binary.LittleEndian, value)
+		} else {
+			stream.err = binary.Write(stream.writer, binary.LittleEndian, value)
+		}
+	}
+}
+
+// SyncMemory saves or loads a memory whose size depends on the cartridge, like PRG RAM or CHR RAM.
+// Its size is stored along, and loading fails when it does not match.
+func (stream *Stream) SyncMemory(memory []byte) {
+	size := uint32(len(memory))
+	stream.Sync(&size)
+	if stream.err == nil && int(size) != len(memory) {
+		stream.err = LengthError{Expected: len(memory), Actual: int(size)}
+		return
+	}
+	stream.Sync(memory)
+}
+
+// Err returns the first error found along the stream
+func (stream *Stream) Err() error {
+	return stream.err
+}
@@ -0,0 +1,55 @@
+package savestate
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type syncedValues struct {
+	flag    bool
+	counter uint16
+	page    [4]byte
+	memory  []byte
+}
+
+func (values *syncedValues) syncState(state *Stream) {
+	state.Sync(&values.flag, &values.counter, &values.page)
+	state.SyncMemory(values.memory)
+}
+
+func TestStream_loads_what_was_saved(t *testing.T) {
+	saved := syncedValues{true, 0x1234, [4]byte{1, 2, 3, 4}, []byte{5, 6, 7}}
+	buffer := bytes.Buffer{}
+	saver := NewSaver(&buffer)
+	saved.syncState(saver)
+	assert.NoError(t, saver.Err())
+
+	loaded := syncedValues{memory: make([]byte, 3)}
+	loader := NewLoader(&buffer)
+	loaded.syncState(loader)
+
+	assert.NoError(t, loader.Err())
+	assert.True(t, loader.Loading())
+	assert.Equal(t, saved, loaded)
+}
+
+func TestStream_SyncMemory_fails_when_size_differs(t *testing.T) {
+	buffer := bytes.Buffer{}
+	NewSaver(&buffer).SyncMemory(make([]byte, 0x2000))
+
+	loader := NewLoader(&buffer)
+	loader.SyncMemory(make([]byte, 0x800))
+
+	assert.Equal(t, LengthError{Expected: 0x800, Actual: 0x2000}, loader.Err())
+}
+
+func TestStream_keeps_first_error(t *testing.T) {
+	loader := NewLoader(bytes.NewReader([]byte{0x01}))
+	value := uint16(0)
+	flag := false
+	loader.Sync(&value, &flag)
+
+	assert.Error(t, loader.Err())
+	assert.False(t, flag, "syncs after an error should be ignored")
+}
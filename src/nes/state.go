@@ -0,0 +1,90 @@
+package nes
+
+import (
+	"bytes"
+	"github.com/raulferras/nes-golang/src/nes/savestate"
+	"io"
+	"io/ioutil"
+)
+
+// SaveState writes the whole console state: CPU, RAM, DMA, PPU, APU and cartridge.
+// See savestate.Header for the layout of the file.
+func (nes *Nes) SaveState(writer io.Writer) error {
+	state := savestate.NewSaverWithHeader(writer, nes.cartridge.ROMHash())
+	nes.syncState(state)
+
+	return state.Err()
+}
+
+// LoadState restores a state written by SaveState. States of another rom, or another state version, are refused.
+// The state is read whole before loading, and the console is restored to where it was when a truncated
+// or corrupt state fails halfway.
+func (nes *Nes) LoadState(reader io.Reader) error {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	current := bytes.Buffer{}
+	if err := nes.SaveState(&current); err != nil {
+		return err
+	}
+	if err := nes.loadState(bytes.NewReader(data)); err != nil {
+		if restoreErr := nes.loadState(&current); restoreErr != nil {
+			return restoreErr
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (nes *Nes) loadState(reader io.Reader) error {
+	state, err := savestate.NewLoaderWithHeader(reader, nes.cartridge.ROMHash())
+	if err != nil {
+		return err
+	}
+	nes.syncState(state)
+	if err := state.Err(); err != nil {
+		return err
+	}
+
+	nes.batteryFlushFrame = nes.ppu.FrameNumber()
+
+	return nil
+}
+
+func (nes *Nes) syncState(state *savestate.Stream) {
+	state.Sync(&nes.systemClockCounter, &nes.cpuClockCounter)
+	nes.Cpu.syncState(state)
+	nes.bus.syncState(state)
+	nes.dma.syncState(state)
+	nes.ppu.SyncState(state)
+	nes.apu.SyncState(state)
+	nes.cartridge.SyncState(state)
+}
+
+func (cpu *Cpu6502) syncState(state *savestate.Stream) {
+	state.Sync(&cpu.registers, &cpu.opCyclesLeft, &cpu.cycle)
+	state.Sync(
+		&cpu.opcode, &cpu.instructionCycle, &cpu.addressReady, &cpu.operationCycle,
+		&cpu.baseAddress, &cpu.operandAddress, &cpu.dataLatch, &cpu.operandLatched,
+	)
+	state.Sync(
+		&cpu.nmiLine, &cpu.nmiPending, &cpu.irqLine, &cpu.interruptPollCycle,
+		&cpu.nmiRequested, &cpu.irqRequested, &cpu.interruptVector, &cpu.hardwareInterrupt,
+	)
+}
+
+// Only the 2KB of real RAM are synced, the rest of the array is never used
+func (cm *CPUMemory) syncState(state *savestate.Stream) {
+	state.Sync(cm.ram[:RAM_LAST_REAL_ADDRESS+1], &cm.controllers, &cm.controllersState)
+}
+
+func (d *dma) syncState(state *savestate.Stream) {
+	state.Sync(
+		&d.halted, &d.haltCycle, &d.haltAddress,
+		&d.oamActive, &d.oamPage, &d.oamCycles, &d.oamValue,
+		&d.dmcActive, &d.dmcNeedsHalt, &d.dmcNeedsDummy,
+	)
+}
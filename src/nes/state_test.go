@@ -0,0 +1,87 @@
+package nes
+
+import (
+	"bytes"
+	gamePak2 "github.com/raulferras/nes-golang/src/nes/gamePak"
+	"github.com/raulferras/nes-golang/src/nes/savestate"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func aRunningNes(t *testing.T, romPath string) *Nes {
+	gamePak, err := gamePak2.CreateGamePakFromROMFile(romPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nes := CreateNes(&gamePak, &Debugger{})
+	nes.Start()
+
+	return nes
+}
+
+func runFrames(nes *Nes, frames int) {
+	for i := 0; i < frames; i++ {
+		nes.TickTillFrameComplete()
+	}
+}
+
+func TestNes_LoadState_resumes_emulation_where_it_was_saved(t *testing.T) {
+	nes := aRunningNes(t, "./../../assets/roms/tests/cpu_dummy_reads.nes")
+	runFrames(nes, 20)
+	// Save in the middle of a scanline, with the rendering pipeline busy
+	for i := 0; i < 12345; i++ {
+		nes.Tick()
+	}
+
+	state := bytes.Buffer{}
+	assert.NoError(t, nes.SaveState(&state))
+
+	runFrames(nes, 30)
+	expectedFrame := append([]byte(nil), nes.Frame().Pix...)
+	expectedRAM := nes.bus.ram
+	expectedRegisters := nes.Cpu.registers
+	expectedCycles := nes.cpuClockCounter
+	expectedPPUFrame := nes.ppu.FrameNumber()
+
+	assert.NoError(t, nes.LoadState(&state))
+	runFrames(nes, 30)
+
+	assert.Equal(t, expectedCycles, nes.cpuClockCounter)
+	assert.Equal(t, expectedPPUFrame, nes.ppu.FrameNumber())
+	assert.Equal(t, expectedRegisters, nes.Cpu.registers)
+	assert.Equal(t, expectedRAM, nes.bus.ram)
+	assert.Equal(t, expectedFrame, nes.Frame().Pix)
+}
+
+func TestNes_LoadState_refuses_states_of_other_roms(t *testing.T) {
+	nes := aRunningNes(t, "./../../assets/roms/tests/cpu_dummy_reads.nes")
+	state := bytes.Buffer{}
+	assert.NoError(t, nes.SaveState(&state))
+
+	other := aRunningNes(t, "./../../assets/roms/tests/nestest/nestest.nes")
+	err := other.LoadState(&state)
+
+	assert.IsType(t, savestate.ROMMismatchError{}, err)
+}
+
+func TestNes_LoadState_keeps_console_untouched_when_state_is_truncated(t *testing.T) {
+	nes := aRunningNes(t, "./../../assets/roms/tests/cpu_dummy_reads.nes")
+	runFrames(nes, 20)
+	state := bytes.Buffer{}
+	assert.NoError(t, nes.SaveState(&state))
+
+	runFrames(nes, 30)
+	expectedRAM := nes.bus.ram
+	expectedRegisters := nes.Cpu.registers
+	expectedCycles := nes.cpuClockCounter
+	expectedPPUFrame := nes.ppu.FrameNumber()
+
+	err := nes.LoadState(bytes.NewReader(state.Bytes()[:state.Len()/2]))
+
+	assert.Error(t, err)
+	assert.Equal(t, expectedCycles, nes.cpuClockCounter)
+	assert.Equal(t, expectedPPUFrame, nes.ppu.FrameNumber())
+	assert.Equal(t, expectedRegisters, nes.Cpu.registers)
+	assert.Equal(t, expectedRAM, nes.bus.ram)
+}